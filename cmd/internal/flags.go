@@ -55,8 +55,21 @@ func ServeFlags(flags *pflag.FlagSet, opts *ToolboxOptions) {
 	flags.StringVarP(&opts.Cfg.Address, "address", "a", "127.0.0.1", "Address of the interface the server will listen on.")
 	flags.IntVarP(&opts.Cfg.Port, "port", "p", 5000, "Port the server will listen on.")
 	flags.BoolVar(&opts.Cfg.Stdio, "stdio", false, "Listens via MCP STDIO instead of acting as a remote HTTP server.")
+	flags.StringVar(&opts.Cfg.StdioAuthFile, "stdio-auth-file", "", "File that maps header names to values (e.g. '<authService>_token: <token>') sent with every MCP STDIO request for auth services to verify. Read for every request.")
 	flags.BoolVar(&opts.Cfg.UI, "ui", false, "Launches the Toolbox UI web server.")
+	flags.BoolVar(&opts.Cfg.DisableDefaultToolsetAPI, "disable-default-toolset-api", false, "Disables the /api/toolset and /api/tool/{toolName} routes, which reach every tool through the default toolset. Tools are then only reachable through /api/toolset/{toolsetName}.")
 
 	flags.StringSliceVar(&opts.Cfg.AllowedOrigins, "allowed-origins", []string{"*"}, "Specifies a list of origins permitted to access this server. Defaults to '*'.")
 	flags.StringSliceVar(&opts.Cfg.AllowedHosts, "allowed-hosts", []string{"*"}, "Specifies a list of hosts permitted to access this server. Defaults to '*'.")
+	flags.IntVar(&opts.Cfg.PageSize, "page-size", 0, "Maximum number of items returned per page by MCP list methods and the toolset API. Defaults to 0, which disables pagination.")
+	flags.IntVar(&opts.Cfg.TaskWorkers, "task-workers", 10, "Maximum number of MCP tasks that run concurrently. Additional tasks wait for a worker.")
+	flags.StringVar(&opts.Cfg.OAuthIssuer, "oauth-issuer", "", "Issuer URL of the OAuth authorization server whose bearer tokens are required by the MCP endpoint.")
+	flags.StringVar(&opts.Cfg.OAuthResource, "oauth-resource", "", "Canonical URI of the MCP endpoint (e.g. 'https://toolbox.example.com/mcp'). Required with --oauth-issuer.")
+	flags.StringSliceVar(&opts.Cfg.OAuthAudiences, "oauth-audience", []string{}, "Accepted audiences of the bearer tokens. Defaults to the value of --oauth-resource.")
+	flags.StringSliceVar(&opts.Cfg.OAuthScopeToolsets, "oauth-scope-toolset", []string{}, "Maps an OAuth scope to a toolset it grants access to, as 'scope=toolset'. Use an empty toolset for the default toolset and '*' for every toolset. Can be specified multiple times.")
+	flags.StringVar(&opts.Cfg.TLSCertFile, "tls-cert-file", "", "File containing the PEM encoded certificate of the server. Enables HTTPS with --tls-key-file. Reloaded when it changes.")
+	flags.StringVar(&opts.Cfg.TLSKeyFile, "tls-key-file", "", "File containing the PEM encoded private key of the server. Reloaded when it changes.")
+	flags.StringVar(&opts.Cfg.TLSClientCAFile, "tls-client-ca-file", "", "File containing the PEM encoded CA certificates that client certificates are verified against. Enables mTLS.")
+	flags.BoolVar(&opts.Cfg.TLSRequireClientCert, "tls-require-client-cert", false, "Rejects connections without a client certificate verified against --tls-client-ca-file.")
+	flags.StringSliceVar(&opts.Cfg.RequireAuthServices, "require-auth-service", []string{}, "Requires every request to /api, /mcp and /ui to be verified by one of these auth services. Can be specified multiple times.")
 }
@@ -15,9 +15,22 @@
 package internal
 
 import (
+	// Import auth service packages for side effect of registration
+	_ "github.com/googleapis/genai-toolbox/internal/auth/apikey"
+	_ "github.com/googleapis/genai-toolbox/internal/auth/google"
+	_ "github.com/googleapis/genai-toolbox/internal/auth/introspection"
+	_ "github.com/googleapis/genai-toolbox/internal/auth/mtls"
+	_ "github.com/googleapis/genai-toolbox/internal/auth/oidc"
+
+	// Import embedding model packages for side effect of registration
+	_ "github.com/googleapis/genai-toolbox/internal/embeddingmodels/gemini"
+
 	// Import prompt packages for side effect of registration
 	_ "github.com/googleapis/genai-toolbox/internal/prompts/custom"
 
+	// Import resource packages for side effect of registration
+	_ "github.com/googleapis/genai-toolbox/internal/mcpresources/postgres/postgrestableschema"
+
 	// Import tool packages for side effect of registration
 	_ "github.com/googleapis/genai-toolbox/internal/tools/alloydb/alloydbcreatecluster"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/alloydb/alloydbcreateinstance"
@@ -148,6 +161,7 @@ import (
 	_ "github.com/googleapis/genai-toolbox/internal/tools/looker/lookerrunlookmltests"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/looker/lookerupdateprojectfile"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/looker/lookervalidateproject"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/mcp/mcptool"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/mindsdb/mindsdbexecutesql"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/mindsdb/mindsdbsql"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/mongodb/mongodbaggregate"
@@ -223,6 +237,7 @@ import (
 	_ "github.com/googleapis/genai-toolbox/internal/tools/tidb/tidbsql"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/trino/trinoexecutesql"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/trino/trinosql"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/utility/llmtransform"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/utility/wait"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/valkey"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/yugabytedbsql"
@@ -251,6 +266,7 @@ import (
 	_ "github.com/googleapis/genai-toolbox/internal/sources/firestore"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/http"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/looker"
+	_ "github.com/googleapis/genai-toolbox/internal/sources/mcp"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/mindsdb"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/mongodb"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/mssql"
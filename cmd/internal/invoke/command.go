@@ -21,7 +21,7 @@ import (
 
 	"github.com/googleapis/genai-toolbox/cmd/internal"
 	"github.com/googleapis/genai-toolbox/internal/server"
-	"github.com/googleapis/genai-toolbox/internal/server/resources"
+	mcputil "github.com/googleapis/genai-toolbox/internal/server/mcp/util"
 	"github.com/googleapis/genai-toolbox/internal/util/parameters"
 	"github.com/spf13/cobra"
 )
@@ -62,14 +62,14 @@ func runInvoke(cmd *cobra.Command, args []string, opts *internal.ToolboxOptions)
 	}
 
 	// Initialize Resources
-	sourcesMap, authServicesMap, embeddingModelsMap, toolsMap, toolsetsMap, promptsMap, promptsetsMap, err := server.InitializeConfigs(ctx, opts.Cfg)
+	initialized, err := server.InitializeConfigs(ctx, opts.Cfg)
 	if err != nil {
 		errMsg := fmt.Errorf("failed to initialize resources: %w", err)
 		opts.Logger.ErrorContext(ctx, errMsg.Error())
 		return errMsg
 	}
 
-	resourceMgr := resources.NewResourceManager(sourcesMap, authServicesMap, embeddingModelsMap, toolsMap, toolsetsMap, promptsMap, promptsetsMap)
+	resourceMgr := initialized.NewResourceManager()
 
 	// Execute Tool
 	toolName := args[0]
@@ -101,13 +101,6 @@ func runInvoke(cmd *cobra.Command, args []string, opts *internal.ToolboxOptions)
 		return errMsg
 	}
 
-	parsedParams, err = tool.EmbedParams(ctx, parsedParams, resourceMgr.GetEmbeddingModelMap())
-	if err != nil {
-		errMsg := fmt.Errorf("error embedding parameters: %w", err)
-		opts.Logger.ErrorContext(ctx, errMsg.Error())
-		return errMsg
-	}
-
 	// Client Auth not supported for ephemeral CLI call
 	requiresAuth, err := tool.RequiresClientAuthorization(resourceMgr)
 	if err != nil {
@@ -121,7 +114,16 @@ func runInvoke(cmd *cobra.Command, args []string, opts *internal.ToolboxOptions)
 		return errMsg
 	}
 
-	result, err := tool.Invoke(ctx, resourceMgr, parsedParams, "")
+	// the tool is invoked through the default toolset, whose policies apply
+	toolset, _ := resourceMgr.GetToolset("")
+	invokeCtx, parsedParams, toolErr := mcputil.PrepareInvocation(ctx, toolset, toolName, tool, parsedParams, resourceMgr.GetEmbeddingModelMap())
+	if toolErr != nil {
+		errMsg := fmt.Errorf("tool invocation denied: %w", toolErr)
+		opts.Logger.ErrorContext(ctx, errMsg.Error())
+		return errMsg
+	}
+
+	result, err := tool.Invoke(invokeCtx, resourceMgr, parsedParams, "")
 	if err != nil {
 		errMsg := fmt.Errorf("tool execution failed: %w", err)
 		opts.Logger.ErrorContext(ctx, errMsg.Error())
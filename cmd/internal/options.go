@@ -254,6 +254,9 @@ func (opts *ToolboxOptions) LoadConfig(ctx context.Context, parser *ToolsFilePar
 	opts.Cfg.ToolConfigs = finalToolsFile.Tools
 	opts.Cfg.ToolsetConfigs = finalToolsFile.Toolsets
 	opts.Cfg.PromptConfigs = finalToolsFile.Prompts
+	opts.Cfg.ResourceConfigs = finalToolsFile.Resources
+	opts.Cfg.PolicyConfigs = finalToolsFile.Policies
+	opts.Cfg.ServerMetadataConfig = finalToolsFile.Server
 
 	return isCustomConfigured, nil
 }
@@ -98,7 +98,11 @@ func runServe(cmd *cobra.Command, opts *internal.ToolboxOptions) error {
 		}
 		opts.Logger.InfoContext(ctx, "Server ready to serve!")
 		if opts.Cfg.UI {
-			opts.Logger.InfoContext(ctx, fmt.Sprintf("Toolbox UI is up and running at: http://%s:%d/ui", opts.Cfg.Address, opts.Cfg.Port))
+			scheme := "http"
+			if opts.Cfg.TLSCertFile != "" {
+				scheme = "https"
+			}
+			opts.Logger.InfoContext(ctx, fmt.Sprintf("Toolbox UI is up and running at: %s://%s:%d/ui", scheme, opts.Cfg.Address, opts.Cfg.Port))
 		}
 
 		go func() {
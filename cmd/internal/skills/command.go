@@ -25,7 +25,6 @@ import (
 
 	"github.com/googleapis/genai-toolbox/cmd/internal"
 	"github.com/googleapis/genai-toolbox/internal/server"
-	"github.com/googleapis/genai-toolbox/internal/server/resources"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 
 	"github.com/spf13/cobra"
@@ -224,12 +223,12 @@ func run(cmd *skillsCmd, opts *internal.ToolboxOptions) error {
 
 func (c *skillsCmd) collectTools(ctx context.Context, opts *internal.ToolboxOptions) (map[string]map[string]tools.Tool, error) {
 	// Initialize Resources
-	sourcesMap, authServicesMap, embeddingModelsMap, toolsMap, toolsetsMap, promptsMap, promptsetsMap, err := server.InitializeConfigs(ctx, opts.Cfg)
+	initialized, err := server.InitializeConfigs(ctx, opts.Cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize resources: %w", err)
 	}
 
-	resourceMgr := resources.NewResourceManager(sourcesMap, authServicesMap, embeddingModelsMap, toolsMap, toolsetsMap, promptsMap, promptsetsMap)
+	resourceMgr := initialized.NewResourceManager()
 
 	skillsToTools := make(map[string]map[string]tools.Tool)
 
@@ -254,14 +253,14 @@ func (c *skillsCmd) collectTools(ctx context.Context, opts *internal.ToolboxOpti
 		return skillsToTools, nil
 	}
 
-	if len(toolsetsMap) <= 1 {
+	if len(initialized.Toolsets) <= 1 {
 		// Default to all tools if no toolset found
-		skillsToTools[c.name] = toolsMap
+		skillsToTools[c.name] = initialized.Tools
 		return skillsToTools, nil
 	}
 
 	// One skill per toolset
-	for tsName, ts := range toolsetsMap {
+	for tsName, ts := range initialized.Toolsets {
 		if tsName == "" {
 			continue
 		}
@@ -37,6 +37,9 @@ type ToolsFile struct {
 	Tools           server.ToolConfigs           `yaml:"tools"`
 	Toolsets        server.ToolsetConfigs        `yaml:"toolsets"`
 	Prompts         server.PromptConfigs         `yaml:"prompts"`
+	Resources       server.ResourceConfigs       `yaml:"resources"`
+	Policies        server.PolicyConfigs         `yaml:"policies"`
+	Server          *server.ServerMetadataConfig `yaml:"server"`
 }
 
 type ToolsFileParser struct {
@@ -89,10 +92,13 @@ func (p *ToolsFileParser) ParseToolsFile(ctx context.Context, raw []byte) (Tools
 	}
 
 	// Parse contents
-	toolsFile.Sources, toolsFile.AuthServices, toolsFile.EmbeddingModels, toolsFile.Tools, toolsFile.Toolsets, toolsFile.Prompts, err = server.UnmarshalResourceConfig(ctx, raw)
+	c, err := server.UnmarshalFileConfigs(ctx, raw)
 	if err != nil {
 		return toolsFile, err
 	}
+	toolsFile.Sources, toolsFile.AuthServices, toolsFile.EmbeddingModels, toolsFile.Tools, toolsFile.Toolsets, toolsFile.Prompts, toolsFile.Resources = c.Sources, c.AuthServices, c.EmbeddingModels, c.Tools, c.Toolsets, c.Prompts, c.Resources
+	toolsFile.Policies = c.Policies
+	toolsFile.Server = c.Server
 	return toolsFile, nil
 }
 
@@ -231,6 +237,8 @@ func mergeToolsFiles(files ...ToolsFile) (ToolsFile, error) {
 		Tools:           make(server.ToolConfigs),
 		Toolsets:        make(server.ToolsetConfigs),
 		Prompts:         make(server.PromptConfigs),
+		Resources:       make(server.ResourceConfigs),
+		Policies:        make(server.PolicyConfigs),
 	}
 
 	var conflicts []string
@@ -291,11 +299,38 @@ func mergeToolsFiles(files ...ToolsFile) (ToolsFile, error) {
 				merged.Prompts[name] = prompt
 			}
 		}
+
+		// Check for conflicts and merge resources
+		for name, resource := range file.Resources {
+			if _, exists := merged.Resources[name]; exists {
+				conflicts = append(conflicts, fmt.Sprintf("resource '%s' (file #%d)", name, fileIndex+1))
+			} else {
+				merged.Resources[name] = resource
+			}
+		}
+
+		// Check for conflicts and merge policies
+		for name, policy := range file.Policies {
+			if _, exists := merged.Policies[name]; exists {
+				conflicts = append(conflicts, fmt.Sprintf("policy '%s' (file #%d)", name, fileIndex+1))
+			} else {
+				merged.Policies[name] = policy
+			}
+		}
+
+		// Check for conflicts and merge the server metadata
+		if file.Server != nil {
+			if merged.Server != nil {
+				conflicts = append(conflicts, fmt.Sprintf("server (file #%d)", fileIndex+1))
+			} else {
+				merged.Server = file.Server
+			}
+		}
 	}
 
 	// If conflicts were detected, return an error
 	if len(conflicts) > 0 {
-		return ToolsFile{}, fmt.Errorf("resource conflicts detected:\n  - %s\n\nPlease ensure each source, authService, tool, toolset and prompt has a unique name across all files", strings.Join(conflicts, "\n  - "))
+		return ToolsFile{}, fmt.Errorf("resource conflicts detected:\n  - %s\n\nPlease ensure each source, authService, tool, toolset, prompt, resource and policy has a unique name, and the server is configured only once, across all files", strings.Join(conflicts, "\n  - "))
 	}
 
 	return merged, nil
@@ -20,7 +20,10 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/auth/apikey"
 	"github.com/googleapis/genai-toolbox/internal/auth/google"
+	"github.com/googleapis/genai-toolbox/internal/auth/introspection"
+	"github.com/googleapis/genai-toolbox/internal/auth/oidc"
 	"github.com/googleapis/genai-toolbox/internal/embeddingmodels/gemini"
 	"github.com/googleapis/genai-toolbox/internal/prebuiltconfigs"
 	"github.com/googleapis/genai-toolbox/internal/prompts"
@@ -752,6 +755,127 @@ func TestParseToolFile(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "server metadata",
+			in: `
+kind: server
+title: Toolbox
+instructions: Use the tools.
+icons:
+- src: https://example.com/icon.png
+  mimeType: image/png
+  sizes: ["48x48"]
+prompts:
+  my-prompt:
+    title: Data analysis
+---
+kind: toolsets
+name: my-toolset
+tools: []
+instructions: Analyze the data.
+`,
+			wantToolsFile: ToolsFile{
+				Toolsets: server.ToolsetConfigs{
+					"my-toolset": tools.ToolsetConfig{
+						Name:           "my-toolset",
+						ToolNames:      []string{},
+						ServerMetadata: tools.ServerMetadata{Instructions: "Analyze the data."},
+					},
+				},
+				Server: &server.ServerMetadataConfig{
+					ServerMetadata: tools.ServerMetadata{
+						DisplayMetadata: tools.DisplayMetadata{
+							Title: "Toolbox",
+							Icons: []tools.Icon{{Src: "https://example.com/icon.png", MimeType: "image/png", Sizes: []string{"48x48"}}},
+						},
+						Instructions: "Use the tools.",
+					},
+					Prompts: map[string]tools.DisplayMetadata{"my-prompt": {Title: "Data analysis"}},
+				},
+			},
+		},
+		{
+			description: "authorization policies",
+			in: `
+kind: tools
+name: example_tool
+type: postgres-sql
+source: my-pg-instance
+description: some description
+statement: SELECT 1;
+authorization:
+  allow:
+  - authServices: [my-google-auth]
+    claims:
+      hd:
+        equals: example.com
+  deny:
+  - claims:
+      email:
+        in: [intern@example.com]
+---
+kind: toolsets
+name: example_toolset
+tools: [example_tool]
+authorization:
+  allow:
+  - claims:
+      groups:
+        contains: analysts
+`,
+			wantToolsFile: ToolsFile{
+				Tools: server.ToolConfigs{
+					"example_tool": postgressql.Config{
+						Name:         "example_tool",
+						Type:         "postgres-sql",
+						Source:       "my-pg-instance",
+						Description:  "some description",
+						Statement:    "SELECT 1;",
+						AuthRequired: []string{},
+						CommonConfig: tools.CommonConfig{
+							Authorization: &tools.AuthorizationPolicy{
+								Allow: []tools.AuthorizationRule{{
+									AuthServices: []string{"my-google-auth"},
+									Claims:       map[string]tools.ClaimMatcher{"hd": {Equals: "example.com"}},
+								}},
+								Deny: []tools.AuthorizationRule{{
+									Claims: map[string]tools.ClaimMatcher{"email": {In: []any{"intern@example.com"}}},
+								}},
+							},
+						},
+					},
+				},
+				Toolsets: server.ToolsetConfigs{
+					"example_toolset": tools.ToolsetConfig{
+						Name:      "example_toolset",
+						ToolNames: []string{"example_tool"},
+						Authorization: &tools.AuthorizationPolicy{
+							Allow: []tools.AuthorizationRule{{
+								Claims: map[string]tools.ClaimMatcher{"groups": {Contains: "analysts"}},
+							}},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "toolset with resources",
+			in: `
+kind: toolsets
+name: my-toolset
+tools: []
+resources: [table_schema]
+`,
+			wantToolsFile: ToolsFile{
+				Toolsets: server.ToolsetConfigs{
+					"my-toolset": tools.ToolsetConfig{
+						Name:          "my-toolset",
+						ToolNames:     []string{},
+						ResourceNames: []string{"table_schema"},
+					},
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.description, func(t *testing.T) {
@@ -775,10 +899,36 @@ func TestParseToolFile(t *testing.T) {
 			if diff := cmp.Diff(tc.wantToolsFile.Prompts, toolsFile.Prompts); diff != "" {
 				t.Fatalf("incorrect prompts parse: diff %v", diff)
 			}
+			if diff := cmp.Diff(tc.wantToolsFile.Server, toolsFile.Server); diff != "" {
+				t.Fatalf("incorrect server parse: diff %v", diff)
+			}
 		})
 	}
 }
 
+func TestParseToolFileUnknownToolsetKey(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	// a misspelled key must not leave the toolset without its policy
+	in := `
+kind: toolsets
+name: example_toolset
+tools: [example_tool]
+authorisation:
+  allow:
+  - claims:
+      groups:
+        contains: analysts
+`
+	parser := ToolsFileParser{}
+	_, err = parser.ParseToolsFile(ctx, testutils.FormatYaml(in))
+	if err == nil || !strings.Contains(err.Error(), "unknown field \"authorisation\"") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
 func TestParseToolFileWithAuth(t *testing.T) {
 	ctx, err := testutils.ContextWithNewLogger()
 	if err != nil {
@@ -1099,6 +1249,113 @@ func TestParseToolFileWithAuth(t *testing.T) {
 				Prompts: nil,
 			},
 		},
+		{
+			description: "oidc auth service",
+			in: `
+			kind: authServices
+			name: my-okta
+			type: oidc
+			issuer: https://example.okta.com/oauth2/default
+			audiences:
+				- api://toolbox
+			clockSkew: 30s
+			`,
+			wantToolsFile: ToolsFile{
+				AuthServices: server.AuthServiceConfigs{
+					"my-okta": oidc.Config{
+						Name:      "my-okta",
+						Type:      oidc.AuthServiceType,
+						Issuer:    "https://example.okta.com/oauth2/default",
+						Audiences: []string{"api://toolbox"},
+						ClockSkew: "30s",
+					},
+				},
+			},
+		},
+		{
+			description: "apiKey auth service",
+			in: `
+			kind: authServices
+			name: my-keys
+			type: apiKey
+			header: X-API-Key
+			keysFile: /etc/toolbox/keys.yaml
+			keys:
+				- hash: sha256:salt:digest
+				  expiresAt: "2027-01-01T00:00:00Z"
+				  claims:
+				    name: reporting
+				    scopes: [read]
+			`,
+			wantToolsFile: ToolsFile{
+				AuthServices: server.AuthServiceConfigs{
+					"my-keys": apikey.Config{
+						Name:     "my-keys",
+						Type:     apikey.AuthServiceType,
+						Header:   "X-API-Key",
+						KeysFile: "/etc/toolbox/keys.yaml",
+						Keys: []apikey.Key{{
+							Hash:      "sha256:salt:digest",
+							ExpiresAt: "2027-01-01T00:00:00Z",
+							Claims:    map[string]any{"name": "reporting", "scopes": []any{"read"}},
+						}},
+					},
+				},
+			},
+		},
+		{
+			description: "introspection auth service",
+			in: `
+			kind: authServices
+			name: my-introspection
+			type: introspection
+			introspectionUrl: https://idp.example.com/oauth2/introspect
+			clientId: toolbox
+			clientSecret: secret
+			audiences:
+				- api://toolbox
+			claims:
+				email: username
+			maxCacheDuration: 5m
+			`,
+			wantToolsFile: ToolsFile{
+				AuthServices: server.AuthServiceConfigs{
+					"my-introspection": introspection.Config{
+						Name:             "my-introspection",
+						Type:             introspection.AuthServiceType,
+						IntrospectionURL: "https://idp.example.com/oauth2/introspect",
+						ClientID:         "toolbox",
+						ClientSecret:     "secret",
+						Audiences:        []string{"api://toolbox"},
+						Claims:           map[string]string{"email": "username"},
+						MaxCacheDuration: "5m",
+					},
+				},
+			},
+		},
+		{
+			description: "policies",
+			in: `
+			kind: policies
+			name: dba-only
+			description: Only DBAs may execute SQL.
+			tools:
+				- execute-sql
+			condition: claims.exists(s, "dba" in claims[s].groups)
+			message: only DBAs may execute SQL
+			`,
+			wantToolsFile: ToolsFile{
+				Policies: server.PolicyConfigs{
+					"dba-only": tools.InvocationPolicyConfig{
+						Name:        "dba-only",
+						Description: "Only DBAs may execute SQL.",
+						Tools:       []string{"execute-sql"},
+						Condition:   `claims.exists(s, "dba" in claims[s].groups)`,
+						Message:     "only DBAs may execute SQL",
+					},
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.description, func(t *testing.T) {
@@ -1122,6 +1379,9 @@ func TestParseToolFileWithAuth(t *testing.T) {
 			if diff := cmp.Diff(tc.wantToolsFile.Prompts, toolsFile.Prompts); diff != "" {
 				t.Fatalf("incorrect prompts parse: diff %v", diff)
 			}
+			if diff := cmp.Diff(tc.wantToolsFile.Policies, toolsFile.Policies); diff != "" {
+				t.Fatalf("incorrect policies parse: diff %v", diff)
+			}
 		})
 	}
 
@@ -2138,6 +2398,9 @@ func TestMergeToolsFiles(t *testing.T) {
 		Sources: server.SourceConfigs{"source1": httpsrc.Config{Name: "source1"}},
 		Tools:   server.ToolConfigs{"tool2": http.Config{Name: "tool2"}},
 	}
+	fileWithServer := ToolsFile{
+		Server: &server.ServerMetadataConfig{ServerMetadata: tools.ServerMetadata{Instructions: "Use the tools."}},
+	}
 
 	testCases := []struct {
 		name    string
@@ -2154,6 +2417,8 @@ func TestMergeToolsFiles(t *testing.T) {
 				Tools:           server.ToolConfigs{"tool1": http.Config{Name: "tool1"}, "tool2": http.Config{Name: "tool2"}},
 				Toolsets:        server.ToolsetConfigs{"set1": tools.ToolsetConfig{Name: "set1"}, "set2": tools.ToolsetConfig{Name: "set2"}},
 				Prompts:         server.PromptConfigs{},
+				Resources:       server.ResourceConfigs{},
+				Policies:        server.PolicyConfigs{},
 				EmbeddingModels: server.EmbeddingModelConfigs{"model1": gemini.Config{Name: "gemini-text"}},
 			},
 			wantErr: false,
@@ -2163,6 +2428,11 @@ func TestMergeToolsFiles(t *testing.T) {
 			files:   []ToolsFile{file1, file2, fileWithConflicts},
 			wantErr: true,
 		},
+		{
+			name:    "merge with server configured twice",
+			files:   []ToolsFile{fileWithServer, fileWithServer},
+			wantErr: true,
+		},
 		{
 			name:  "merge single file",
 			files: []ToolsFile{file1},
@@ -2173,6 +2443,8 @@ func TestMergeToolsFiles(t *testing.T) {
 				Tools:           file1.Tools,
 				Toolsets:        file1.Toolsets,
 				Prompts:         server.PromptConfigs{},
+				Resources:       server.ResourceConfigs{},
+				Policies:        server.PolicyConfigs{},
 			},
 		},
 		{
@@ -2185,6 +2457,8 @@ func TestMergeToolsFiles(t *testing.T) {
 				Tools:           make(server.ToolConfigs),
 				Toolsets:        make(server.ToolsetConfigs),
 				Prompts:         server.PromptConfigs{},
+				Resources:       server.ResourceConfigs{},
+				Policies:        server.PolicyConfigs{},
 			},
 		},
 	}
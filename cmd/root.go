@@ -23,6 +23,7 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"reflect"
 	"runtime"
 	"slices"
 	"strings"
@@ -36,12 +37,8 @@ import (
 	"github.com/googleapis/genai-toolbox/cmd/internal/migrate"
 	"github.com/googleapis/genai-toolbox/cmd/internal/serve"
 	"github.com/googleapis/genai-toolbox/cmd/internal/skills"
-	"github.com/googleapis/genai-toolbox/internal/auth"
-	"github.com/googleapis/genai-toolbox/internal/embeddingmodels"
-	"github.com/googleapis/genai-toolbox/internal/prompts"
 	"github.com/googleapis/genai-toolbox/internal/server"
 	"github.com/googleapis/genai-toolbox/internal/sources"
-	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/util"
 	"github.com/spf13/cobra"
 )
@@ -138,23 +135,49 @@ func handleDynamicReload(ctx context.Context, toolsFile internal.ToolsFile, s *s
 		panic(err)
 	}
 
-	sourcesMap, authServicesMap, embeddingModelsMap, toolsMap, toolsetsMap, promptsMap, promptsetsMap, err := validateReloadEdits(ctx, toolsFile)
+	// sources whose config did not change are reused
+	previousSources := s.ResourceMgr.GetSourcesMap()
+	initialized, err := validateReloadEdits(ctx, toolsFile, previousSources)
 	if err != nil {
 		errMsg := fmt.Errorf("unable to validate reloaded edits: %w", err)
 		logger.WarnContext(ctx, errMsg.Error())
 		return err
 	}
 
-	s.ResourceMgr.SetResources(sourcesMap, authServicesMap, embeddingModelsMap, toolsMap, toolsetsMap, promptsMap, promptsetsMap)
+	previousToolsets, previousPromptsets := s.ResourceMgr.GetToolsetsMap(), s.ResourceMgr.GetPromptsetsMap()
+	drained := s.ResourceMgr.SetResources(initialized.Sources, initialized.AuthServices, initialized.EmbeddingModels, initialized.Tools, initialized.Toolsets, initialized.Prompts, initialized.Promptsets, initialized.Resources)
+	s.NotifyListChanged(ctx, previousToolsets, previousPromptsets)
+
+	// the replaced sources are closed to release their resources, once the
+	// invocations and tasks that still use them end
+	replaced := make(map[string]sources.Source)
+	for name, source := range previousSources {
+		if reused, ok := initialized.Sources[name]; !ok || !sameSource(reused, source) {
+			replaced[name] = source
+		}
+	}
+	go func() {
+		<-drained
+		if err := sources.CloseAll(replaced); err != nil {
+			logger.WarnContext(ctx, err.Error())
+		}
+	}()
 
 	return nil
 }
 
+// sameSource reports whether a and b are the same instance of a source.
+func sameSource(a, b sources.Source) bool {
+	if reflect.TypeOf(a) != reflect.TypeOf(b) || !reflect.TypeOf(a).Comparable() {
+		return false
+	}
+	return a == b
+}
+
 // validateReloadEdits checks that the reloaded tools file configs can initialized without failing
 func validateReloadEdits(
-	ctx context.Context, toolsFile internal.ToolsFile,
-) (map[string]sources.Source, map[string]auth.AuthService, map[string]embeddingmodels.EmbeddingModel, map[string]tools.Tool, map[string]tools.Toolset, map[string]prompts.Prompt, map[string]prompts.Promptset, error,
-) {
+	ctx context.Context, toolsFile internal.ToolsFile, previousSources map[string]sources.Source,
+) (server.InitializedConfigs, error) {
 	logger, err := util.LoggerFromContext(ctx)
 	if err != nil {
 		panic(err)
@@ -173,21 +196,25 @@ func validateReloadEdits(
 	reloadedConfig := server.ServerConfig{
 		Version:               versionString,
 		SourceConfigs:         toolsFile.Sources,
+		PreviousSources:       previousSources,
 		AuthServiceConfigs:    toolsFile.AuthServices,
 		EmbeddingModelConfigs: toolsFile.EmbeddingModels,
 		ToolConfigs:           toolsFile.Tools,
 		ToolsetConfigs:        toolsFile.Toolsets,
 		PromptConfigs:         toolsFile.Prompts,
+		ResourceConfigs:       toolsFile.Resources,
+		PolicyConfigs:         toolsFile.Policies,
+		ServerMetadataConfig:  toolsFile.Server,
 	}
 
-	sourcesMap, authServicesMap, embeddingModelsMap, toolsMap, toolsetsMap, promptsMap, promptsetsMap, err := server.InitializeConfigs(ctx, reloadedConfig)
+	initialized, err := server.InitializeConfigs(ctx, reloadedConfig)
 	if err != nil {
 		errMsg := fmt.Errorf("unable to initialize reloaded configs: %w", err)
 		logger.WarnContext(ctx, errMsg.Error())
-		return nil, nil, nil, nil, nil, nil, nil, err
+		return server.InitializedConfigs{}, err
 	}
 
-	return sourcesMap, authServicesMap, embeddingModelsMap, toolsMap, toolsetsMap, promptsMap, promptsetsMap, nil
+	return initialized, nil
 }
 
 // Helper to check if a file has a newer ModTime than stored in the map
@@ -382,7 +409,7 @@ func watchChanges(ctx context.Context, watchDirs map[string]bool, watchedFiles m
 
 			err = handleDynamicReload(ctx, reloadedToolsFile, s)
 			if err != nil {
-				errMsg := fmt.Errorf("unable to parse reloaded tools file at %q: %w", reloadedToolsFile, err)
+				errMsg := fmt.Errorf("unable to parse reloaded tools file at %q: %w", allFiles, err)
 				logger.WarnContext(ctx, errMsg.Error())
 				continue
 			}
@@ -481,7 +508,11 @@ func run(cmd *cobra.Command, opts *internal.ToolboxOptions) error {
 		}
 		opts.Logger.InfoContext(ctx, "Server ready to serve!")
 		if opts.Cfg.UI {
-			opts.Logger.InfoContext(ctx, fmt.Sprintf("Toolbox UI is up and running at: http://%s:%d/ui", opts.Cfg.Address, opts.Cfg.Port))
+			scheme := "http"
+			if opts.Cfg.TLSCertFile != "" {
+				scheme = "https"
+			}
+			opts.Logger.InfoContext(ctx, fmt.Sprintf("Toolbox UI is up and running at: %s://%s:%d/ui", scheme, opts.Cfg.Address, opts.Cfg.Port))
 		}
 
 		go func() {
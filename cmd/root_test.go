@@ -63,6 +63,18 @@ func withDefaults(c server.ServerConfig) server.ServerConfig {
 	if c.UserAgentMetadata == nil {
 		c.UserAgentMetadata = []string{}
 	}
+	if c.TaskWorkers == 0 {
+		c.TaskWorkers = 10
+	}
+	if c.OAuthAudiences == nil {
+		c.OAuthAudiences = []string{}
+	}
+	if c.OAuthScopeToolsets == nil {
+		c.OAuthScopeToolsets = []string{}
+	}
+	if c.RequireAuthServices == nil {
+		c.RequireAuthServices = []string{}
+	}
 	return c
 }
 
@@ -211,6 +223,13 @@ func TestServerConfigFlags(t *testing.T) {
 				DisableReload: true,
 			}),
 		},
+		{
+			desc: "disable default toolset api",
+			args: []string{"--disable-default-toolset-api"},
+			want: withDefaults(server.ServerConfig{
+				DisableDefaultToolsetAPI: true,
+			}),
+		},
 		{
 			desc: "allowed origin",
 			args: []string{"--allowed-origins", "http://foo.com,http://bar.com"},
@@ -232,6 +251,57 @@ func TestServerConfigFlags(t *testing.T) {
 				UserAgentMetadata: []string{"foo", "bar"},
 			}),
 		},
+		{
+			desc: "task workers",
+			args: []string{"--task-workers", "4"},
+			want: withDefaults(server.ServerConfig{
+				TaskWorkers: 4,
+			}),
+		},
+		{
+			desc: "stdio auth file",
+			args: []string{"--stdio-auth-file", "auth.yaml"},
+			want: withDefaults(server.ServerConfig{
+				StdioAuthFile: "auth.yaml",
+			}),
+		},
+		{
+			desc: "oauth",
+			args: []string{
+				"--oauth-issuer", "https://issuer.example.com",
+				"--oauth-resource", "https://toolbox.example.com/mcp",
+				"--oauth-audience", "toolbox",
+				"--oauth-scope-toolset", "tools.read=,tools.admin=*",
+			},
+			want: withDefaults(server.ServerConfig{
+				OAuthIssuer:        "https://issuer.example.com",
+				OAuthResource:      "https://toolbox.example.com/mcp",
+				OAuthAudiences:     []string{"toolbox"},
+				OAuthScopeToolsets: []string{"tools.read=", "tools.admin=*"},
+			}),
+		},
+		{
+			desc: "tls",
+			args: []string{
+				"--tls-cert-file", "tls.crt",
+				"--tls-key-file", "tls.key",
+				"--tls-client-ca-file", "ca.crt",
+				"--tls-require-client-cert",
+			},
+			want: withDefaults(server.ServerConfig{
+				TLSCertFile:          "tls.crt",
+				TLSKeyFile:           "tls.key",
+				TLSClientCAFile:      "ca.crt",
+				TLSRequireClientCert: true,
+			}),
+		},
+		{
+			desc: "require auth service",
+			args: []string{"--require-auth-service", "my-oidc,my-keys"},
+			want: withDefaults(server.ServerConfig{
+				RequireAuthServices: []string{"my-oidc", "my-keys"},
+			}),
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -0,0 +1,285 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apikey
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+const AuthServiceType string = "apiKey"
+
+// hashScheme prefixes the hashes of the keys, to leave room for other
+// schemes.
+const hashScheme = "sha256"
+
+func init() {
+	if !auth.Register(AuthServiceType, newConfig) {
+		panic(fmt.Sprintf("auth service type %q already registered", AuthServiceType))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (auth.AuthServiceConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+// validate interface
+var _ auth.AuthServiceConfig = Config{}
+
+// Auth service configuration
+type Config struct {
+	Name string `yaml:"name" validate:"required"`
+	Type string `yaml:"type" validate:"required"`
+	// Header carries the key. Defaults to `<name>_token`.
+	Header string `yaml:"header"`
+	Keys   []Key  `yaml:"keys" validate:"dive"`
+	// KeysFile is a YAML file with more `keys`, reloaded when it changes.
+	KeysFile string `yaml:"keysFile"`
+}
+
+// Key is an accepted API key.
+type Key struct {
+	// Hash is the salted hash of the key, as returned by HashKey.
+	Hash string `yaml:"hash" validate:"required"`
+	// ExpiresAt is the RFC 3339 time after which the key is rejected.
+	ExpiresAt string `yaml:"expiresAt"`
+	// Claims are attached to the requests made with the key, such as the
+	// name and team of the caller, or its scopes.
+	Claims map[string]any `yaml:"claims"`
+}
+
+// keysFile is the format of the file of `keysFile`.
+type keysFile struct {
+	Keys []Key `yaml:"keys"`
+}
+
+// HashKey returns the hash of key with salt, in the format accepted by
+// `hash`: "sha256:<salt>:<hex encoded SHA-256 of salt followed by key>".
+func HashKey(salt, key string) string {
+	sum := sha256.Sum256([]byte(salt + key))
+	return fmt.Sprintf("%s:%s:%s", hashScheme, salt, hex.EncodeToString(sum[:]))
+}
+
+// Returns the auth service type
+func (cfg Config) AuthServiceConfigType() string {
+	return AuthServiceType
+}
+
+// Initialize an API key auth service
+func (cfg Config) Initialize() (auth.AuthService, error) {
+	if len(cfg.Keys) == 0 && cfg.KeysFile == "" {
+		return nil, fmt.Errorf("one of `keys` or `keysFile` must be set")
+	}
+	keys, err := parseKeys(cfg.Keys)
+	if err != nil {
+		return nil, err
+	}
+	a := &AuthService{
+		Config: cfg,
+		keys:   keys,
+	}
+	if cfg.KeysFile != "" {
+		a.file = &keyFile{path: cfg.KeysFile}
+		if err := a.file.reload(); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+var _ auth.AuthService = AuthService{}
+
+// struct used to store auth service info
+type AuthService struct {
+	Config
+	keys []key
+	// file holds the keys of `keysFile`, if set
+	file *keyFile
+}
+
+// Returns the auth service type
+func (a AuthService) AuthServiceType() string {
+	return AuthServiceType
+}
+
+func (a AuthService) ToConfig() auth.AuthServiceConfig {
+	return a.Config
+}
+
+// Returns the name of the auth service
+func (a AuthService) GetName() string {
+	return a.Name
+}
+
+// Verifies the API key and return the claims of the key
+func (a AuthService) GetClaimsFromHeader(ctx context.Context, h http.Header) (map[string]any, error) {
+	header := a.Header
+	if header == "" {
+		header = a.Name + "_token"
+	}
+	apiKey := h.Get(header)
+	if apiKey == "" {
+		return nil, nil
+	}
+
+	keys := a.keys
+	if a.file != nil {
+		keys = slices.Concat(keys, a.file.current(ctx))
+	}
+	for _, k := range keys {
+		if !k.matches(apiKey) {
+			continue
+		}
+		if !k.expiresAt.IsZero() && time.Now().After(k.expiresAt) {
+			return nil, fmt.Errorf("API key verification failure: key expired at %s", k.expiresAt.Format(time.RFC3339))
+		}
+		return maps.Clone(k.claims), nil
+	}
+	return nil, fmt.Errorf("API key verification failure: invalid key")
+}
+
+// key is a parsed Key.
+type key struct {
+	salt      string
+	digest    []byte
+	expiresAt time.Time
+	claims    map[string]any
+}
+
+func (k key) matches(apiKey string) bool {
+	sum := sha256.Sum256([]byte(k.salt + apiKey))
+	return subtle.ConstantTimeCompare(sum[:], k.digest) == 1
+}
+
+func parseKeys(keys []Key) ([]key, error) {
+	parsed := make([]key, 0, len(keys))
+	for i, k := range keys {
+		scheme, rest, _ := strings.Cut(k.Hash, ":")
+		salt, digest, ok := strings.Cut(rest, ":")
+		if scheme != hashScheme || !ok || salt == "" {
+			return nil, fmt.Errorf("key %d: hash must have the format %q", i, hashScheme+":<salt>:<digest>")
+		}
+		d, err := hex.DecodeString(digest)
+		if err != nil || len(d) != sha256.Size {
+			return nil, fmt.Errorf("key %d: digest must be a hex encoded SHA-256 hash", i)
+		}
+		p := key{salt: salt, digest: d, claims: k.Claims}
+		if k.ExpiresAt != "" {
+			if p.expiresAt, err = time.Parse(time.RFC3339, k.ExpiresAt); err != nil {
+				return nil, fmt.Errorf("key %d: invalid expiresAt: %w", i, err)
+			}
+		}
+		if p.claims == nil {
+			p.claims = map[string]any{}
+		}
+		parsed = append(parsed, p)
+	}
+	return parsed, nil
+}
+
+// keyFile holds the keys of a file, reloaded when its modification time or
+// size changes.
+type keyFile struct {
+	path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+	keys    []key
+	// lastErr is the last error logged, so that it is logged once
+	lastErr string
+}
+
+// current returns the keys of the file, reloading it if it changed. A file
+// that was removed has no keys, and a file that can not be read or parsed
+// rejects every key until it is fixed, so that removing or breaking the file
+// never leaves stale keys valid.
+func (f *keyFile) current(ctx context.Context) []key {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	info, err := os.Stat(f.path)
+	if err == nil && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return f.keys
+	}
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		err = fmt.Errorf("keys file %q was removed", f.path)
+		f.modTime, f.size = time.Time{}, 0
+	case err == nil:
+		if err = f.reloadLocked(info); err != nil {
+			// do not retry until the file changes again
+			f.modTime, f.size = info.ModTime(), info.Size()
+		}
+	}
+	if err != nil {
+		f.keys = nil
+		if msg := err.Error(); msg != f.lastErr {
+			f.lastErr = msg
+			if logger, lErr := util.LoggerFromContext(ctx); lErr == nil {
+				logger.ErrorContext(ctx, fmt.Sprintf("rejecting the API keys of the keys file: %s", err))
+			}
+		}
+		return nil
+	}
+	f.lastErr = ""
+	return f.keys
+}
+
+func (f *keyFile) reload() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return fmt.Errorf("unable to read keys file: %w", err)
+	}
+	return f.reloadLocked(info)
+}
+
+func (f *keyFile) reloadLocked(info os.FileInfo) error {
+	b, err := os.ReadFile(f.path)
+	if err != nil {
+		return fmt.Errorf("unable to read keys file: %w", err)
+	}
+	var content keysFile
+	if err := yaml.UnmarshalWithOptions(b, &content, yaml.Strict()); err != nil {
+		return fmt.Errorf("unable to parse keys file %q: %w", f.path, err)
+	}
+	keys, err := parseKeys(content.Keys)
+	if err != nil {
+		return fmt.Errorf("keys file %q: %w", f.path, err)
+	}
+	f.keys, f.modTime, f.size = keys, info.ModTime(), info.Size()
+	return nil
+}
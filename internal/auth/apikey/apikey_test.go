@@ -0,0 +1,221 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apikey_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/auth/apikey"
+)
+
+func TestGetClaimsFromHeader(t *testing.T) {
+	cfg := apikey.Config{
+		Name: "my-keys",
+		Type: apikey.AuthServiceType,
+		Keys: []apikey.Key{
+			{
+				Hash:   apikey.HashKey("s1", "reporting-key"),
+				Claims: map[string]any{"name": "reporting", "team": "data", "scopes": []any{"read"}},
+			},
+			{
+				Hash:      apikey.HashKey("s2", "expired-key"),
+				ExpiresAt: time.Now().Add(-time.Hour).Format(time.RFC3339),
+			},
+			{
+				Hash:      apikey.HashKey("s3", "unexpired-key"),
+				ExpiresAt: time.Now().Add(time.Hour).Format(time.RFC3339),
+			},
+		},
+	}
+	a, err := cfg.Initialize()
+	if err != nil {
+		t.Fatalf("unable to initialize auth service: %s", err)
+	}
+
+	tcs := []struct {
+		desc   string
+		header http.Header
+		want   map[string]any
+		err    string
+	}{
+		{
+			desc:   "no key",
+			header: http.Header{},
+		},
+		{
+			desc:   "valid key",
+			header: http.Header{"My-Keys_token": {"reporting-key"}},
+			want:   map[string]any{"name": "reporting", "team": "data", "scopes": []any{"read"}},
+		},
+		{
+			desc:   "invalid key",
+			header: http.Header{"My-Keys_token": {"guessed-key"}},
+			err:    "API key verification failure: invalid key",
+		},
+		{
+			desc:   "expired key",
+			header: http.Header{"My-Keys_token": {"expired-key"}},
+			err:    "API key verification failure: key expired",
+		},
+		{
+			desc:   "key before expiry",
+			header: http.Header{"My-Keys_token": {"unexpired-key"}},
+			want:   map[string]any{},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			claims, err := a.GetClaimsFromHeader(context.Background(), tc.header)
+			if tc.err != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tc.err) {
+					t.Fatalf("unexpected error: got %v, want %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, claims); diff != "" {
+				t.Fatalf("unexpected claims (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCustomHeader(t *testing.T) {
+	cfg := apikey.Config{
+		Name:   "my-keys",
+		Type:   apikey.AuthServiceType,
+		Header: "X-API-Key",
+		Keys:   []apikey.Key{{Hash: apikey.HashKey("salt", "secret"), Claims: map[string]any{"name": "svc"}}},
+	}
+	a, err := cfg.Initialize()
+	if err != nil {
+		t.Fatalf("unable to initialize auth service: %s", err)
+	}
+	claims, err := a.GetClaimsFromHeader(context.Background(), http.Header{"X-Api-Key": {"secret"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if claims["name"] != "svc" {
+		t.Fatalf("unexpected claims: %v", claims)
+	}
+}
+
+func TestKeysFileReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.yaml")
+	write := func(content string, modTime time.Time) {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("unable to write keys file: %s", err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("unable to set modification time: %s", err)
+		}
+	}
+	now := time.Now()
+	write("keys:\n- hash: "+apikey.HashKey("a", "old-key")+"\n  claims:\n    name: old\n", now.Add(-time.Minute))
+
+	cfg := apikey.Config{Name: "my-keys", Type: apikey.AuthServiceType, KeysFile: path}
+	a, err := cfg.Initialize()
+	if err != nil {
+		t.Fatalf("unable to initialize auth service: %s", err)
+	}
+	check := func(key string, wantName string) {
+		t.Helper()
+		claims, err := a.GetClaimsFromHeader(context.Background(), http.Header{"My-Keys_token": {key}})
+		if wantName == "" {
+			if err == nil {
+				t.Fatalf("expected key %q to be rejected", key)
+			}
+			return
+		}
+		if err != nil {
+			t.Fatalf("unexpected error for key %q: %s", key, err)
+		}
+		if claims["name"] != wantName {
+			t.Fatalf("unexpected claims for key %q: %v", key, claims)
+		}
+	}
+	check("old-key", "old")
+	check("new-key", "")
+
+	// rotate the keys
+	write("keys:\n- hash: "+apikey.HashKey("b", "new-key")+"\n  claims:\n    name: new\n", now)
+	check("old-key", "")
+	check("new-key", "new")
+
+	// an invalid file rejects every key until it is fixed
+	write("keys: [", now.Add(time.Minute))
+	check("new-key", "")
+	write("keys:\n- hash: "+apikey.HashKey("b", "new-key")+"\n  claims:\n    name: new\n", now.Add(2*time.Minute))
+	check("new-key", "new")
+
+	// removing the file revokes its keys
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("unable to remove keys file: %s", err)
+	}
+	check("new-key", "")
+	write("keys:\n- hash: "+apikey.HashKey("b", "new-key")+"\n  claims:\n    name: new\n", now.Add(3*time.Minute))
+	check("new-key", "new")
+}
+
+func TestInitializeErrors(t *testing.T) {
+	tcs := []struct {
+		desc string
+		cfg  apikey.Config
+		err  string
+	}{
+		{
+			desc: "no keys",
+			cfg:  apikey.Config{Name: "my-keys", Type: apikey.AuthServiceType},
+			err:  "one of `keys` or `keysFile` must be set",
+		},
+		{
+			desc: "unknown hash scheme",
+			cfg:  apikey.Config{Name: "my-keys", Type: apikey.AuthServiceType, Keys: []apikey.Key{{Hash: "md5:salt:abc"}}},
+			err:  `key 0: hash must have the format "sha256:<salt>:<digest>"`,
+		},
+		{
+			desc: "invalid digest",
+			cfg:  apikey.Config{Name: "my-keys", Type: apikey.AuthServiceType, Keys: []apikey.Key{{Hash: "sha256:salt:abc"}}},
+			err:  "key 0: digest must be a hex encoded SHA-256 hash",
+		},
+		{
+			desc: "invalid expiry",
+			cfg:  apikey.Config{Name: "my-keys", Type: apikey.AuthServiceType, Keys: []apikey.Key{{Hash: apikey.HashKey("salt", "key"), ExpiresAt: "tomorrow"}}},
+			err:  "key 0: invalid expiresAt",
+		},
+		{
+			desc: "missing keys file",
+			cfg:  apikey.Config{Name: "my-keys", Type: apikey.AuthServiceType, KeysFile: filepath.Join(t.TempDir(), "missing.yaml")},
+			err:  "unable to read keys file",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := tc.cfg.Initialize()
+			if err == nil || !strings.HasPrefix(err.Error(), tc.err) {
+				t.Fatalf("unexpected error: got %v, want %q", err, tc.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/auth"
+)
+
+type mockAuthServiceConfig struct {
+	name string
+	Type string
+}
+
+func (m *mockAuthServiceConfig) AuthServiceConfigType() string         { return m.Type }
+func (m *mockAuthServiceConfig) Initialize() (auth.AuthService, error) { return nil, nil }
+
+var errMockFactory = errors.New("mock factory error")
+
+func mockFactory(ctx context.Context, name string, decoder *yaml.Decoder) (auth.AuthServiceConfig, error) {
+	return &mockAuthServiceConfig{name: name, Type: "mockType"}, nil
+}
+
+func mockErrorFactory(ctx context.Context, name string, decoder *yaml.Decoder) (auth.AuthServiceConfig, error) {
+	return nil, errMockFactory
+}
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("RegisterAndDecodeSuccess", func(t *testing.T) {
+		authServiceType := "testTypeSuccess"
+		if !auth.Register(authServiceType, mockFactory) {
+			t.Fatal("expected registration to succeed")
+		}
+		// This should fail because we are registering a duplicate
+		if auth.Register(authServiceType, mockFactory) {
+			t.Fatal("expected duplicate registration to fail")
+		}
+
+		decoder := yaml.NewDecoder(strings.NewReader(""))
+		config, err := auth.DecodeConfig(ctx, authServiceType, "testAuthService", decoder)
+		if err != nil {
+			t.Fatalf("expected DecodeConfig to succeed, but got error: %v", err)
+		}
+		if config == nil {
+			t.Fatal("expected a non-nil config")
+		}
+	})
+
+	t.Run("DecodeUnknownType", func(t *testing.T) {
+		decoder := yaml.NewDecoder(strings.NewReader(""))
+		_, err := auth.DecodeConfig(ctx, "unregisteredType", "testAuthService", decoder)
+		if err == nil {
+			t.Fatal("expected an error for unknown type, but got nil")
+		}
+		if !strings.Contains(err.Error(), "not a valid type of auth service") {
+			t.Errorf("expected error to contain 'not a valid type of auth service', but got: %v", err)
+		}
+	})
+
+	t.Run("FactoryReturnsError", func(t *testing.T) {
+		authServiceType := "testTypeError"
+		if !auth.Register(authServiceType, mockErrorFactory) {
+			t.Fatal("expected registration to succeed")
+		}
+
+		decoder := yaml.NewDecoder(strings.NewReader(""))
+		_, err := auth.DecodeConfig(ctx, authServiceType, "testAuthService", decoder)
+		if err == nil {
+			t.Fatal("expected an error from the factory, but got nil")
+		}
+		if !errors.Is(err, errMockFactory) {
+			t.Errorf("expected error to wrap mock factory error, but it didn't")
+		}
+	})
+}
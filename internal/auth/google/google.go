@@ -19,12 +19,27 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/auth"
 	"google.golang.org/api/idtoken"
 )
 
 const AuthServiceType string = "google"
 
+func init() {
+	if !auth.Register(AuthServiceType, newConfig) {
+		panic(fmt.Sprintf("auth service type %q already registered", AuthServiceType))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (auth.AuthServiceConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
 // validate interface
 var _ auth.AuthServiceConfig = Config{}
 
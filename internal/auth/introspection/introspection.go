@@ -0,0 +1,236 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package introspection
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+)
+
+const AuthServiceType string = "introspection"
+
+// maxResponseSize limits the size of the introspection responses read.
+const maxResponseSize = 1 << 20
+
+func init() {
+	if !auth.Register(AuthServiceType, newConfig) {
+		panic(fmt.Sprintf("auth service type %q already registered", AuthServiceType))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (auth.AuthServiceConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+// validate interface
+var _ auth.AuthServiceConfig = Config{}
+
+// Auth service configuration
+type Config struct {
+	Name string `yaml:"name" validate:"required"`
+	Type string `yaml:"type" validate:"required"`
+	// IntrospectionURL is the RFC 7662 token introspection endpoint.
+	IntrospectionURL string `yaml:"introspectionUrl" validate:"required"`
+	ClientID         string `yaml:"clientId" validate:"required"`
+	ClientSecret     string `yaml:"clientSecret" validate:"required"`
+	// Audiences, if set, must include one of the audiences of the token.
+	Audiences []string `yaml:"audiences"`
+	// Claims maps claim names to fields of the introspection response. All
+	// fields but `active` are claims if empty.
+	Claims map[string]string `yaml:"claims"`
+	// MaxCacheDuration caps how long active tokens are cached, e.g. "5m".
+	// Tokens are cached until they expire if empty.
+	MaxCacheDuration string `yaml:"maxCacheDuration"`
+}
+
+// Returns the auth service type
+func (cfg Config) AuthServiceConfigType() string {
+	return AuthServiceType
+}
+
+// Initialize a token introspection auth service
+func (cfg Config) Initialize() (auth.AuthService, error) {
+	u, err := url.Parse(cfg.IntrospectionURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return nil, fmt.Errorf("invalid introspectionUrl %q", cfg.IntrospectionURL)
+	}
+	var maxCacheDuration time.Duration
+	if cfg.MaxCacheDuration != "" {
+		d, err := time.ParseDuration(cfg.MaxCacheDuration)
+		if err != nil {
+			return nil, fmt.Errorf("invalid maxCacheDuration %q: %w", cfg.MaxCacheDuration, err)
+		}
+		if d < 0 {
+			return nil, fmt.Errorf("maxCacheDuration must not be negative: %q", cfg.MaxCacheDuration)
+		}
+		maxCacheDuration = d
+	}
+	a := &AuthService{
+		Config:           cfg,
+		client:           &http.Client{Timeout: 10 * time.Second},
+		cache:            sources.NewCache(nil),
+		maxCacheDuration: maxCacheDuration,
+	}
+	return a, nil
+}
+
+var _ auth.AuthService = AuthService{}
+
+// struct used to store auth service info
+type AuthService struct {
+	Config
+	client *http.Client
+	// cache holds the claims of active tokens, keyed by the hash of the token
+	cache            *sources.Cache
+	maxCacheDuration time.Duration
+}
+
+// Returns the auth service type
+func (a AuthService) AuthServiceType() string {
+	return AuthServiceType
+}
+
+func (a AuthService) ToConfig() auth.AuthServiceConfig {
+	return a.Config
+}
+
+// Returns the name of the auth service
+func (a AuthService) GetName() string {
+	return a.Name
+}
+
+// Introspects the token and return claims
+func (a AuthService) GetClaimsFromHeader(ctx context.Context, h http.Header) (map[string]any, error) {
+	token := h.Get(a.Name + "_token")
+	if token == "" {
+		return nil, nil
+	}
+	// the token may be sent as a bearer token
+	if prefix := "bearer "; len(token) > len(prefix) && strings.EqualFold(token[:len(prefix)], prefix) {
+		token = token[len(prefix):]
+	}
+
+	sum := sha256.Sum256([]byte(token))
+	key := hex.EncodeToString(sum[:])
+	if claims, ok := a.cache.Get(key); ok {
+		return maps.Clone(claims.(map[string]any)), nil
+	}
+
+	resp, err := a.introspect(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("token introspection failure: %w", err)
+	}
+	if active, _ := resp["active"].(bool); !active {
+		return nil, fmt.Errorf("token introspection failure: inactive token")
+	}
+	if len(a.Audiences) > 0 && !hasAudience(resp["aud"], a.Audiences) {
+		return nil, fmt.Errorf("token introspection failure: invalid audience")
+	}
+	claims := a.claims(resp)
+
+	// active tokens are cached until they expire; tokens without an
+	// expiration time are introspected on every request
+	if exp, ok := resp["exp"].(float64); ok {
+		expiresAt := time.Unix(int64(exp), 0)
+		if limit := time.Now().Add(a.maxCacheDuration); a.maxCacheDuration > 0 && limit.Before(expiresAt) {
+			expiresAt = limit
+		}
+		if time.Now().Before(expiresAt) {
+			a.cache.SetWithExpiry(key, claims, expiresAt)
+		}
+	}
+	return maps.Clone(claims), nil
+}
+
+// introspect posts token to the introspection endpoint, authenticated with
+// the client credentials, and returns the response.
+func (a AuthService) introspect(ctx context.Context, token string) (map[string]any, error) {
+	form := url.Values{"token": {token}, "token_type_hint": {"access_token"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.IntrospectionURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	// client credentials are form encoded before basic authentication
+	// (RFC 6749, section 2.3.1)
+	req.SetBasicAuth(url.QueryEscape(a.ClientID), url.QueryEscape(a.ClientSecret))
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to reach introspection endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("introspection endpoint returned status %d", resp.StatusCode)
+	}
+	var out map[string]any
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&out); err != nil {
+		return nil, fmt.Errorf("unable to decode introspection response: %w", err)
+	}
+	return out, nil
+}
+
+// claims returns the claims of an introspection response.
+func (a AuthService) claims(resp map[string]any) map[string]any {
+	if len(a.Claims) == 0 {
+		claims := maps.Clone(resp)
+		delete(claims, "active")
+		return claims
+	}
+	claims := make(map[string]any, len(a.Claims))
+	for claim, field := range a.Claims {
+		v, ok := resp[field]
+		if !ok {
+			continue
+		}
+		claims[claim] = v
+	}
+	return claims
+}
+
+// hasAudience reports whether aud, a string or a list of strings, includes
+// one of audiences.
+func hasAudience(aud any, audiences []string) bool {
+	switch v := aud.(type) {
+	case string:
+		return slices.Contains(audiences, v)
+	case []any:
+		for _, s := range v {
+			if s, ok := s.(string); ok && slices.Contains(audiences, s) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,246 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package introspection_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/auth/introspection"
+)
+
+// newEndpoint returns an introspection endpoint that answers with the
+// response of each token, and counts the requests it serves.
+func newEndpoint(t *testing.T, responses map[string]map[string]any) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if id, secret, ok := r.BasicAuth(); !ok || id != "toolbox" || secret != "s3cr%2Ft" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		resp, ok := responses[r.PostForm.Get("token")]
+		if !ok {
+			resp = map[string]any{"active": false}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestGetClaimsFromHeader(t *testing.T) {
+	exp := time.Now().Add(time.Hour).Unix()
+	srv, _ := newEndpoint(t, map[string]map[string]any{
+		"alice-token": {"active": true, "sub": "alice", "username": "alice@example.com", "aud": []string{"toolbox"}, "exp": exp},
+		"other-token": {"active": true, "sub": "bob", "aud": "other", "exp": exp},
+	})
+	cfg := introspection.Config{
+		Name:             "my-introspection",
+		Type:             introspection.AuthServiceType,
+		IntrospectionURL: srv.URL,
+		ClientID:         "toolbox",
+		ClientSecret:     "s3cr/t",
+		Audiences:        []string{"toolbox"},
+	}
+	a, err := cfg.Initialize()
+	if err != nil {
+		t.Fatalf("unable to initialize auth service: %s", err)
+	}
+
+	tcs := []struct {
+		desc   string
+		header http.Header
+		want   map[string]any
+		err    string
+	}{
+		{
+			desc:   "no token",
+			header: http.Header{},
+		},
+		{
+			desc:   "active token",
+			header: http.Header{"My-Introspection_token": {"alice-token"}},
+			want:   map[string]any{"sub": "alice", "username": "alice@example.com", "aud": []any{"toolbox"}, "exp": float64(exp)},
+		},
+		{
+			desc:   "bearer token",
+			header: http.Header{"My-Introspection_token": {"Bearer alice-token"}},
+			want:   map[string]any{"sub": "alice", "username": "alice@example.com", "aud": []any{"toolbox"}, "exp": float64(exp)},
+		},
+		{
+			desc:   "inactive token",
+			header: http.Header{"My-Introspection_token": {"revoked-token"}},
+			err:    "token introspection failure: inactive token",
+		},
+		{
+			desc:   "wrong audience",
+			header: http.Header{"My-Introspection_token": {"other-token"}},
+			err:    "token introspection failure: invalid audience",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			claims, err := a.GetClaimsFromHeader(context.Background(), tc.header)
+			if tc.err != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tc.err) {
+					t.Fatalf("unexpected error: got %v, want %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, claims); diff != "" {
+				t.Fatalf("unexpected claims (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClaimMapping(t *testing.T) {
+	srv, _ := newEndpoint(t, map[string]map[string]any{
+		"alice-token": {"active": true, "sub": "alice", "username": "alice@example.com", "scope": "read write"},
+	})
+	cfg := introspection.Config{
+		Name:             "my-introspection",
+		Type:             introspection.AuthServiceType,
+		IntrospectionURL: srv.URL,
+		ClientID:         "toolbox",
+		ClientSecret:     "s3cr/t",
+		Claims:           map[string]string{"email": "username", "scope": "scope", "group": "group"},
+	}
+	a, err := cfg.Initialize()
+	if err != nil {
+		t.Fatalf("unable to initialize auth service: %s", err)
+	}
+	claims, err := a.GetClaimsFromHeader(context.Background(), http.Header{"My-Introspection_token": {"alice-token"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := map[string]any{"email": "alice@example.com", "scope": "read write"}
+	if diff := cmp.Diff(want, claims); diff != "" {
+		t.Fatalf("unexpected claims (-want +got):\n%s", diff)
+	}
+}
+
+func TestCache(t *testing.T) {
+	srv, requests := newEndpoint(t, map[string]map[string]any{
+		"expiring-token":   {"active": true, "sub": "alice", "exp": time.Now().Add(time.Hour).Unix()},
+		"expired-token":    {"active": true, "sub": "alice", "exp": time.Now().Add(-time.Minute).Unix()},
+		"unexpiring-token": {"active": true, "sub": "alice"},
+	})
+	tcs := []struct {
+		desc             string
+		token            string
+		maxCacheDuration string
+		wantRequests     int32
+	}{
+		{
+			desc:         "cached until expiry",
+			token:        "expiring-token",
+			wantRequests: 1,
+		},
+		{
+			desc:             "cached up to max cache duration",
+			token:            "expiring-token",
+			maxCacheDuration: "1ns",
+			wantRequests:     2,
+		},
+		{
+			desc:         "expired token",
+			token:        "expired-token",
+			wantRequests: 2,
+		},
+		{
+			desc:         "token without expiry",
+			token:        "unexpiring-token",
+			wantRequests: 2,
+		},
+		{
+			desc:         "inactive token",
+			token:        "revoked-token",
+			wantRequests: 2,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			cfg := introspection.Config{
+				Name:             "my-introspection",
+				Type:             introspection.AuthServiceType,
+				IntrospectionURL: srv.URL,
+				ClientID:         "toolbox",
+				ClientSecret:     "s3cr/t",
+				MaxCacheDuration: tc.maxCacheDuration,
+			}
+			a, err := cfg.Initialize()
+			if err != nil {
+				t.Fatalf("unable to initialize auth service: %s", err)
+			}
+			requests.Store(0)
+			for range 2 {
+				_, _ = a.GetClaimsFromHeader(context.Background(), http.Header{"My-Introspection_token": {tc.token}})
+			}
+			if got := requests.Load(); got != tc.wantRequests {
+				t.Fatalf("unexpected number of introspection requests: got %d, want %d", got, tc.wantRequests)
+			}
+		})
+	}
+}
+
+func TestInitializeErrors(t *testing.T) {
+	tcs := []struct {
+		desc string
+		cfg  introspection.Config
+		err  string
+	}{
+		{
+			desc: "invalid introspection URL",
+			cfg:  introspection.Config{Name: "my-introspection", Type: introspection.AuthServiceType, IntrospectionURL: "example.com/introspect"},
+			err:  `invalid introspectionUrl "example.com/introspect"`,
+		},
+		{
+			desc: "invalid max cache duration",
+			cfg:  introspection.Config{Name: "my-introspection", Type: introspection.AuthServiceType, IntrospectionURL: "https://example.com/introspect", MaxCacheDuration: "soon"},
+			err:  `invalid maxCacheDuration "soon"`,
+		},
+		{
+			desc: "negative max cache duration",
+			cfg:  introspection.Config{Name: "my-introspection", Type: introspection.AuthServiceType, IntrospectionURL: "https://example.com/introspect", MaxCacheDuration: "-1m"},
+			err:  `maxCacheDuration must not be negative: "-1m"`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := tc.cfg.Initialize()
+			if err == nil || !strings.HasPrefix(err.Error(), tc.err) {
+				t.Fatalf("unexpected error: got %v, want %q", err, tc.err)
+			}
+		})
+	}
+}
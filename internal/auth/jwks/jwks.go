@@ -0,0 +1,288 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package jwks verifies JSON Web Tokens signed with the keys an issuer
+// publishes as a JSON Web Key Set.
+package jwks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	jose "github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// DefaultCacheTTL is how long the keys of an issuer are cached by
+	// default before they are fetched again.
+	DefaultCacheTTL = time.Hour
+	// minRefreshInterval limits how often the keys are fetched again, when a
+	// token is signed with an unknown key or a fetch failed.
+	minRefreshInterval = time.Minute
+	// DefaultLeeway is the clock skew tolerated by default when validating
+	// the time claims.
+	DefaultLeeway = time.Minute
+)
+
+// discoveryPaths are the well-known paths of the metadata of an issuer, in
+// order: OAuth 2.0 authorization server metadata (RFC 8414), then OpenID
+// Connect discovery.
+var discoveryPaths = []string{
+	"/.well-known/oauth-authorization-server",
+	"/.well-known/openid-configuration",
+}
+
+// signatureAlgorithms are the asymmetric algorithms accepted for tokens.
+var signatureAlgorithms = []jose.SignatureAlgorithm{
+	jose.RS256, jose.RS384, jose.RS512,
+	jose.PS256, jose.PS384, jose.PS512,
+	jose.ES256, jose.ES384, jose.ES512,
+	jose.EdDSA,
+}
+
+// Verifier verifies tokens issued by an issuer. The keys of the issuer are
+// fetched on first use and cached.
+type Verifier struct {
+	issuer   string
+	client   *http.Client
+	leeway   time.Duration
+	cacheTTL time.Duration
+	// fetches shares a fetch of the keys between concurrent verifications
+	fetches singleflight.Group
+
+	mu        sync.Mutex
+	jwksURL   string
+	keys      jose.JSONWebKeySet
+	fetchedAt time.Time
+	// failedAt is when the last fetch failed, with fetchErr, if it failed
+	failedAt time.Time
+	fetchErr error
+}
+
+// cache is the state of the cached keys.
+type cache struct {
+	// keys are the cached keys that match a key ID
+	keys      []jose.JSONWebKey
+	fetchedAt time.Time
+	failedAt  time.Time
+	fetchErr  error
+}
+
+// NewVerifier returns a Verifier of the tokens of issuer. If jwksURL is
+// empty, it is discovered from the metadata of the issuer.
+func NewVerifier(issuer, jwksURL string) *Verifier {
+	return &Verifier{
+		issuer:   issuer,
+		jwksURL:  jwksURL,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		leeway:   DefaultLeeway,
+		cacheTTL: DefaultCacheTTL,
+	}
+}
+
+// WithLeeway sets the clock skew tolerated when validating the time claims,
+// and returns v.
+func (v *Verifier) WithLeeway(leeway time.Duration) *Verifier {
+	v.leeway = leeway
+	return v
+}
+
+// WithCacheTTL sets how long the keys are cached before they are fetched
+// again, and returns v.
+func (v *Verifier) WithCacheTTL(ttl time.Duration) *Verifier {
+	v.cacheTTL = ttl
+	return v
+}
+
+// Verify checks the signature, issuer and time claims of token and returns
+// its claims. If audiences is not empty, the audience of the token must
+// include one of them.
+func (v *Verifier) Verify(ctx context.Context, token string, audiences []string) (map[string]any, error) {
+	parsed, err := jwt.ParseSigned(token, signatureAlgorithms)
+	if err != nil {
+		return nil, fmt.Errorf("malformed token: %w", err)
+	}
+	if len(parsed.Headers) != 1 {
+		return nil, fmt.Errorf("token must have exactly one signature")
+	}
+	keys, err := v.keysFor(ctx, parsed.Headers[0].KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var registered jwt.Claims
+	var claims map[string]any
+	verified := false
+	for _, key := range keys {
+		if err := parsed.Claims(key.Key, &registered, &claims); err == nil {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, fmt.Errorf("invalid token signature")
+	}
+
+	if registered.Expiry == nil {
+		return nil, fmt.Errorf("token has no expiration time")
+	}
+	expected := jwt.Expected{Issuer: v.issuer, Time: time.Now()}
+	if len(audiences) > 0 {
+		expected.AnyAudience = audiences
+	}
+	if err := registered.ValidateWithLeeway(expected, v.leeway); err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+	return claims, nil
+}
+
+// keysFor returns the keys that may have signed a token with the key ID kid.
+// The keys are fetched again once they expire, or if none matches at most
+// once per minRefreshInterval. A failed fetch is not retried before
+// minRefreshInterval either, and the cached keys are used meanwhile.
+func (v *Verifier) keysFor(ctx context.Context, kid string) ([]jose.JSONWebKey, error) {
+	c := v.match(kid)
+	expired := c.fetchedAt.IsZero() || time.Since(c.fetchedAt) > v.cacheTTL
+	unknown := len(c.keys) == 0 && time.Since(c.fetchedAt) > minRefreshInterval
+	backoff := !c.failedAt.IsZero() && time.Since(c.failedAt) <= minRefreshInterval
+	if (expired || unknown) && !backoff {
+		// a failed fetch is recorded in the cache
+		_ = v.refresh(ctx)
+		c = v.match(kid)
+	}
+	if c.fetchedAt.IsZero() {
+		return nil, c.fetchErr
+	}
+	if len(c.keys) == 0 {
+		return nil, fmt.Errorf("no key of issuer %q matches key ID %q", v.issuer, kid)
+	}
+	return c.keys, nil
+}
+
+// match returns the cached signing keys with the key ID kid, or every
+// signing key if kid is empty, and the state of the cache.
+func (v *Verifier) match(kid string) cache {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	candidates := v.keys.Keys
+	if kid != "" {
+		candidates = v.keys.Key(kid)
+	}
+	c := cache{fetchedAt: v.fetchedAt, failedAt: v.failedAt, fetchErr: v.fetchErr}
+	for _, key := range candidates {
+		if key.Use == "" || key.Use == "sig" {
+			c.keys = append(c.keys, key)
+		}
+	}
+	return c
+}
+
+// refresh fetches the keys of the issuer. Concurrent calls share a single
+// fetch, which is not cancelled with the context of the first caller.
+func (v *Verifier) refresh(ctx context.Context) error {
+	_, err, _ := v.fetches.Do("keys", func() (any, error) {
+		return nil, v.fetch(context.WithoutCancel(ctx))
+	})
+	return err
+}
+
+// fetch fetches the keys of the issuer, whose URL is discovered first if it
+// is not known. The previous keys are kept if it fails.
+func (v *Verifier) fetch(ctx context.Context) error {
+	v.mu.Lock()
+	jwksURL := v.jwksURL
+	v.mu.Unlock()
+	jwksURL, keys, err := v.fetchKeys(ctx, jwksURL)
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if err != nil {
+		v.failedAt, v.fetchErr = time.Now(), err
+		return err
+	}
+	v.jwksURL = jwksURL
+	v.keys = keys
+	v.fetchedAt = time.Now()
+	v.failedAt, v.fetchErr = time.Time{}, nil
+	return nil
+}
+
+// fetchKeys fetches the keys at jwksURL, which is discovered first if empty,
+// and returns the URL with the keys.
+func (v *Verifier) fetchKeys(ctx context.Context, jwksURL string) (string, jose.JSONWebKeySet, error) {
+	var keys jose.JSONWebKeySet
+	if jwksURL == "" {
+		var err error
+		jwksURL, err = v.discover(ctx)
+		if err != nil {
+			return "", keys, err
+		}
+	}
+	if err := v.get(ctx, jwksURL, &keys); err != nil {
+		return "", keys, fmt.Errorf("unable to fetch the keys of issuer %q: %w", v.issuer, err)
+	}
+	return jwksURL, keys, nil
+}
+
+// discover returns the JWKS URL from the metadata of the issuer. The issuer
+// of the metadata must be the configured issuer.
+func (v *Verifier) discover(ctx context.Context) (string, error) {
+	base := strings.TrimSuffix(v.issuer, "/")
+	var errs []string
+	for _, path := range discoveryPaths {
+		var metadata struct {
+			Issuer  string `json:"issuer"`
+			JwksURI string `json:"jwks_uri"`
+		}
+		if err := v.get(ctx, base+path, &metadata); err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		if metadata.Issuer != v.issuer {
+			errs = append(errs, fmt.Sprintf("%s has issuer %q", path, metadata.Issuer))
+			continue
+		}
+		if metadata.JwksURI == "" {
+			errs = append(errs, fmt.Sprintf("%s has no jwks_uri", path))
+			continue
+		}
+		return metadata.JwksURI, nil
+	}
+	return "", fmt.Errorf("unable to discover the keys of issuer %q: %s", v.issuer, strings.Join(errs, "; "))
+}
+
+// get decodes the JSON document at url into v.
+func (v *Verifier) get(ctx context.Context, url string, dest any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s returned status %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(dest)
+}
@@ -0,0 +1,221 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwks_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/auth/jwks"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+)
+
+func TestVerify(t *testing.T) {
+	issuer := testutils.NewIssuer(t)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unable to generate key: %s", err)
+	}
+	audiences := []string{"https://toolbox.example.com/mcp"}
+
+	tcs := []struct {
+		desc      string
+		token     string
+		audiences []string
+		err       string
+	}{
+		{
+			desc:      "valid token",
+			token:     issuer.Token(t, map[string]any{"sub": "alice", "aud": audiences[0]}),
+			audiences: audiences,
+		},
+		{
+			desc:  "no audience check",
+			token: issuer.Token(t, map[string]any{"sub": "alice"}),
+		},
+		{
+			desc:      "wrong audience",
+			token:     issuer.Token(t, map[string]any{"sub": "alice", "aud": "https://other.example.com"}),
+			audiences: audiences,
+			err:       "invalid token: go-jose/go-jose/jwt: validation failed, invalid audience claim (aud)",
+		},
+		{
+			desc:  "wrong issuer",
+			token: issuer.Token(t, map[string]any{"iss": "https://other.example.com"}),
+			err:   "invalid token: go-jose/go-jose/jwt: validation failed, invalid issuer claim (iss)",
+		},
+		{
+			desc:  "expired",
+			token: issuer.Token(t, map[string]any{"exp": time.Now().Add(-time.Hour).Unix()}),
+			err:   "invalid token: go-jose/go-jose/jwt: validation failed, token is expired (exp)",
+		},
+		{
+			desc:  "no expiration time",
+			token: issuer.Token(t, map[string]any{"exp": nil}),
+			err:   "token has no expiration time",
+		},
+		{
+			desc: "signed with another key",
+			token: testutils.SignToken(t, otherKey, "key-1", map[string]any{
+				"iss": issuer.URL,
+				"exp": time.Now().Add(time.Hour).Unix(),
+			}),
+			err: "invalid token signature",
+		},
+		{
+			desc:  "malformed",
+			token: "not-a-token",
+			err:   "malformed token",
+		},
+	}
+	verifier := jwks.NewVerifier(issuer.URL, "")
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			claims, err := verifier.Verify(context.Background(), tc.token, tc.audiences)
+			if tc.err != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tc.err) {
+					t.Fatalf("unexpected error: got %v, want %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if claims["sub"] != "alice" {
+				t.Fatalf("unexpected claims: %v", claims)
+			}
+		})
+	}
+	// the keys are fetched once and cached
+	if got := issuer.JWKSRequests(); got != 1 {
+		t.Fatalf("unexpected number of key requests: got %d, want 1", got)
+	}
+}
+
+func TestVerifyUnknownKey(t *testing.T) {
+	issuer := testutils.NewIssuer(t)
+	verifier := jwks.NewVerifier(issuer.URL, issuer.URL+"/jwks")
+	if _, err := verifier.Verify(context.Background(), issuer.Token(t, nil), nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// the keys are not fetched again right after they were fetched
+	issuer.RotateKey(t)
+	_, err := verifier.Verify(context.Background(), issuer.Token(t, nil), nil)
+	want := "no key of issuer"
+	if err == nil || !strings.HasPrefix(err.Error(), want) {
+		t.Fatalf("unexpected error: got %v, want %q", err, want)
+	}
+	if got := issuer.JWKSRequests(); got != 1 {
+		t.Fatalf("unexpected number of key requests: got %d, want 1", got)
+	}
+}
+
+func TestVerifyIssuerUnavailable(t *testing.T) {
+	issuer := testutils.NewIssuer(t)
+	verifier := jwks.NewVerifier(issuer.URL, issuer.URL+"/jwks").WithCacheTTL(time.Millisecond)
+	token := issuer.Token(t, nil)
+	if _, err := verifier.Verify(context.Background(), token, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// the cached keys are used once they expire while the issuer is down
+	issuer.SetUnavailable(true)
+	time.Sleep(2 * time.Millisecond)
+	for range 3 {
+		if _, err := verifier.Verify(context.Background(), token, nil); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	// the failed fetch is not retried until minRefreshInterval passes
+	if got := issuer.JWKSRequests(); got != 2 {
+		t.Fatalf("unexpected number of key requests: got %d, want 2", got)
+	}
+}
+
+func TestVerifyFetchFailureBackoff(t *testing.T) {
+	issuer := testutils.NewIssuer(t)
+	issuer.SetUnavailable(true)
+	verifier := jwks.NewVerifier(issuer.URL, issuer.URL+"/jwks")
+	token := issuer.Token(t, nil)
+	for range 3 {
+		_, err := verifier.Verify(context.Background(), token, nil)
+		want := "unable to fetch the keys of issuer"
+		if err == nil || !strings.HasPrefix(err.Error(), want) {
+			t.Fatalf("unexpected error: got %v, want %q", err, want)
+		}
+	}
+	if got := issuer.JWKSRequests(); got != 1 {
+		t.Fatalf("unexpected number of key requests: got %d, want 1", got)
+	}
+}
+
+func TestVerifyDiscoveryFailure(t *testing.T) {
+	issuer := testutils.NewIssuer(t)
+	verifier := jwks.NewVerifier(issuer.URL+"/unknown", "")
+	_, err := verifier.Verify(context.Background(), issuer.Token(t, nil), nil)
+	want := "unable to discover the keys of issuer"
+	if err == nil || !strings.HasPrefix(err.Error(), want) {
+		t.Fatalf("unexpected error: got %v, want %q", err, want)
+	}
+}
+
+func TestVerifyDiscoveryIssuerMismatch(t *testing.T) {
+	issuer := testutils.NewIssuer(t)
+	// the metadata is served for the issuer without the trailing slash
+	verifier := jwks.NewVerifier(issuer.URL+"/", "")
+	_, err := verifier.Verify(context.Background(), issuer.Token(t, map[string]any{"iss": issuer.URL + "/"}), nil)
+	want := "unable to discover the keys of issuer"
+	if err == nil || !strings.HasPrefix(err.Error(), want) || !strings.Contains(err.Error(), fmt.Sprintf("has issuer %q", issuer.URL)) {
+		t.Fatalf("unexpected error: got %v, want %q", err, want)
+	}
+	if got := issuer.JWKSRequests(); got != 0 {
+		t.Fatalf("unexpected number of key requests: got %d, want 0", got)
+	}
+}
+
+func TestVerifyConcurrent(t *testing.T) {
+	issuer := testutils.NewIssuer(t)
+	verifier := jwks.NewVerifier(issuer.URL, "")
+	token := issuer.Token(t, nil)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := verifier.Verify(context.Background(), token, nil)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	// concurrent verifications share the fetch of the keys
+	if got := issuer.JWKSRequests(); got != 1 {
+		t.Fatalf("unexpected number of key requests: got %d, want 1", got)
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mtls
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+const AuthServiceType string = "mtls"
+
+// spiffeScheme is the scheme of the URI SAN that holds the SPIFFE ID of a
+// workload.
+const spiffeScheme = "spiffe"
+
+func init() {
+	if !auth.Register(AuthServiceType, newConfig) {
+		panic(fmt.Sprintf("auth service type %q already registered", AuthServiceType))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (auth.AuthServiceConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+// validate interface
+var _ auth.AuthServiceConfig = Config{}
+
+// Auth service configuration
+type Config struct {
+	Name string `yaml:"name" validate:"required"`
+	Type string `yaml:"type" validate:"required"`
+}
+
+// Returns the auth service type
+func (cfg Config) AuthServiceConfigType() string {
+	return AuthServiceType
+}
+
+// Initialize a mTLS auth service
+func (cfg Config) Initialize() (auth.AuthService, error) {
+	a := &AuthService{
+		Config: cfg,
+	}
+	return a, nil
+}
+
+var _ auth.AuthService = AuthService{}
+
+// struct used to store auth service info
+type AuthService struct {
+	Config
+}
+
+// Returns the auth service type
+func (a AuthService) AuthServiceType() string {
+	return AuthServiceType
+}
+
+func (a AuthService) ToConfig() auth.AuthServiceConfig {
+	return a.Config
+}
+
+// Returns the name of the auth service
+func (a AuthService) GetName() string {
+	return a.Name
+}
+
+// Returns the claims of the client certificate verified by the server. The
+// certificate is not carried by the header, but by the TLS connection of
+// the request.
+func (a AuthService) GetClaimsFromHeader(ctx context.Context, _ http.Header) (map[string]any, error) {
+	state := util.TLSConnectionStateFromContext(ctx)
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return nil, nil
+	}
+	if len(state.VerifiedChains) == 0 {
+		return nil, fmt.Errorf("mTLS verification failure: client certificate was not verified")
+	}
+	cert := state.VerifiedChains[0][0]
+
+	dnsNames := make([]any, 0, len(cert.DNSNames))
+	for _, n := range cert.DNSNames {
+		dnsNames = append(dnsNames, n)
+	}
+	emails := make([]any, 0, len(cert.EmailAddresses))
+	for _, e := range cert.EmailAddresses {
+		emails = append(emails, e)
+	}
+	ips := make([]any, 0, len(cert.IPAddresses))
+	for _, ip := range cert.IPAddresses {
+		ips = append(ips, ip.String())
+	}
+	uris := make([]any, 0, len(cert.URIs))
+	claims := map[string]any{
+		"subject":        cert.Subject.String(),
+		"commonName":     cert.Subject.CommonName,
+		"issuer":         cert.Issuer.String(),
+		"serialNumber":   cert.SerialNumber.String(),
+		"dnsNames":       dnsNames,
+		"emailAddresses": emails,
+		"ipAddresses":    ips,
+	}
+	for _, u := range cert.URIs {
+		uris = append(uris, u.String())
+		// a SPIFFE certificate has exactly one SPIFFE ID
+		if u.Scheme == spiffeScheme {
+			claims["spiffeId"] = u.String()
+		}
+	}
+	claims["uris"] = uris
+	return claims, nil
+}
@@ -0,0 +1,109 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mtls_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/auth/mtls"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+func TestGetClaimsFromHeader(t *testing.T) {
+	ca := testutils.NewCertificateAuthority(t)
+	spiffeID, _ := url.Parse("spiffe://example.org/ns/data/sa/reporting")
+	client := ca.Issue(t, &x509.Certificate{
+		Subject:        pkix.Name{CommonName: "reporting", Organization: []string{"Example"}},
+		DNSNames:       []string{"reporting.example.org"},
+		EmailAddresses: []string{"reporting@example.org"},
+		IPAddresses:    []net.IP{net.ParseIP("10.0.0.1")},
+		URIs:           []*url.URL{spiffeID},
+	})
+
+	a, err := mtls.Config{Name: "my-mtls", Type: mtls.AuthServiceType}.Initialize()
+	if err != nil {
+		t.Fatalf("unable to initialize auth service: %s", err)
+	}
+
+	tcs := []struct {
+		desc  string
+		state *tls.ConnectionState
+		want  map[string]any
+		err   bool
+	}{
+		{
+			desc: "no TLS",
+		},
+		{
+			desc:  "no client certificate",
+			state: &tls.ConnectionState{},
+		},
+		{
+			desc: "verified client certificate",
+			state: &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{client.Cert},
+				VerifiedChains:   [][]*x509.Certificate{{client.Cert, ca.Cert}},
+			},
+			want: map[string]any{
+				"subject":        "CN=reporting,O=Example",
+				"commonName":     "reporting",
+				"issuer":         "CN=Test CA",
+				"serialNumber":   client.Cert.SerialNumber.String(),
+				"dnsNames":       []any{"reporting.example.org"},
+				"emailAddresses": []any{"reporting@example.org"},
+				"ipAddresses":    []any{"10.0.0.1"},
+				"uris":           []any{"spiffe://example.org/ns/data/sa/reporting"},
+				"spiffeId":       "spiffe://example.org/ns/data/sa/reporting",
+			},
+		},
+		{
+			desc: "unverified client certificate",
+			state: &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{client.Cert},
+			},
+			err: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.state != nil {
+				ctx = util.WithTLSConnectionState(ctx, tc.state)
+			}
+			claims, err := a.GetClaimsFromHeader(ctx, http.Header{})
+			if tc.err {
+				if err == nil {
+					t.Fatalf("expected error, got claims %v", claims)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, claims); diff != "" {
+				t.Fatalf("unexpected claims (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
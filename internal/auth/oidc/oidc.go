@@ -0,0 +1,123 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/auth/jwks"
+)
+
+const AuthServiceType string = "oidc"
+
+func init() {
+	if !auth.Register(AuthServiceType, newConfig) {
+		panic(fmt.Sprintf("auth service type %q already registered", AuthServiceType))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (auth.AuthServiceConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+// validate interface
+var _ auth.AuthServiceConfig = Config{}
+
+// Auth service configuration
+type Config struct {
+	Name   string `yaml:"name" validate:"required"`
+	Type   string `yaml:"type" validate:"required"`
+	Issuer string `yaml:"issuer" validate:"required"`
+	// JwksURL is discovered from the metadata of the issuer if empty.
+	JwksURL   string   `yaml:"jwksUrl"`
+	Audiences []string `yaml:"audiences" validate:"required,min=1"`
+	// ClockSkew is the tolerated clock skew, e.g. "30s". Defaults to 1m.
+	ClockSkew string `yaml:"clockSkew"`
+}
+
+// Returns the auth service type
+func (cfg Config) AuthServiceConfigType() string {
+	return AuthServiceType
+}
+
+// Initialize an OIDC auth service
+func (cfg Config) Initialize() (auth.AuthService, error) {
+	leeway := jwks.DefaultLeeway
+	if cfg.ClockSkew != "" {
+		d, err := time.ParseDuration(cfg.ClockSkew)
+		if err != nil {
+			return nil, fmt.Errorf("invalid clockSkew %q: %w", cfg.ClockSkew, err)
+		}
+		if d < 0 {
+			return nil, fmt.Errorf("clockSkew must not be negative: %q", cfg.ClockSkew)
+		}
+		leeway = d
+	}
+	a := &AuthService{
+		Config:   cfg,
+		verifier: jwks.NewVerifier(cfg.Issuer, cfg.JwksURL).WithLeeway(leeway),
+	}
+	return a, nil
+}
+
+var _ auth.AuthService = AuthService{}
+
+// struct used to store auth service info
+type AuthService struct {
+	Config
+	// verifier caches the keys of the issuer across requests
+	verifier *jwks.Verifier
+}
+
+// Returns the auth service type
+func (a AuthService) AuthServiceType() string {
+	return AuthServiceType
+}
+
+func (a AuthService) ToConfig() auth.AuthServiceConfig {
+	return a.Config
+}
+
+// Returns the name of the auth service
+func (a AuthService) GetName() string {
+	return a.Name
+}
+
+// Verifies the JWT from the issuer and return claims
+func (a AuthService) GetClaimsFromHeader(ctx context.Context, h http.Header) (map[string]any, error) {
+	token := h.Get(a.Name + "_token")
+	if token == "" {
+		return nil, nil
+	}
+	// the token may be sent as a bearer token
+	if prefix := "bearer "; len(token) > len(prefix) && strings.EqualFold(token[:len(prefix)], prefix) {
+		token = token[len(prefix):]
+	}
+	claims, err := a.verifier.Verify(ctx, token, a.Audiences)
+	if err != nil {
+		return nil, fmt.Errorf("OIDC token verification failure: %w", err)
+	}
+	return claims, nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/auth/oidc"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+)
+
+func TestGetClaimsFromHeader(t *testing.T) {
+	issuer := testutils.NewIssuer(t)
+	cfg := oidc.Config{
+		Name:      "my-oidc",
+		Type:      oidc.AuthServiceType,
+		Issuer:    issuer.URL,
+		Audiences: []string{"toolbox"},
+		ClockSkew: "10s",
+	}
+	a, err := cfg.Initialize()
+	if err != nil {
+		t.Fatalf("unable to initialize auth service: %s", err)
+	}
+
+	tcs := []struct {
+		desc   string
+		header http.Header
+		want   map[string]any
+		err    string
+	}{
+		{
+			desc:   "no token",
+			header: http.Header{},
+		},
+		{
+			desc:   "valid token",
+			header: http.Header{"My-Oidc_token": {issuer.Token(t, map[string]any{"aud": "toolbox", "email": "alice@example.com"})}},
+			want:   map[string]any{"email": "alice@example.com"},
+		},
+		{
+			desc:   "bearer token",
+			header: http.Header{"My-Oidc_token": {"Bearer " + issuer.Token(t, map[string]any{"aud": "toolbox", "email": "alice@example.com"})}},
+			want:   map[string]any{"email": "alice@example.com"},
+		},
+		{
+			desc:   "wrong audience",
+			header: http.Header{"My-Oidc_token": {issuer.Token(t, map[string]any{"aud": "other"})}},
+			err:    "OIDC token verification failure: invalid token",
+		},
+		{
+			desc:   "expired beyond clock skew",
+			header: http.Header{"My-Oidc_token": {issuer.Token(t, map[string]any{"aud": "toolbox", "exp": time.Now().Add(-time.Minute).Unix()})}},
+			err:    "OIDC token verification failure: invalid token",
+		},
+		{
+			desc:   "expired within clock skew",
+			header: http.Header{"My-Oidc_token": {issuer.Token(t, map[string]any{"aud": "toolbox", "email": "alice@example.com", "exp": time.Now().Add(-5 * time.Second).Unix()})}},
+			want:   map[string]any{"email": "alice@example.com"},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			claims, err := a.GetClaimsFromHeader(context.Background(), tc.header)
+			if tc.err != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tc.err) {
+					t.Fatalf("unexpected error: got %v, want %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tc.want == nil {
+				if claims != nil {
+					t.Fatalf("unexpected claims: %v", claims)
+				}
+				return
+			}
+			for k, v := range tc.want {
+				if claims[k] != v {
+					t.Fatalf("unexpected claim %q: got %v, want %v", k, claims[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestInitializeInvalidClockSkew(t *testing.T) {
+	cfg := oidc.Config{Name: "my-oidc", Type: oidc.AuthServiceType, Issuer: "https://example.com", Audiences: []string{"toolbox"}, ClockSkew: "soon"}
+	if _, err := cfg.Initialize(); err == nil {
+		t.Fatalf("expected error for invalid clock skew")
+	}
+}
@@ -16,10 +16,44 @@ package embeddingmodels
 
 import (
 	"context"
+	"fmt"
 	"strconv"
 	"strings"
+
+	"github.com/goccy/go-yaml"
 )
 
+// EmbeddingModelConfigFactory defines the function signature for creating an
+// EmbeddingModelConfig.
+type EmbeddingModelConfigFactory func(ctx context.Context, name string, decoder *yaml.Decoder) (EmbeddingModelConfig, error)
+
+var embeddingModelRegistry = make(map[string]EmbeddingModelConfigFactory)
+
+// Register registers a new embedding model type with its factory.
+// It returns false if the type is already registered.
+func Register(embeddingModelType string, factory EmbeddingModelConfigFactory) bool {
+	if _, exists := embeddingModelRegistry[embeddingModelType]; exists {
+		// Embedding model with this type already exists, do not overwrite.
+		return false
+	}
+	embeddingModelRegistry[embeddingModelType] = factory
+	return true
+}
+
+// DecodeConfig decodes an embedding model configuration using the registered
+// factory for the given type.
+func DecodeConfig(ctx context.Context, embeddingModelType string, name string, decoder *yaml.Decoder) (EmbeddingModelConfig, error) {
+	factory, found := embeddingModelRegistry[embeddingModelType]
+	if !found {
+		return nil, fmt.Errorf("%s is not a valid type of embedding model", embeddingModelType)
+	}
+	embeddingModelConfig, err := factory(ctx, name, decoder)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse as %q: %w", name, err)
+	}
+	return embeddingModelConfig, nil
+}
+
 // EmbeddingModelConfig is the interface for configuring embedding models.
 type EmbeddingModelConfig interface {
 	EmbeddingModelConfigType() string
@@ -0,0 +1,80 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Forwarder receives the records logged with a context that carries it, such
+// as the records emitted while handling the request of an MCP client.
+type Forwarder func(ctx context.Context, level slog.Level, msg string, keysAndValues ...any)
+
+type forwarderKey struct{}
+
+// WithForwarder adds a Forwarder to the context
+func WithForwarder(ctx context.Context, forwarder Forwarder) context.Context {
+	return context.WithValue(ctx, forwarderKey{}, forwarder)
+}
+
+// ForwardingLogger is a Logger that passes every record to the Forwarder of
+// the record's context, in addition to logging it.
+type ForwardingLogger struct {
+	Logger
+}
+
+// NewForwardingLogger creates a Logger that logs to logger and forwards the
+// records to the Forwarder of their context, if any. Records are forwarded
+// regardless of the level of logger.
+func NewForwardingLogger(logger Logger) Logger {
+	if fl, ok := logger.(*ForwardingLogger); ok {
+		return fl
+	}
+	return &ForwardingLogger{Logger: logger}
+}
+
+func (fl *ForwardingLogger) forward(ctx context.Context, level slog.Level, msg string, keysAndValues ...any) {
+	forwarder, ok := ctx.Value(forwarderKey{}).(Forwarder)
+	if !ok || forwarder == nil {
+		return
+	}
+	// records logged by the forwarder itself are not forwarded again
+	forwarder(WithForwarder(ctx, nil), level, msg, keysAndValues...)
+}
+
+// DebugContext logs debug messages
+func (fl *ForwardingLogger) DebugContext(ctx context.Context, msg string, keysAndValues ...any) {
+	fl.Logger.DebugContext(ctx, msg, keysAndValues...)
+	fl.forward(ctx, slog.LevelDebug, msg, keysAndValues...)
+}
+
+// InfoContext logs info messages
+func (fl *ForwardingLogger) InfoContext(ctx context.Context, msg string, keysAndValues ...any) {
+	fl.Logger.InfoContext(ctx, msg, keysAndValues...)
+	fl.forward(ctx, slog.LevelInfo, msg, keysAndValues...)
+}
+
+// WarnContext logs warning messages
+func (fl *ForwardingLogger) WarnContext(ctx context.Context, msg string, keysAndValues ...any) {
+	fl.Logger.WarnContext(ctx, msg, keysAndValues...)
+	fl.forward(ctx, slog.LevelWarn, msg, keysAndValues...)
+}
+
+// ErrorContext logs error messages
+func (fl *ForwardingLogger) ErrorContext(ctx context.Context, msg string, keysAndValues ...any) {
+	fl.Logger.ErrorContext(ctx, msg, keysAndValues...)
+	fl.forward(ctx, slog.LevelError, msg, keysAndValues...)
+}
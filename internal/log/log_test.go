@@ -483,3 +483,50 @@ func TestStructuredLoggerDebugLog(t *testing.T) {
 		})
 	}
 }
+
+func TestForwardingLogger(t *testing.T) {
+	type record struct {
+		level         slog.Level
+		msg           string
+		keysAndValues []any
+	}
+	var got []record
+	forwarder := func(ctx context.Context, level slog.Level, msg string, keysAndValues ...any) {
+		got = append(got, record{level: level, msg: msg, keysAndValues: keysAndValues})
+	}
+
+	var out, errOut bytes.Buffer
+	base, err := NewStdLogger(&out, &errOut, "error")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	logger := NewForwardingLogger(base)
+	if NewForwardingLogger(logger) != logger {
+		t.Fatalf("expected forwarding logger not to be wrapped twice")
+	}
+
+	// records without a forwarder are only logged
+	logger.ErrorContext(context.Background(), "not forwarded")
+	if len(got) != 0 {
+		t.Fatalf("unexpected forwarded records: %v", got)
+	}
+
+	// records are forwarded regardless of the level of the base logger
+	ctx := WithForwarder(context.Background(), forwarder)
+	logger.DebugContext(ctx, "debug", "key", "value")
+	logger.InfoContext(ctx, "info")
+	logger.WarnContext(ctx, "warn")
+	logger.ErrorContext(ctx, "error")
+	want := []record{
+		{level: slog.LevelDebug, msg: "debug", keysAndValues: []any{"key", "value"}},
+		{level: slog.LevelInfo, msg: "info"},
+		{level: slog.LevelWarn, msg: "warn"},
+		{level: slog.LevelError, msg: "error"},
+	}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(record{})); diff != "" {
+		t.Fatalf("unexpected forwarded records (-want +got):\n%s", diff)
+	}
+	if out.Len() != 0 || !strings.Contains(errOut.String(), "not forwarded") {
+		t.Fatalf("unexpected base logger output: %q, %q", out.String(), errOut.String())
+	}
+}
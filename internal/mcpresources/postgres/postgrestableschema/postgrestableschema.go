@@ -0,0 +1,150 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgrestableschema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/mcpresources"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util/parameters"
+)
+
+const resourceType string = "postgres-table-schema"
+
+const listColumnsStatement = `
+	SELECT
+		column_name,
+		data_type,
+		is_nullable,
+		column_default
+	FROM information_schema.columns
+	WHERE table_schema = $1 AND table_name = $2
+	ORDER BY ordinal_position
+`
+
+func init() {
+	if !mcpresources.Register(resourceType, newConfig) {
+		panic(fmt.Sprintf("resource type %q already registered", resourceType))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (mcpresources.ResourceConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	RunSQL(context.Context, string, []any) (any, error)
+}
+
+type Config struct {
+	Name        string `yaml:"name" validate:"required"`
+	Type        string `yaml:"type" validate:"required"`
+	Source      string `yaml:"source" validate:"required"`
+	Description string `yaml:"description"`
+	// Completions configures the values suggested for the "schema" and
+	// "table" variables of the URI template.
+	Completions map[string]parameters.Completion `yaml:"completions"`
+}
+
+var _ mcpresources.ResourceConfig = Config{}
+
+func (cfg Config) ResourceConfigType() string {
+	return resourceType
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (mcpresources.Resource, error) {
+	for variable := range cfg.Completions {
+		if variable != "schema" && variable != "table" {
+			return nil, fmt.Errorf("invalid completion for %q: the URI template only has the variables \"schema\" and \"table\"", variable)
+		}
+	}
+	description := cfg.Description
+	if description == "" {
+		description = fmt.Sprintf("Column definitions of a table in the %q source.", cfg.Source)
+	}
+	r := Resource{
+		Config: cfg,
+		mcpManifest: mcpresources.McpManifest{
+			URITemplate: fmt.Sprintf("postgres://%s/{schema}/{table}", cfg.Source),
+			Name:        cfg.Name,
+			Description: description,
+			MimeType:    "application/json",
+		},
+	}
+	return r, nil
+}
+
+var _ mcpresources.Resource = Resource{}
+var _ mcpresources.TemplateCompleter = Resource{}
+
+type Resource struct {
+	Config
+	mcpManifest mcpresources.McpManifest
+}
+
+func (r Resource) McpManifest() mcpresources.McpManifest {
+	return r.mcpManifest
+}
+
+func (r Resource) Read(ctx context.Context, resourceMgr tools.SourceProvider, uri string) ([]mcpresources.Contents, error) {
+	values, ok := mcpresources.MatchURITemplate(r.mcpManifest.URITemplate, uri)
+	if !ok {
+		return nil, fmt.Errorf("uri %q does not match %q", uri, r.mcpManifest.URITemplate)
+	}
+	source, err := tools.GetCompatibleSource[compatibleSource](resourceMgr, r.Source, r.Name, r.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := source.RunSQL(ctx, listColumnsStatement, []any{values["schema"], values["table"]})
+	if err != nil {
+		return nil, err
+	}
+	if rows, ok := resp.([]any); !ok || len(rows) == 0 {
+		return nil, fmt.Errorf("table %q.%q does not exist", values["schema"], values["table"])
+	}
+	text, err := json.Marshal(resp)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal table schema: %w", err)
+	}
+	return []mcpresources.Contents{
+		{
+			URI:      uri,
+			MimeType: r.mcpManifest.MimeType,
+			Text:     string(text),
+		},
+	}, nil
+}
+
+func (r Resource) GetCompletion(variable string) *parameters.Completion {
+	completion, ok := r.Completions[variable]
+	if !ok {
+		return nil
+	}
+	return &completion
+}
+
+func (r Resource) ToConfig() mcpresources.ResourceConfig {
+	return r.Config
+}
@@ -0,0 +1,146 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgrestableschema_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/mcpresources"
+	"github.com/googleapis/genai-toolbox/internal/mcpresources/postgres/postgrestableschema"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/util/parameters"
+)
+
+func TestParseFromYamlPostgresTableSchema(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ResourceConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+            kind: resources
+            name: table_schema
+            type: postgres-table-schema
+            source: my-pg-instance
+            description: some description
+			`,
+			want: server.ResourceConfigs{
+				"table_schema": postgrestableschema.Config{
+					Name:        "table_schema",
+					Type:        "postgres-table-schema",
+					Source:      "my-pg-instance",
+					Description: "some description",
+				},
+			},
+		},
+		{
+			desc: "with completions",
+			in: `
+            kind: resources
+            name: table_schema
+            type: postgres-table-schema
+            source: my-pg-instance
+            completions:
+              schema:
+                values:
+                  - public
+              table:
+                tool: list_tables
+			`,
+			want: server.ResourceConfigs{
+				"table_schema": postgrestableschema.Config{
+					Name:   "table_schema",
+					Type:   "postgres-table-schema",
+					Source: "my-pg-instance",
+					Completions: map[string]parameters.Completion{
+						"schema": {Values: []any{"public"}},
+						"table":  {Tool: "list_tables"},
+					},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			// Parse contents
+			got, err := server.UnmarshalFileConfigs(ctx, testutils.FormatYaml(tc.in))
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Resources); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestInitializePostgresTableSchema(t *testing.T) {
+	cfg := postgrestableschema.Config{
+		Name:   "table_schema",
+		Type:   "postgres-table-schema",
+		Source: "my-pg-instance",
+	}
+	r, err := cfg.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := mcpresources.McpManifest{
+		URITemplate: "postgres://my-pg-instance/{schema}/{table}",
+		Name:        "table_schema",
+		Description: `Column definitions of a table in the "my-pg-instance" source.`,
+		MimeType:    "application/json",
+	}
+	if diff := cmp.Diff(want, r.McpManifest()); diff != "" {
+		t.Fatalf("incorrect manifest: diff %v", diff)
+	}
+}
+
+func TestInitializePostgresTableSchemaCompletions(t *testing.T) {
+	cfg := postgrestableschema.Config{
+		Name:   "table_schema",
+		Type:   "postgres-table-schema",
+		Source: "my-pg-instance",
+		Completions: map[string]parameters.Completion{
+			"table": {Tool: "list_tables"},
+		},
+	}
+	r, err := cfg.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	completer, ok := r.(mcpresources.TemplateCompleter)
+	if !ok {
+		t.Fatalf("resource does not implement TemplateCompleter")
+	}
+	if diff := cmp.Diff(&parameters.Completion{Tool: "list_tables"}, completer.GetCompletion("table")); diff != "" {
+		t.Fatalf("incorrect completion: diff %v", diff)
+	}
+	if got := completer.GetCompletion("schema"); got != nil {
+		t.Fatalf("unexpected completion for schema: %v", got)
+	}
+
+	cfg.Completions = map[string]parameters.Completion{"column": {Tool: "list_columns"}}
+	if _, err := cfg.Initialize(nil); err == nil {
+		t.Fatalf("expected error for a completion of an unknown variable")
+	}
+}
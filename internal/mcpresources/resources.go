@@ -0,0 +1,203 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mcpresources contains the MCP resources that can be exposed by
+// Toolbox, such as table schemas of a source. Resources are either concrete
+// (identified by a fixed URI) or templates (identified by an RFC 6570 URI
+// template).
+package mcpresources
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util/parameters"
+)
+
+// ResourceConfigFactory defines the signature for a function that creates and
+// decodes a specific resource's configuration.
+type ResourceConfigFactory func(ctx context.Context, name string, decoder *yaml.Decoder) (ResourceConfig, error)
+
+var resourceRegistry = make(map[string]ResourceConfigFactory)
+
+// Register allows individual resource packages to register their configuration
+// factory function. This is typically called from an init() function in the
+// resource's package. It associates a 'type' string with a function that can
+// produce the specific ResourceConfig type. It returns true if the registration
+// was successful, and false if a resource with the same type was already
+// registered.
+func Register(resourceType string, factory ResourceConfigFactory) bool {
+	if _, exists := resourceRegistry[resourceType]; exists {
+		// Resource with this type already exists, do not overwrite.
+		return false
+	}
+	resourceRegistry[resourceType] = factory
+	return true
+}
+
+// DecodeConfig looks up the registered factory for the given type and uses it
+// to decode the resource configuration.
+func DecodeConfig(ctx context.Context, resourceType string, name string, decoder *yaml.Decoder) (ResourceConfig, error) {
+	factory, found := resourceRegistry[resourceType]
+	if !found {
+		return nil, fmt.Errorf("unknown resource type: %q", resourceType)
+	}
+	resourceConfig, err := factory(ctx, name, decoder)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse resource %q as type %q: %w", name, resourceType, err)
+	}
+	return resourceConfig, nil
+}
+
+type ResourceConfig interface {
+	ResourceConfigType() string
+	Initialize(map[string]sources.Source) (Resource, error)
+}
+
+type Resource interface {
+	// McpManifest returns the definition of the resource. Resource templates
+	// set URITemplate instead of URI.
+	McpManifest() McpManifest
+	// Read returns the contents of the resource identified by uri. For
+	// resource templates, uri matches the template.
+	Read(ctx context.Context, resourceMgr tools.SourceProvider, uri string) ([]Contents, error)
+	ToConfig() ResourceConfig
+}
+
+// TemplateCompleter is implemented by resource templates that can suggest
+// values for the variables of their URI template.
+type TemplateCompleter interface {
+	// GetCompletion returns how values are suggested for the variable, or nil
+	// if no suggestions are available.
+	GetCompletion(variable string) *parameters.Completion
+}
+
+// McpManifest is the definition for a resource or resource template the MCP
+// client can read.
+type McpManifest struct {
+	// URI of the resource. Empty for resource templates.
+	URI string `json:"uri,omitempty"`
+	// URITemplate is an RFC 6570 URI template used to construct resource URIs.
+	// Empty for concrete resources.
+	URITemplate string `json:"uriTemplate,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+// IsTemplate returns true if the manifest describes a resource template.
+func (m McpManifest) IsTemplate() bool {
+	return m.URITemplate != ""
+}
+
+// Matches returns true if the uri identifies this resource or, for templates,
+// if the uri can be expanded from the template.
+func (m McpManifest) Matches(uri string) bool {
+	if !m.IsTemplate() {
+		return m.URI == uri
+	}
+	_, ok := MatchURITemplate(m.URITemplate, uri)
+	return ok
+}
+
+// Contents is the contents of a specific resource. Exactly one of Text or
+// Blob (base64-encoded) is set.
+type Contents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text,omitempty"`
+	Blob     string `json:"blob,omitempty"`
+}
+
+var templateVariable = regexp.MustCompile(`\{([a-zA-Z0-9_]+)\}`)
+
+// MatchURITemplate matches uri against a simple (level 1) RFC 6570 URI
+// template and returns the values of the template variables. Each variable
+// matches a single non-empty path segment, whose value is percent-decoded.
+func MatchURITemplate(template, uri string) (map[string]string, bool) {
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	names := []string{}
+	last := 0
+	for _, loc := range templateVariable.FindAllStringSubmatchIndex(template, -1) {
+		pattern.WriteString(regexp.QuoteMeta(template[last:loc[0]]))
+		pattern.WriteString(`([^/?#]+)`)
+		names = append(names, template[loc[2]:loc[3]])
+		last = loc[1]
+	}
+	pattern.WriteString(regexp.QuoteMeta(template[last:]))
+	pattern.WriteString("$")
+
+	re, err := regexp.Compile(pattern.String())
+	if err != nil {
+		return nil, false
+	}
+	matches := re.FindStringSubmatch(uri)
+	if matches == nil {
+		return nil, false
+	}
+	values := make(map[string]string, len(names))
+	for i, name := range names {
+		v, err := url.PathUnescape(matches[i+1])
+		if err != nil {
+			return nil, false
+		}
+		values[name] = v
+	}
+	return values, true
+}
+
+// Manifests returns the manifests of the resources sorted by name, split into
+// concrete resources and resource templates.
+func Manifests(resourcesMap map[string]Resource) ([]McpManifest, []McpManifest) {
+	resources := make([]McpManifest, 0)
+	templates := make([]McpManifest, 0)
+	for _, name := range slices.Sorted(maps.Keys(resourcesMap)) {
+		m := resourcesMap[name].McpManifest()
+		if m.IsTemplate() {
+			templates = append(templates, m)
+		} else {
+			resources = append(resources, m)
+		}
+	}
+	return resources, templates
+}
+
+// Find returns the resource identified by uri. Concrete resources take
+// precedence over resource templates.
+func Find(resourcesMap map[string]Resource, uri string) (Resource, bool) {
+	var match Resource
+	for _, name := range slices.Sorted(maps.Keys(resourcesMap)) {
+		r := resourcesMap[name]
+		m := r.McpManifest()
+		if !m.Matches(uri) {
+			continue
+		}
+		if !m.IsTemplate() {
+			return r, true
+		}
+		if match == nil {
+			match = r
+		}
+	}
+	return match, match != nil
+}
@@ -0,0 +1,160 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcpresources_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/mcpresources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+type mockResource struct {
+	manifest mcpresources.McpManifest
+}
+
+func (r mockResource) McpManifest() mcpresources.McpManifest {
+	return r.manifest
+}
+
+func (r mockResource) Read(context.Context, tools.SourceProvider, string) ([]mcpresources.Contents, error) {
+	return nil, nil
+}
+
+func (r mockResource) ToConfig() mcpresources.ResourceConfig {
+	return nil
+}
+
+func TestMatchURITemplate(t *testing.T) {
+	tcs := []struct {
+		desc     string
+		template string
+		uri      string
+		want     map[string]string
+		wantOk   bool
+	}{
+		{
+			desc:     "matches variables",
+			template: "postgres://my-pg/{schema}/{table}",
+			uri:      "postgres://my-pg/public/orders",
+			want:     map[string]string{"schema": "public", "table": "orders"},
+			wantOk:   true,
+		},
+		{
+			desc:     "decodes variables",
+			template: "postgres://my-pg/{schema}/{table}",
+			uri:      "postgres://my-pg/public/order%20items",
+			want:     map[string]string{"schema": "public", "table": "order items"},
+			wantOk:   true,
+		},
+		{
+			desc:     "invalid escape",
+			template: "postgres://my-pg/{schema}/{table}",
+			uri:      "postgres://my-pg/public/orders%zz",
+			wantOk:   false,
+		},
+		{
+			desc:     "no variables",
+			template: "file:///docs/readme",
+			uri:      "file:///docs/readme",
+			want:     map[string]string{},
+			wantOk:   true,
+		},
+		{
+			desc:     "variable does not span segments",
+			template: "postgres://my-pg/{schema}/{table}",
+			uri:      "postgres://my-pg/public/orders/extra",
+			wantOk:   false,
+		},
+		{
+			desc:     "empty variable",
+			template: "postgres://my-pg/{schema}/{table}",
+			uri:      "postgres://my-pg/public/",
+			wantOk:   false,
+		},
+		{
+			desc:     "literal characters are escaped",
+			template: "mock://a.b/{name}",
+			uri:      "mock://aXb/foo",
+			wantOk:   false,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, ok := mcpresources.MatchURITemplate(tc.template, tc.uri)
+			if ok != tc.wantOk {
+				t.Fatalf("unexpected match result: got %t, want %t", ok, tc.wantOk)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect variables: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestManifestsAndFind(t *testing.T) {
+	readme := mockResource{manifest: mcpresources.McpManifest{URI: "mock://docs/readme", Name: "readme"}}
+	docs := mockResource{manifest: mcpresources.McpManifest{URITemplate: "mock://docs/{name}", Name: "docs"}}
+	tables := mockResource{manifest: mcpresources.McpManifest{URITemplate: "mock://tables/{name}", Name: "tables"}}
+	resourcesMap := map[string]mcpresources.Resource{
+		"readme": readme,
+		"docs":   docs,
+		"tables": tables,
+	}
+
+	gotResources, gotTemplates := mcpresources.Manifests(resourcesMap)
+	if diff := cmp.Diff([]mcpresources.McpManifest{readme.manifest}, gotResources); diff != "" {
+		t.Fatalf("incorrect resources: diff %v", diff)
+	}
+	if diff := cmp.Diff([]mcpresources.McpManifest{docs.manifest, tables.manifest}, gotTemplates); diff != "" {
+		t.Fatalf("incorrect resource templates: diff %v", diff)
+	}
+
+	tcs := []struct {
+		desc   string
+		uri    string
+		want   string
+		wantOk bool
+	}{
+		{desc: "concrete resource takes precedence", uri: "mock://docs/readme", want: "readme", wantOk: true},
+		{desc: "resource template", uri: "mock://docs/guide", want: "docs", wantOk: true},
+		{desc: "other resource template", uri: "mock://tables/orders", want: "tables", wantOk: true},
+		{desc: "unknown uri", uri: "mock://unknown/orders", wantOk: false},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, ok := mcpresources.Find(resourcesMap, tc.uri)
+			if ok != tc.wantOk {
+				t.Fatalf("unexpected find result: got %t, want %t", ok, tc.wantOk)
+			}
+			if ok && got.McpManifest().Name != tc.want {
+				t.Fatalf("unexpected resource: got %q, want %q", got.McpManifest().Name, tc.want)
+			}
+		})
+	}
+}
+
+func TestDecodeConfigUnknownType(t *testing.T) {
+	_, err := mcpresources.DecodeConfig(context.Background(), "unknown-type", "my-resource", nil)
+	if err == nil {
+		t.Fatalf("expected error for unknown resource type")
+	}
+	want := `unknown resource type: "unknown-type"`
+	if err.Error() != want {
+		t.Fatalf("unexpected error: got %q, want %q", err, want)
+	}
+}
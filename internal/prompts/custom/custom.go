@@ -91,6 +91,10 @@ func (p Prompt) SubstituteParams(argValues parameters.ParamValues) (any, error)
 	return prompts.SubstituteMessages(p.Messages, p.Arguments, argValues)
 }
 
+func (p Prompt) GetArguments() prompts.Arguments {
+	return p.Arguments
+}
+
 func (p Prompt) ParseArgs(args map[string]any, data map[string]map[string]any) (parameters.ParamValues, error) {
 	return prompts.ParseArguments(p.Arguments, args, data)
 }
@@ -19,6 +19,7 @@ import (
 	"fmt"
 
 	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/util/parameters"
 )
 
@@ -70,6 +71,7 @@ type PromptConfig interface {
 type Prompt interface {
 	SubstituteParams(parameters.ParamValues) (any, error)
 	ParseArgs(map[string]any, map[string]map[string]any) (parameters.ParamValues, error)
+	GetArguments() Arguments
 	Manifest() Manifest
 	McpManifest() McpManifest
 	ToConfig() PromptConfig
@@ -83,9 +85,13 @@ type Manifest struct {
 
 // McpManifest is the definition for a prompt the MCP client can get.
 type McpManifest struct {
-	Name        string           `json:"name"`
+	Name string `json:"name"`
+	// A human-readable name of the prompt. Only supported for v2025-06-18+.
+	Title       string           `json:"title,omitempty"`
 	Description string           `json:"description,omitempty"`
 	Arguments   []ArgMcpManifest `json:"arguments,omitempty"`
+	// Icons of the prompt. Only supported for v2025-11-25+.
+	Icons []tools.Icon `json:"icons,omitempty"`
 }
 
 func GetMcpManifest(name, desc string, args Arguments) McpManifest {
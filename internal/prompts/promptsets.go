@@ -36,6 +36,17 @@ func (p Promptset) ToConfig() PromptsetConfig {
 	return p.PromptsetConfig
 }
 
+// GetPrompt returns the prompt with the given name, if it is part of the
+// promptset.
+func (p Promptset) GetPrompt(promptName string) (Prompt, bool) {
+	for i, m := range p.McpManifest {
+		if m.Name == promptName {
+			return *p.Prompts[i], true
+		}
+	}
+	return nil, false
+}
+
 type PromptsetManifest struct {
 	ServerVersion   string              `json:"serverVersion"`
 	PromptsManifest map[string]Manifest `json:"prompts"`
@@ -36,6 +36,7 @@ func (m mockPrompt) SubstituteParams(parameters.ParamValues) (any, error) { retu
 func (m mockPrompt) ParseArgs(map[string]any, map[string]map[string]any) (parameters.ParamValues, error) {
 	return nil, nil
 }
+func (m mockPrompt) GetArguments() prompts.Arguments  { return m.args }
 func (m mockPrompt) Manifest() prompts.Manifest       { return m.manifest }
 func (m mockPrompt) McpManifest() prompts.McpManifest { return m.mcpManifest }
 func (m mockPrompt) ToConfig() prompts.PromptConfig   { return nil }
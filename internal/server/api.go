@@ -18,11 +18,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"net/http"
+	"slices"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
+	mcputil "github.com/googleapis/genai-toolbox/internal/server/mcp/util"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/util"
 	"github.com/googleapis/genai-toolbox/internal/util/parameters"
@@ -38,10 +41,19 @@ func apiRouter(s *Server) (chi.Router, error) {
 	r.Use(middleware.StripSlashes)
 	r.Use(render.SetContentType(render.ContentTypeJSON))
 
-	r.Get("/toolset", func(w http.ResponseWriter, r *http.Request) { toolsetHandler(s, w, r) })
+	// unscoped routes resolve tools through the default toolset, which
+	// includes every tool
+	if !s.disableDefaultToolsetAPI {
+		r.Get("/toolset", func(w http.ResponseWriter, r *http.Request) { toolsetHandler(s, w, r) })
+		r.Route("/tool/{toolName}", func(r chi.Router) {
+			r.Get("/", func(w http.ResponseWriter, r *http.Request) { toolGetHandler(s, w, r) })
+			r.Post("/invoke", func(w http.ResponseWriter, r *http.Request) { toolInvokeHandler(s, w, r) })
+		})
+	}
 	r.Get("/toolset/{toolsetName}", func(w http.ResponseWriter, r *http.Request) { toolsetHandler(s, w, r) })
 
-	r.Route("/tool/{toolName}", func(r chi.Router) {
+	// toolset-scoped routes only resolve tools that belong to the toolset
+	r.Route("/toolset/{toolsetName}/tool/{toolName}", func(r chi.Router) {
 		r.Get("/", func(w http.ResponseWriter, r *http.Request) { toolGetHandler(s, w, r) })
 		r.Post("/invoke", func(w http.ResponseWriter, r *http.Request) { toolInvokeHandler(s, w, r) })
 	})
@@ -72,7 +84,46 @@ func toolsetHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		_ = render.Render(w, r, newErrResponse(err, http.StatusNotFound))
 		return
 	}
-	render.JSON(w, r, toolset.Manifest)
+
+	// tools are paginated in the order of their names
+	names := slices.Sorted(maps.Keys(toolset.Manifest.ToolsManifest))
+	page, nextCursor, err := util.Paginate(names, r.URL.Query().Get("cursor"), s.pageSize)
+	if err != nil {
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+		return
+	}
+	manifest := tools.ToolsetManifest{
+		ServerVersion: toolset.Manifest.ServerVersion,
+		ToolsManifest: make(map[string]tools.Manifest, len(page)),
+		NextCursor:    nextCursor,
+	}
+	for _, name := range page {
+		manifest.ToolsManifest[name] = toolset.Manifest.ToolsManifest[name]
+	}
+	render.JSON(w, r, manifest)
+}
+
+// resolveTool looks up a Tool by name, and the toolset it is called from. If
+// the request is scoped to a toolset, tools outside of that toolset are
+// treated as nonexistent. Otherwise, the default toolset is returned.
+func resolveTool(s *Server, r *http.Request, toolName string) (tools.Toolset, tools.Tool, error) {
+	tool, ok := s.ResourceMgr.GetTool(toolName)
+	if !ok {
+		return tools.Toolset{}, nil, fmt.Errorf("invalid tool name: tool with name %q does not exist", toolName)
+	}
+	toolsetName := chi.URLParam(r, "toolsetName")
+	toolset, ok := s.ResourceMgr.GetToolset(toolsetName)
+	if toolsetName == "" {
+		return toolset, tool, nil
+	}
+	if !ok {
+		return tools.Toolset{}, nil, fmt.Errorf("toolset %q does not exist", toolsetName)
+	}
+	if !toolset.HasTool(toolName) {
+		return tools.Toolset{}, nil, fmt.Errorf("invalid tool name: tool with name %q does not exist in toolset %q", toolName, toolsetName)
+	}
+	return toolset, tool, nil
 }
 
 // toolGetHandler handles requests for a single Tool.
@@ -91,9 +142,8 @@ func toolGetHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		span.End()
 	}()
 
-	tool, ok := s.ResourceMgr.GetTool(toolName)
-	if !ok {
-		err = fmt.Errorf("invalid tool name: tool with name %q does not exist", toolName)
+	_, tool, err := resolveTool(s, r, toolName)
+	if err != nil {
 		s.logger.DebugContext(ctx, err.Error())
 		_ = render.Render(w, r, newErrResponse(err, http.StatusNotFound))
 		return
@@ -114,6 +164,10 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 	ctx, span := s.instrumentation.Tracer.Start(r.Context(), "toolbox/server/tool/invoke")
 	r = r.WithContext(ctx)
 	ctx = util.WithLogger(r.Context(), s.logger)
+	// the resources used by the invocation are not closed by a reload until
+	// it ends
+	ctx, release := s.ResourceMgr.Acquire(ctx)
+	defer release()
 
 	toolName := chi.URLParam(r, "toolName")
 	s.logger.DebugContext(ctx, fmt.Sprintf("tool name: %s", toolName))
@@ -126,9 +180,8 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		span.End()
 	}()
 
-	tool, ok := s.ResourceMgr.GetTool(toolName)
-	if !ok {
-		err = fmt.Errorf("invalid tool name: tool with name %q does not exist", toolName)
+	toolset, tool, err := resolveTool(s, r, toolName)
+	if err != nil {
 		s.logger.DebugContext(ctx, err.Error())
 		_ = render.Render(w, r, newErrResponse(err, http.StatusNotFound))
 		return
@@ -187,7 +240,10 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		_ = render.Render(w, r, newErrResponse(err, http.StatusUnauthorized))
 		return
 	}
+
 	s.logger.DebugContext(ctx, "tool invocation authorized")
+	// sources may run the invocation as the caller
+	ctx = util.WithAuthClaims(ctx, claimsFromAuth)
 
 	var data map[string]any
 	if err = util.DecodeJSON(r.Body, &data); err != nil {
@@ -226,11 +282,27 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 	}
 	s.logger.DebugContext(ctx, fmt.Sprintf("invocation params: %s", params))
 
-	params, err = tool.EmbedParams(ctx, params, s.ResourceMgr.GetEmbeddingModelMap())
-	if err != nil {
-		err = fmt.Errorf("error embedding parameters: %w", err)
+	// confirmation requires MCP elicitation, fail closed for this endpoint
+	if tools.RequiresConfirmation(tool) {
+		err = fmt.Errorf("tool %q requires confirmation, which is only supported by MCP clients with the elicitation capability", toolName)
 		s.logger.DebugContext(ctx, err.Error())
-		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+		_ = render.Render(w, r, newErrResponse(err, http.StatusForbidden))
+		return
+	}
+
+	// the policies must allow the invocation before the parameters are embedded
+	ctx, params, prepareErr := mcputil.PrepareInvocation(ctx, toolset, toolName, tool, params, s.ResourceMgr.GetEmbeddingModelMap())
+	if prepareErr != nil {
+		s.logger.DebugContext(ctx, fmt.Sprintf("invocation denied: %v", prepareErr))
+		var clientServerErr *util.ClientServerError
+		if errors.As(prepareErr, &clientServerErr) {
+			_ = render.Render(w, r, newErrResponse(prepareErr, clientServerErr.Code))
+			return
+		}
+		errMap := map[string]string{"error": prepareErr.Error()}
+		errMarshal, _ := json.Marshal(errMap)
+
+		_ = render.Render(w, r, &resultResponse{Result: string(errMarshal)})
 		return
 	}
 
@@ -23,6 +23,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/googleapis/genai-toolbox/internal/server/resources"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 )
 
@@ -123,6 +124,55 @@ func TestToolsetEndpoint(t *testing.T) {
 	}
 }
 
+func TestToolsetEndpointPagination(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2, tool3}
+	toolsMap, toolsets, _, _ := setUpResources(t, mockTools, nil)
+	resourceManager := resources.NewResourceManager(nil, nil, nil, toolsMap, toolsets, nil, nil, nil)
+	r, shutdown := setUpServerWithPageSize(t, "api", resourceManager, 2)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	get := func(cursor string) (int, tools.ToolsetManifest) {
+		resp, body, err := runRequest(ts, http.MethodGet, "/toolset/?cursor="+cursor, nil, nil)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		var m tools.ToolsetManifest
+		if resp.StatusCode == http.StatusOK {
+			if err := json.Unmarshal(body, &m); err != nil {
+				t.Fatalf("unable to parse ToolsetManifest: %s", err)
+			}
+		}
+		return resp.StatusCode, m
+	}
+
+	status, first := get("")
+	if status != http.StatusOK {
+		t.Fatalf("unexpected status code: want %d, got %d", http.StatusOK, status)
+	}
+	if len(first.ToolsManifest) != 2 || first.NextCursor == "" {
+		t.Fatalf("unexpected first page: %+v", first)
+	}
+
+	status, second := get(first.NextCursor)
+	if status != http.StatusOK {
+		t.Fatalf("unexpected status code: want %d, got %d", http.StatusOK, status)
+	}
+	if len(second.ToolsManifest) != 1 || second.NextCursor != "" {
+		t.Fatalf("unexpected second page: %+v", second)
+	}
+	for name := range second.ToolsManifest {
+		if _, ok := first.ToolsManifest[name]; ok {
+			t.Fatalf("%q tool returned on both pages", name)
+		}
+	}
+
+	if status, _ := get("not-a-cursor"); status != http.StatusBadRequest {
+		t.Fatalf("unexpected status code for invalid cursor: want %d, got %d", http.StatusBadRequest, status)
+	}
+}
+
 func TestToolGetEndpoint(t *testing.T) {
 	mockTools := []MockTool{tool1, tool2}
 	toolsMap, toolsets, _, _ := setUpResources(t, mockTools, nil)
@@ -212,7 +262,7 @@ func TestToolGetEndpoint(t *testing.T) {
 }
 
 func TestToolInvokeEndpoint(t *testing.T) {
-	mockTools := []MockTool{tool1, tool2, tool4, tool5}
+	mockTools := []MockTool{tool1, tool2, tool4, tool5, tool6, tool7}
 	toolsMap, toolsets, _, _ := setUpResources(t, mockTools, nil)
 	r, shutdown := setUpServer(t, "api", toolsMap, toolsets, nil, nil)
 	defer shutdown()
@@ -261,6 +311,20 @@ func TestToolInvokeEndpoint(t *testing.T) {
 			want:        "",
 			isErr:       true,
 		},
+		{
+			name:        "tool requiring confirmation",
+			toolName:    tool6.Name,
+			requestBody: bytes.NewBuffer([]byte(`{"name": "orders"}`)),
+			want:        "",
+			isErr:       true,
+		},
+		{
+			name:        "tool denied by authorization policy",
+			toolName:    tool7.Name,
+			requestBody: bytes.NewBuffer([]byte(`{}`)),
+			want:        "",
+			isErr:       true,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -295,3 +359,122 @@ func TestToolInvokeEndpoint(t *testing.T) {
 		})
 	}
 }
+
+func TestToolsetScopedToolEndpoints(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2}
+	toolsMap, toolsets, _, _ := setUpResources(t, mockTools, nil)
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets, nil, nil)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	testCases := []struct {
+		name           string
+		method         string
+		path           string
+		wantStatusCode int
+	}{
+		{
+			name:           "get tool in toolset",
+			method:         http.MethodGet,
+			path:           fmt.Sprintf("/toolset/tool1_only/tool/%s", tool1.Name),
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "get tool outside of toolset",
+			method:         http.MethodGet,
+			path:           fmt.Sprintf("/toolset/tool1_only/tool/%s", tool2.Name),
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "invoke tool in toolset",
+			method:         http.MethodPost,
+			path:           fmt.Sprintf("/toolset/tool1_only/tool/%s/invoke", tool1.Name),
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "invoke tool outside of toolset",
+			method:         http.MethodPost,
+			path:           fmt.Sprintf("/toolset/tool1_only/tool/%s/invoke", tool2.Name),
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "invoke tool in invalid toolset",
+			method:         http.MethodPost,
+			path:           fmt.Sprintf("/toolset/some_imaginary_toolset/tool/%s/invoke", tool1.Name),
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, body, err := runRequest(ts, tc.method, tc.path, bytes.NewBuffer([]byte(`{}`)), nil)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != tc.wantStatusCode {
+				t.Fatalf("unexpected status code: want %d, got %d, body: %s", tc.wantStatusCode, resp.StatusCode, body)
+			}
+		})
+	}
+}
+
+func TestDisableDefaultToolsetAPI(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2}
+	toolsMap, toolsets, _, _ := setUpResources(t, mockTools, nil)
+	resourceManager := resources.NewResourceManager(nil, nil, nil, toolsMap, toolsets, nil, nil, nil)
+	r, shutdown := setUpServerWithOptions(t, "api", resourceManager, func(s *Server) { s.disableDefaultToolsetAPI = true })
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	testCases := []struct {
+		name           string
+		method         string
+		path           string
+		wantStatusCode int
+	}{
+		{
+			name:           "list default toolset",
+			method:         http.MethodGet,
+			path:           "/toolset",
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "get unscoped tool",
+			method:         http.MethodGet,
+			path:           fmt.Sprintf("/tool/%s", tool1.Name),
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "invoke unscoped tool",
+			method:         http.MethodPost,
+			path:           fmt.Sprintf("/tool/%s/invoke", tool2.Name),
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "list named toolset",
+			method:         http.MethodGet,
+			path:           "/toolset/tool1_only",
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "invoke tool in toolset",
+			method:         http.MethodPost,
+			path:           fmt.Sprintf("/toolset/tool1_only/tool/%s/invoke", tool1.Name),
+			wantStatusCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, body, err := runRequest(ts, tc.method, tc.path, bytes.NewBuffer([]byte(`{}`)), nil)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != tc.wantStatusCode {
+				t.Fatalf("unexpected status code: want %d, got %d, body: %s", tc.wantStatusCode, resp.StatusCode, body)
+			}
+		})
+	}
+}
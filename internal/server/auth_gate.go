@@ -0,0 +1,62 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/render"
+)
+
+// requireAuth rejects requests that none of the named auth services verify.
+// The auth services are looked up for every request, so that reloaded auth
+// services are used; a removed auth service verifies no request.
+func (s *Server) requireAuth(authServices []string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, name := range authServices {
+				aS, ok := s.ResourceMgr.GetAuthService(name)
+				if !ok {
+					continue
+				}
+				claims, err := aS.GetClaimsFromHeader(r.Context(), r.Header)
+				if err != nil {
+					// one of the other auth services may verify the request
+					s.logger.DebugContext(r.Context(), fmt.Sprintf("auth service %q rejected request: %s", name, err))
+					continue
+				}
+				if claims != nil {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+			err := fmt.Errorf("request requires a principal verified by an auth service")
+			// MCP clients discover how to authenticate from the challenge of
+			// the protected resource
+			if s.oauth != nil && isMcpPath(r.URL.Path) {
+				s.oauth.challenge(w, r, http.StatusUnauthorized, "", nil, err)
+				return
+			}
+			_ = render.Render(w, r, newErrResponse(err, http.StatusUnauthorized))
+		})
+	}
+}
+
+// isMcpPath reports whether path is served by the MCP endpoint.
+func isMcpPath(path string) bool {
+	return path == "/mcp" || strings.HasPrefix(path, "/mcp/")
+}
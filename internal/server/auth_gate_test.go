@@ -0,0 +1,141 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/server/resources"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+)
+
+func TestRequireAuth(t *testing.T) {
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "warn")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	authServices := map[string]auth.AuthService{
+		"my-oidc": MockAuthService{Name: "my-oidc"},
+		"my-keys": MockAuthService{Name: "my-keys"},
+		"other":   MockAuthService{Name: "other"},
+	}
+	s := &Server{
+		logger:      testLogger,
+		ResourceMgr: resources.NewResourceManager(nil, authServices, nil, nil, nil, nil, nil, nil),
+	}
+	handler := s.requireAuth([]string{"my-oidc", "my-keys", "removed"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tcs := []struct {
+		desc       string
+		header     http.Header
+		wantStatus int
+	}{
+		{
+			desc:       "no credentials",
+			header:     http.Header{},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			desc:       "verified by first auth service",
+			header:     http.Header{"My-Oidc_token": {"alice@example.com"}},
+			wantStatus: http.StatusOK,
+		},
+		{
+			desc:       "verified by second auth service",
+			header:     http.Header{"My-Keys_token": {"reporting@example.com"}},
+			wantStatus: http.StatusOK,
+		},
+		{
+			desc:       "verified by auth service that is not required",
+			header:     http.Header{"Other_token": {"alice@example.com"}},
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/toolset", nil)
+			req.Header = tc.header
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tc.wantStatus {
+				t.Fatalf("unexpected status: got %d, want %d", rec.Code, tc.wantStatus)
+			}
+			if tc.wantStatus != http.StatusUnauthorized {
+				return
+			}
+			if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, "application/json") {
+				t.Fatalf("unexpected content type: %q", got)
+			}
+			if got := rec.Header().Get("WWW-Authenticate"); got != "" {
+				t.Fatalf("unexpected challenge without OAuth: %q", got)
+			}
+		})
+	}
+}
+
+func TestRequireAuthMcpChallenge(t *testing.T) {
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "warn")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	o, err := newOAuthResource(ServerConfig{
+		OAuthIssuer:   testutils.NewIssuer(t).URL,
+		OAuthResource: oauthTestResource,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	authServices := map[string]auth.AuthService{"my-oidc": MockAuthService{Name: "my-oidc"}}
+	s := &Server{
+		logger:      testLogger,
+		ResourceMgr: resources.NewResourceManager(nil, authServices, nil, nil, nil, nil, nil, nil),
+		oauth:       o,
+	}
+	handler := s.requireAuth([]string{"my-oidc"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	resourceMetadata := `resource_metadata="https://toolbox.example.com/.well-known/oauth-protected-resource/mcp"`
+	tcs := []struct {
+		desc          string
+		path          string
+		wantChallenge bool
+	}{
+		{desc: "mcp endpoint", path: "/mcp", wantChallenge: true},
+		{desc: "mcp toolset endpoint", path: "/mcp/tool1_only", wantChallenge: true},
+		{desc: "api endpoint", path: "/api/toolset"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, tc.path, nil))
+			if rec.Code != http.StatusUnauthorized {
+				t.Fatalf("unexpected status: got %d, want %d", rec.Code, http.StatusUnauthorized)
+			}
+			got := rec.Header().Get("WWW-Authenticate")
+			if tc.wantChallenge != strings.Contains(got, resourceMetadata) {
+				t.Fatalf("unexpected challenge: %q", got)
+			}
+		})
+	}
+}
@@ -73,6 +73,22 @@ var tool5 = MockTool{
 	requiresClientAuthrorization: true,
 }
 
+var tool6 = MockTool{
+	Name: "confirmed_tool",
+	Params: parameters.Parameters{
+		parameters.NewStringParameter("name", "The name of the item to delete."),
+	},
+	requireConfirmation: true,
+}
+
+var tool7 = MockTool{
+	Name:   "policy_tool",
+	Params: []parameters.Parameter{},
+	policy: &tools.AuthorizationPolicy{
+		Allow: []tools.AuthorizationRule{{Claims: map[string]tools.ClaimMatcher{"email": {EndsWith: "@example.com"}}}},
+	},
+}
+
 var prompt1 = MockPrompt{
 	Name: "prompt1",
 	Args: prompts.Arguments{},
@@ -131,6 +147,22 @@ func setUpResources(t *testing.T, mockTools []MockTool, mockPrompts []MockPrompt
 
 // setUpServer create a new server with tools, toolsets, prompts, and promptsets.
 func setUpServer(t *testing.T, router string, tools map[string]tools.Tool, toolsets map[string]tools.Toolset, prompts map[string]prompts.Prompt, promptsets map[string]prompts.Promptset) (chi.Router, func()) {
+	resourceManager := resources.NewResourceManager(nil, nil, nil, tools, toolsets, prompts, promptsets, nil)
+	return setUpServerWithResourceManager(t, router, resourceManager)
+}
+
+// setUpServerWithResourceManager create a new server backed by the given resource manager.
+func setUpServerWithResourceManager(t *testing.T, router string, resourceManager *resources.ResourceManager) (chi.Router, func()) {
+	return setUpServerWithPageSize(t, router, resourceManager, 0)
+}
+
+// setUpServerWithPageSize create a new server that paginates list results with the given page size.
+func setUpServerWithPageSize(t *testing.T, router string, resourceManager *resources.ResourceManager, pageSize int) (chi.Router, func()) {
+	return setUpServerWithOptions(t, router, resourceManager, func(s *Server) { s.pageSize = pageSize })
+}
+
+// setUpServerWithOptions create a new server that is configured by configure before its router is created.
+func setUpServerWithOptions(t *testing.T, router string, resourceManager *resources.ResourceManager, configure func(*Server)) (chi.Router, func()) {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
@@ -150,8 +182,6 @@ func setUpServer(t *testing.T, router string, tools map[string]tools.Tool, tools
 
 	sseManager := newSseManager(ctx)
 
-	resourceManager := resources.NewResourceManager(nil, nil, nil, tools, toolsets, prompts, promptsets)
-
 	server := Server{
 		version:         fakeVersionString,
 		logger:          testLogger,
@@ -159,6 +189,7 @@ func setUpServer(t *testing.T, router string, tools map[string]tools.Tool, tools
 		sseManager:      sseManager,
 		ResourceMgr:     resourceManager,
 	}
+	configure(&server)
 
 	var r chi.Router
 	switch router {
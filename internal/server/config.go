@@ -23,9 +23,8 @@ import (
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/auth"
-	"github.com/googleapis/genai-toolbox/internal/auth/google"
 	"github.com/googleapis/genai-toolbox/internal/embeddingmodels"
-	"github.com/googleapis/genai-toolbox/internal/embeddingmodels/gemini"
+	"github.com/googleapis/genai-toolbox/internal/mcpresources"
 	"github.com/googleapis/genai-toolbox/internal/prompts"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/tools"
@@ -41,6 +40,9 @@ type ServerConfig struct {
 	Port int
 	// SourceConfigs defines what sources of data are available for tools.
 	SourceConfigs SourceConfigs
+	// PreviousSources are the sources in use when the configs are reloaded. A
+	// source whose config did not change is reused instead of initialized again.
+	PreviousSources map[string]sources.Source
 	// AuthServiceConfigs defines what sources of authentication are available for tools.
 	AuthServiceConfigs AuthServiceConfigs
 	// EmbeddingModelConfigs defines a models used to embed parameters.
@@ -53,6 +55,13 @@ type ServerConfig struct {
 	PromptConfigs PromptConfigs
 	// PromptsetConfigs defines what prompts are available
 	PromptsetConfigs PromptsetConfigs
+	// ResourceConfigs defines what MCP resources are available
+	ResourceConfigs ResourceConfigs
+	// PolicyConfigs defines the policies evaluated for tool invocations.
+	PolicyConfigs PolicyConfigs
+	// ServerMetadataConfig defines the metadata MCP clients receive about the
+	// server, its tools and its prompts.
+	ServerMetadataConfig *ServerMetadataConfig
 	// LoggingFormat defines whether structured loggings are used.
 	LoggingFormat logFormat
 	// LogLevel defines the levels to log.
@@ -65,10 +74,16 @@ type ServerConfig struct {
 	TelemetryServiceName string
 	// Stdio indicates if Toolbox is listening via MCP stdio.
 	Stdio bool
+	// StdioAuthFile is a file that maps header names to values, which are
+	// sent with every stdio request for auth services to verify.
+	StdioAuthFile string
 	// DisableReload indicates if the user has disabled dynamic reloading for Toolbox.
 	DisableReload bool
 	// UI indicates if Toolbox UI endpoints (/ui) are available.
 	UI bool
+	// DisableDefaultToolsetAPI removes the /api routes that resolve tools
+	// through the default toolset, leaving only the toolset-scoped routes.
+	DisableDefaultToolsetAPI bool
 	// Specifies a list of origins permitted to access this server.
 	AllowedOrigins []string
 	// Specifies a list of hosts permitted to access this server.
@@ -77,6 +92,36 @@ type ServerConfig struct {
 	UserAgentMetadata []string
 	// PollInterval sets the polling frequency for configuration file updates.
 	PollInterval int
+	// PageSize is the maximum number of items returned per page by list
+	// operations. A value of 0 disables pagination.
+	PageSize int
+	// TaskWorkers is the maximum number of MCP tasks that run concurrently.
+	TaskWorkers int
+	// OAuthIssuer is the authorization server that issues the bearer tokens
+	// required by the MCP endpoint. OAuth is disabled if empty.
+	OAuthIssuer string
+	// OAuthResource is the canonical URI of the MCP endpoint.
+	OAuthResource string
+	// OAuthAudiences are the accepted audiences of the bearer tokens. Defaults
+	// to OAuthResource.
+	OAuthAudiences []string
+	// OAuthScopeToolsets maps scopes to the toolsets they grant access to, as
+	// "scope=toolset".
+	OAuthScopeToolsets []string
+	// TLSCertFile and TLSKeyFile hold the certificate of the server. TLS is
+	// disabled if empty. Both are reloaded when they change.
+	TLSCertFile string
+	TLSKeyFile  string
+	// TLSClientCAFile is a bundle of the CAs that client certificates are
+	// verified against. Client certificates are not requested if empty.
+	TLSClientCAFile string
+	// TLSRequireClientCert rejects connections without a verified client
+	// certificate.
+	TLSRequireClientCert bool
+	// RequireAuthServices are the auth services, one of which must verify
+	// every HTTP request but the health check. Requests are not
+	// authenticated if empty.
+	RequireAuthServices []string
 }
 
 type logFormat string
@@ -138,15 +183,46 @@ type ToolConfigs map[string]tools.ToolConfig
 type ToolsetConfigs map[string]tools.ToolsetConfig
 type PromptConfigs map[string]prompts.PromptConfig
 type PromptsetConfigs map[string]prompts.PromptsetConfig
+type ResourceConfigs map[string]mcpresources.ResourceConfig
+type PolicyConfigs map[string]tools.InvocationPolicyConfig
 
+// ServerMetadataConfig is the `server` kind of a tools file. It sets the
+// metadata MCP clients receive about the server, and the titles and icons of
+// tools and prompts by name.
+type ServerMetadataConfig struct {
+	tools.ServerMetadata `yaml:",inline"`
+	Tools                map[string]tools.DisplayMetadata `yaml:"tools"`
+	Prompts              map[string]tools.DisplayMetadata `yaml:"prompts"`
+}
+
+// FileConfigs are the configs declared in a tools file.
+type FileConfigs struct {
+	Sources         SourceConfigs
+	AuthServices    AuthServiceConfigs
+	EmbeddingModels EmbeddingModelConfigs
+	Tools           ToolConfigs
+	Toolsets        ToolsetConfigs
+	Prompts         PromptConfigs
+	Resources       ResourceConfigs
+	Policies        PolicyConfigs
+	// Server is nil if the file has no `server` document.
+	Server *ServerMetadataConfig
+}
+
+// UnmarshalResourceConfig unmarshals the sources, auth services, embedding
+// models, tools, toolsets and prompts of a tools file. The other kinds are
+// ignored; see UnmarshalFileConfigs.
 func UnmarshalResourceConfig(ctx context.Context, raw []byte) (SourceConfigs, AuthServiceConfigs, EmbeddingModelConfigs, ToolConfigs, ToolsetConfigs, PromptConfigs, error) {
-	// prepare configs map
-	var sourceConfigs SourceConfigs
-	var authServiceConfigs AuthServiceConfigs
-	var embeddingModelConfigs EmbeddingModelConfigs
-	var toolConfigs ToolConfigs
-	var toolsetConfigs ToolsetConfigs
-	var promptConfigs PromptConfigs
+	c, err := UnmarshalFileConfigs(ctx, raw)
+	if err != nil {
+		return nil, nil, nil, nil, nil, nil, err
+	}
+	return c.Sources, c.AuthServices, c.EmbeddingModels, c.Tools, c.Toolsets, c.Prompts, nil
+}
+
+// UnmarshalFileConfigs unmarshals every document of a tools file.
+func UnmarshalFileConfigs(ctx context.Context, raw []byte) (FileConfigs, error) {
+	var c FileConfigs
 	// promptset configs is not yet supported
 
 	decoder := yaml.NewDecoder(bytes.NewReader(raw))
@@ -157,79 +233,136 @@ func UnmarshalResourceConfig(ctx context.Context, raw []byte) (SourceConfigs, Au
 			if err == io.EOF {
 				break
 			}
-			return nil, nil, nil, nil, nil, nil, fmt.Errorf("unable to decode YAML document: %w", err)
+			return FileConfigs{}, fmt.Errorf("unable to decode YAML document: %w", err)
 		}
 		var kind, name string
 		var ok bool
 		if kind, ok = resource["kind"].(string); !ok {
-			return nil, nil, nil, nil, nil, nil, fmt.Errorf("missing 'kind' field or it is not a string: %v", resource)
-		}
-		if name, ok = resource["name"].(string); !ok {
-			return nil, nil, nil, nil, nil, nil, fmt.Errorf("missing 'name' field or it is not a string")
+			return FileConfigs{}, fmt.Errorf("missing 'kind' field or it is not a string: %v", resource)
 		}
 		// remove 'kind' from map for strict unmarshaling
 		delete(resource, "kind")
+		// the server is not a named resource
+		if kind == "server" {
+			if c.Server != nil {
+				return FileConfigs{}, fmt.Errorf("only one document of kind %s is allowed", kind)
+			}
+			sc, err := UnmarshalYAMLServerMetadataConfig(ctx, resource)
+			if err != nil {
+				return FileConfigs{}, fmt.Errorf("error unmarshaling %s: %s", kind, err)
+			}
+			c.Server = &sc
+			continue
+		}
+		if name, ok = resource["name"].(string); !ok {
+			return FileConfigs{}, fmt.Errorf("missing 'name' field or it is not a string")
+		}
 
 		switch kind {
 		case "sources":
-			c, err := UnmarshalYAMLSourceConfig(ctx, name, resource)
+			sc, err := UnmarshalYAMLSourceConfig(ctx, name, resource)
 			if err != nil {
-				return nil, nil, nil, nil, nil, nil, fmt.Errorf("error unmarshaling %s: %s", kind, err)
+				return FileConfigs{}, fmt.Errorf("error unmarshaling %s: %s", kind, err)
 			}
-			if sourceConfigs == nil {
-				sourceConfigs = make(SourceConfigs)
+			if c.Sources == nil {
+				c.Sources = make(SourceConfigs)
 			}
-			sourceConfigs[name] = c
+			c.Sources[name] = sc
 		case "authServices":
-			c, err := UnmarshalYAMLAuthServiceConfig(ctx, name, resource)
+			ac, err := UnmarshalYAMLAuthServiceConfig(ctx, name, resource)
 			if err != nil {
-				return nil, nil, nil, nil, nil, nil, fmt.Errorf("error unmarshaling %s: %s", kind, err)
+				return FileConfigs{}, fmt.Errorf("error unmarshaling %s: %s", kind, err)
 			}
-			if authServiceConfigs == nil {
-				authServiceConfigs = make(AuthServiceConfigs)
+			if c.AuthServices == nil {
+				c.AuthServices = make(AuthServiceConfigs)
 			}
-			authServiceConfigs[name] = c
+			c.AuthServices[name] = ac
 		case "tools":
-			c, err := UnmarshalYAMLToolConfig(ctx, name, resource)
+			tc, err := UnmarshalYAMLToolConfig(ctx, name, resource)
 			if err != nil {
-				return nil, nil, nil, nil, nil, nil, fmt.Errorf("error unmarshaling %s: %s", kind, err)
+				return FileConfigs{}, fmt.Errorf("error unmarshaling %s: %s", kind, err)
 			}
-			if toolConfigs == nil {
-				toolConfigs = make(ToolConfigs)
+			if c.Tools == nil {
+				c.Tools = make(ToolConfigs)
 			}
-			toolConfigs[name] = c
+			c.Tools[name] = tc
 		case "toolsets":
-			c, err := UnmarshalYAMLToolsetConfig(ctx, name, resource)
+			tc, err := UnmarshalYAMLToolsetConfig(ctx, name, resource)
 			if err != nil {
-				return nil, nil, nil, nil, nil, nil, fmt.Errorf("error unmarshaling %s: %s", kind, err)
+				return FileConfigs{}, fmt.Errorf("error unmarshaling %s: %s", kind, err)
 			}
-			if toolsetConfigs == nil {
-				toolsetConfigs = make(ToolsetConfigs)
+			if c.Toolsets == nil {
+				c.Toolsets = make(ToolsetConfigs)
 			}
-			toolsetConfigs[name] = c
+			c.Toolsets[name] = tc
 		case "embeddingModels":
-			c, err := UnmarshalYAMLEmbeddingModelConfig(ctx, name, resource)
+			ec, err := UnmarshalYAMLEmbeddingModelConfig(ctx, name, resource)
 			if err != nil {
-				return nil, nil, nil, nil, nil, nil, fmt.Errorf("error unmarshaling %s: %s", kind, err)
+				return FileConfigs{}, fmt.Errorf("error unmarshaling %s: %s", kind, err)
 			}
-			if embeddingModelConfigs == nil {
-				embeddingModelConfigs = make(EmbeddingModelConfigs)
+			if c.EmbeddingModels == nil {
+				c.EmbeddingModels = make(EmbeddingModelConfigs)
 			}
-			embeddingModelConfigs[name] = c
+			c.EmbeddingModels[name] = ec
 		case "prompts":
-			c, err := UnmarshalYAMLPromptConfig(ctx, name, resource)
+			pc, err := UnmarshalYAMLPromptConfig(ctx, name, resource)
 			if err != nil {
-				return nil, nil, nil, nil, nil, nil, fmt.Errorf("error unmarshaling %s: %s", kind, err)
+				return FileConfigs{}, fmt.Errorf("error unmarshaling %s: %s", kind, err)
+			}
+			if c.Prompts == nil {
+				c.Prompts = make(PromptConfigs)
 			}
-			if promptConfigs == nil {
-				promptConfigs = make(PromptConfigs)
+			c.Prompts[name] = pc
+		case "resources":
+			rc, err := UnmarshalYAMLMcpResourceConfig(ctx, name, resource)
+			if err != nil {
+				return FileConfigs{}, fmt.Errorf("error unmarshaling %s: %s", kind, err)
 			}
-			promptConfigs[name] = c
+			if c.Resources == nil {
+				c.Resources = make(ResourceConfigs)
+			}
+			c.Resources[name] = rc
+		case "policies":
+			pc, err := UnmarshalYAMLPolicyConfig(ctx, name, resource)
+			if err != nil {
+				return FileConfigs{}, fmt.Errorf("error unmarshaling %s: %s", kind, err)
+			}
+			if c.Policies == nil {
+				c.Policies = make(PolicyConfigs)
+			}
+			c.Policies[name] = pc
 		default:
-			return nil, nil, nil, nil, nil, nil, fmt.Errorf("invalid kind %s", kind)
+			return FileConfigs{}, fmt.Errorf("invalid kind %s", kind)
 		}
 	}
-	return sourceConfigs, authServiceConfigs, embeddingModelConfigs, toolConfigs, toolsetConfigs, promptConfigs, nil
+	return c, nil
+}
+
+// UnmarshalYAMLServerMetadataConfig unmarshals the `server` document of a
+// tools file.
+func UnmarshalYAMLServerMetadataConfig(ctx context.Context, r map[string]any) (ServerMetadataConfig, error) {
+	var c ServerMetadataConfig
+	dec, err := util.NewStrictDecoder(r)
+	if err != nil {
+		return c, fmt.Errorf("error creating decoder: %w", err)
+	}
+	if err := dec.DecodeContext(ctx, &c); err != nil {
+		return c, fmt.Errorf("unable to parse as %q: %w", "server", err)
+	}
+	if err := c.Validate(); err != nil {
+		return c, err
+	}
+	for name, m := range c.Tools {
+		if err := m.Validate(); err != nil {
+			return c, fmt.Errorf("tool %q: %w", name, err)
+		}
+	}
+	for name, m := range c.Prompts {
+		if err := m.Validate(); err != nil {
+			return c, fmt.Errorf("prompt %q: %w", name, err)
+		}
+	}
+	return c, nil
 }
 
 func UnmarshalYAMLSourceConfig(ctx context.Context, name string, r map[string]any) (sources.SourceConfig, error) {
@@ -253,18 +386,15 @@ func UnmarshalYAMLAuthServiceConfig(ctx context.Context, name string, r map[stri
 	if !ok {
 		return nil, fmt.Errorf("missing 'type' field or it is not a string")
 	}
-	if resourceType != google.AuthServiceType {
-		return nil, fmt.Errorf("%s is not a valid type of auth service", resourceType)
-	}
 	dec, err := util.NewStrictDecoder(r)
 	if err != nil {
 		return nil, fmt.Errorf("error creating decoder: %s", err)
 	}
-	actual := google.Config{Name: name}
-	if err := dec.DecodeContext(ctx, &actual); err != nil {
-		return nil, fmt.Errorf("unable to parse as %s: %w", name, err)
+	authServiceConfig, err := auth.DecodeConfig(ctx, resourceType, name, dec)
+	if err != nil {
+		return nil, err
 	}
-	return actual, nil
+	return authServiceConfig, nil
 }
 
 func UnmarshalYAMLEmbeddingModelConfig(ctx context.Context, name string, r map[string]any) (embeddingmodels.EmbeddingModelConfig, error) {
@@ -272,18 +402,15 @@ func UnmarshalYAMLEmbeddingModelConfig(ctx context.Context, name string, r map[s
 	if !ok {
 		return nil, fmt.Errorf("missing 'type' field or it is not a string")
 	}
-	if resourceType != gemini.EmbeddingModelType {
-		return nil, fmt.Errorf("%s is not a valid type of embedding model", resourceType)
-	}
 	dec, err := util.NewStrictDecoder(r)
 	if err != nil {
 		return nil, fmt.Errorf("error creating decoder: %s", err)
 	}
-	actual := gemini.Config{Name: name}
-	if err := dec.DecodeContext(ctx, &actual); err != nil {
-		return nil, fmt.Errorf("unable to parse as %q: %w", name, err)
+	embeddingModelConfig, err := embeddingmodels.DecodeConfig(ctx, resourceType, name, dec)
+	if err != nil {
+		return nil, err
 	}
-	return actual, nil
+	return embeddingModelConfig, nil
 }
 
 func UnmarshalYAMLToolConfig(ctx context.Context, name string, r map[string]any) (tools.ToolConfig, error) {
@@ -299,6 +426,18 @@ func UnmarshalYAMLToolConfig(ctx context.Context, name string, r map[string]any)
 	if r["authRequired"] == nil {
 		r["authRequired"] = []string{}
 	}
+	// `requireConfirmation` and `authorization` are accepted by every tool
+	// type and decoded into its tools.CommonConfig, validate them first
+	if v, ok := r["requireConfirmation"]; ok {
+		if _, ok := v.(bool); !ok {
+			return nil, fmt.Errorf("`requireConfirmation` must be a boolean")
+		}
+	}
+	if v, ok := r["authorization"]; ok {
+		if _, err := unmarshalAuthorizationPolicy(ctx, v); err != nil {
+			return nil, err
+		}
+	}
 
 	// validify parameter references
 	if rawParams, ok := r["parameters"]; ok {
@@ -342,21 +481,33 @@ func UnmarshalYAMLToolConfig(ctx context.Context, name string, r map[string]any)
 	if err != nil {
 		return nil, fmt.Errorf("error creating decoder: %s", err)
 	}
-	toolCfg, err := tools.DecodeConfig(ctx, resourceType, name, dec)
-	if err != nil {
-		return nil, err
-	}
-	return toolCfg, nil
+	return tools.DecodeConfig(ctx, resourceType, name, dec)
 }
 
 func UnmarshalYAMLToolsetConfig(ctx context.Context, name string, r map[string]any) (tools.ToolsetConfig, error) {
 	var toolsetConfig tools.ToolsetConfig
+	// toolsets are decoded strictly, as tools are, so that a misspelled key
+	// such as `authorisation` fails instead of leaving the toolset unrestricted
+	dec, err := util.NewStrictDecoder(r)
+	if err != nil {
+		return toolsetConfig, fmt.Errorf("error creating decoder: %s", err)
+	}
+	var fields struct {
+		Name                 string `yaml:"name"`
+		Tools                any    `yaml:"tools"`
+		Resources            any    `yaml:"resources"`
+		Authorization        any    `yaml:"authorization"`
+		tools.ServerMetadata `yaml:",inline"`
+	}
+	if err := dec.DecodeContext(ctx, &fields); err != nil {
+		return toolsetConfig, fmt.Errorf("unable to unmarshal toolset %q: %s", name, err)
+	}
 	toolList, ok := r["tools"].([]any)
 	if !ok {
 		return toolsetConfig, fmt.Errorf("tools is missing or not a list of strings: %v", r)
 	}
 	justTools := map[string]any{"tools": toolList}
-	dec, err := util.NewStrictDecoder(justTools)
+	dec, err = util.NewStrictDecoder(justTools)
 	if err != nil {
 		return toolsetConfig, fmt.Errorf("error creating decoder: %s", err)
 	}
@@ -364,7 +515,58 @@ func UnmarshalYAMLToolsetConfig(ctx context.Context, name string, r map[string]a
 	if err := dec.DecodeContext(ctx, &raw); err != nil {
 		return toolsetConfig, fmt.Errorf("unable to unmarshal tools: %s", err)
 	}
-	return tools.ToolsetConfig{Name: name, ToolNames: raw["tools"]}, nil
+	// the toolset may override the server metadata
+	metadata := make(map[string]any)
+	for _, k := range []string{"title", "instructions", "icons"} {
+		if v, ok := r[k]; ok {
+			metadata[k] = v
+		}
+	}
+	dec, err = util.NewStrictDecoder(metadata)
+	if err != nil {
+		return toolsetConfig, fmt.Errorf("error creating decoder: %s", err)
+	}
+	var serverMetadata tools.ServerMetadata
+	if err := dec.DecodeContext(ctx, &serverMetadata); err != nil {
+		return toolsetConfig, fmt.Errorf("unable to unmarshal server metadata: %s", err)
+	}
+	if err := serverMetadata.Validate(); err != nil {
+		return toolsetConfig, err
+	}
+	var authorization *tools.AuthorizationPolicy
+	if v, ok := r["authorization"]; ok {
+		if authorization, err = unmarshalAuthorizationPolicy(ctx, v); err != nil {
+			return toolsetConfig, err
+		}
+	}
+	var resourceNames []string
+	if v, ok := r["resources"]; ok {
+		dec, err = util.NewStrictDecoder(v)
+		if err != nil {
+			return toolsetConfig, fmt.Errorf("error creating decoder: %s", err)
+		}
+		if err := dec.DecodeContext(ctx, &resourceNames); err != nil {
+			return toolsetConfig, fmt.Errorf("unable to unmarshal resources: %s", err)
+		}
+	}
+	return tools.ToolsetConfig{Name: name, ToolNames: raw["tools"], ResourceNames: resourceNames, ServerMetadata: serverMetadata, Authorization: authorization}, nil
+}
+
+// unmarshalAuthorizationPolicy unmarshals the `authorization` field of a tool
+// or a toolset.
+func unmarshalAuthorizationPolicy(ctx context.Context, v any) (*tools.AuthorizationPolicy, error) {
+	dec, err := util.NewStrictDecoder(v)
+	if err != nil {
+		return nil, fmt.Errorf("error creating decoder: %s", err)
+	}
+	var policy tools.AuthorizationPolicy
+	if err := dec.DecodeContext(ctx, &policy); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal `authorization`: %s", err)
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid `authorization`: %w", err)
+	}
+	return &policy, nil
 }
 
 func UnmarshalYAMLPromptConfig(ctx context.Context, name string, r map[string]any) (prompts.PromptConfig, error) {
@@ -391,6 +593,36 @@ func UnmarshalYAMLPromptConfig(ctx context.Context, name string, r map[string]an
 	return promptCfg, nil
 }
 
+func UnmarshalYAMLMcpResourceConfig(ctx context.Context, name string, r map[string]any) (mcpresources.ResourceConfig, error) {
+	resourceType, ok := r["type"].(string)
+	if !ok {
+		return nil, fmt.Errorf("missing 'type' field or it is not a string")
+	}
+	dec, err := util.NewStrictDecoder(r)
+	if err != nil {
+		return nil, fmt.Errorf("error creating decoder: %s", err)
+	}
+	resourceCfg, err := mcpresources.DecodeConfig(ctx, resourceType, name, dec)
+	if err != nil {
+		return nil, err
+	}
+	return resourceCfg, nil
+}
+
+// UnmarshalYAMLPolicyConfig unmarshals a document of the `policies` kind.
+func UnmarshalYAMLPolicyConfig(ctx context.Context, name string, r map[string]any) (tools.InvocationPolicyConfig, error) {
+	var policyConfig tools.InvocationPolicyConfig
+	dec, err := util.NewStrictDecoder(r)
+	if err != nil {
+		return policyConfig, fmt.Errorf("error creating decoder: %s", err)
+	}
+	if err := dec.DecodeContext(ctx, &policyConfig); err != nil {
+		return policyConfig, fmt.Errorf("unable to parse as %q: %w", name, err)
+	}
+	policyConfig.Name = name
+	return policyConfig, nil
+}
+
 // Tools naming validation is added in the MCP v2025-11-25, but we'll be
 // implementing it across Toolbox
 // Tool names SHOULD be between 1 and 128 characters in length (inclusive).
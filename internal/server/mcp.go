@@ -22,19 +22,26 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"reflect"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
 	"github.com/google/uuid"
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/prompts"
 	"github.com/googleapis/genai-toolbox/internal/server/mcp"
 	"github.com/googleapis/genai-toolbox/internal/server/mcp/jsonrpc"
 	mcputil "github.com/googleapis/genai-toolbox/internal/server/mcp/util"
 	v20241105 "github.com/googleapis/genai-toolbox/internal/server/mcp/v20241105"
 	v20250326 "github.com/googleapis/genai-toolbox/internal/server/mcp/v20250326"
+	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/util"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -49,6 +56,39 @@ type sseSession struct {
 	done       chan struct{}
 	eventQueue chan string
 	lastActive time.Time
+	// toolsetName is the toolset negotiated when the session was created.
+	// Requests on the session cannot switch to a different toolset.
+	toolsetName string
+	// protocol is the MCP protocol version negotiated for the session.
+	protocol string
+	// streamOpen is set while a client holds the GET stream of a streamable
+	// HTTP session open.
+	streamOpen atomic.Bool
+	closeOnce  sync.Once
+	*mcpSession
+}
+
+// send queues a JSON-RPC message to be written to the session's event stream.
+// For streamable HTTP sessions, messages are buffered until the client opens
+// the GET stream.
+func (s *sseSession) send(message any) error {
+	eventData, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message to JSON: %w", err)
+	}
+	select {
+	case s.eventQueue <- fmt.Sprintf("event: message\ndata: %s\n\n", eventData):
+		return nil
+	case <-s.done:
+		return fmt.Errorf("session is closed")
+	default:
+		return fmt.Errorf("unable to add to event queue")
+	}
+}
+
+// close terminates the session and any stream waiting on it.
+func (s *sseSession) close() {
+	s.closeOnce.Do(func() { close(s.done) })
 }
 
 // sseManager manages and control access to sse sessions
@@ -90,8 +130,27 @@ func (m *sseManager) add(id string, session *sseSession) {
 
 func (m *sseManager) remove(id string) {
 	m.mu.Lock()
+	session, ok := m.sseSessions[id]
 	delete(m.sseSessions, id)
 	m.mu.Unlock()
+	if ok && session != nil {
+		session.close()
+		// tasks do not outlive their session
+		session.tasks.CancelAll()
+	}
+}
+
+// list returns the sessions that are currently live.
+func (m *sseManager) list() []*sseSession {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sessions := make([]*sseSession, 0, len(m.sseSessions))
+	for _, session := range m.sseSessions {
+		if session != nil {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions
 }
 
 func (m *sseManager) cleanupRoutine(ctx context.Context) {
@@ -104,25 +163,40 @@ func (m *sseManager) cleanupRoutine(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			func() {
-				m.mu.Lock()
-				defer m.mu.Unlock()
-				now := time.Now()
-				for id, sess := range m.sseSessions {
-					if now.Sub(sess.lastActive) > timeout {
-						delete(m.sseSessions, id)
-					}
-				}
-			}()
+			m.removeIdle(timeout)
+		}
+	}
+}
+
+// removeIdle removes the sessions that have not been active within timeout.
+func (m *sseManager) removeIdle(timeout time.Duration) {
+	m.mu.Lock()
+	now := time.Now()
+	var idle []string
+	for id, sess := range m.sseSessions {
+		// sessions with an open stream are still in use
+		if sess == nil || sess.streamOpen.Load() {
+			continue
 		}
+		if now.Sub(sess.lastActive) > timeout {
+			idle = append(idle, id)
+		}
+	}
+	m.mu.Unlock()
+	for _, id := range idle {
+		m.remove(id)
 	}
 }
 
 type stdioSession struct {
-	protocol string
-	server   *Server
-	reader   *bufio.Reader
-	writer   io.Writer
+	protocolMu sync.Mutex
+	protocol   string
+	server     *Server
+	reader     *bufio.Reader
+	writer     io.Writer
+	// writeMu serializes writes of responses and server messages to stdout.
+	writeMu sync.Mutex
+	*mcpSession
 }
 
 // traceContextCarrier implements propagation.TextMapCarrier for extracting trace context from _meta
@@ -176,14 +250,17 @@ func extractTraceContext(ctx context.Context, body []byte) context.Context {
 
 func NewStdioSession(s *Server, stdin io.Reader, stdout io.Writer) *stdioSession {
 	stdioSession := &stdioSession{
-		server: s,
-		reader: bufio.NewReader(stdin),
-		writer: stdout,
+		server:     s,
+		reader:     bufio.NewReader(stdin),
+		writer:     stdout,
+		mcpSession: newMcpSession(s.taskPool),
 	}
 	return stdioSession
 }
 
 func (s *stdioSession) Start(ctx context.Context) error {
+	s.server.addStdioSession(s)
+	defer s.server.removeStdioSession(s)
 	return s.readInputStream(ctx)
 }
 
@@ -204,8 +281,8 @@ func (s *stdioSession) readInputStream(ctx context.Context) error {
 	defer func() {
 		// Build full attributes including mcp.protocol.version if negotiated
 		fullAttrs := sessionAttrs
-		if s.protocol != "" {
-			fullAttrs = append(fullAttrs, attribute.String("mcp.protocol.version", s.protocol))
+		if protocol := s.getProtocol(); protocol != "" {
+			fullAttrs = append(fullAttrs, attribute.String("mcp.protocol.version", protocol))
 		}
 
 		// Decrement active sessions counter
@@ -221,6 +298,27 @@ func (s *stdioSession) readInputStream(ctx context.Context) error {
 		s.server.instrumentation.McpSessionDuration.Record(ctx, sessionDuration, metric.WithAttributes(durationAttrs...))
 	}()
 
+	// Messages are processed in order by a single worker so that the reader can
+	// keep handling cancellation notifications and responses to server requests
+	// for in-flight requests.
+	lines := make(chan string, 100)
+	workerErr := make(chan error, 1)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for line := range lines {
+			if err := s.processLine(ctx, line); err != nil {
+				workerErr <- err
+				return
+			}
+		}
+	}()
+	defer func() {
+		close(lines)
+		wg.Wait()
+	}()
+
 	for {
 		if err = ctx.Err(); err != nil {
 			return err
@@ -235,42 +333,68 @@ func (s *stdioSession) readInputStream(ctx context.Context) error {
 			return err
 		}
 
-		if err := func() error {
-			// This ensures the transport span becomes a child of the client span
-			msgCtx := extractTraceContext(ctx, []byte(line))
-
-			// Create span for STDIO transport
-			msgCtx, span := s.server.instrumentation.Tracer.Start(msgCtx, "toolbox/server/mcp/stdio",
-				trace.WithSpanKind(trace.SpanKindServer),
-			)
-			defer span.End()
-
-			var v string
-			var res any
-			v, res, err = processMcpMessage(msgCtx, []byte(line), s.server, s.protocol, "", "", nil, "")
-			if err != nil {
-				// errors during the processing of message will generate a valid MCP Error response.
-				// server can continue to run.
-				s.server.logger.ErrorContext(msgCtx, err.Error())
-				span.SetStatus(codes.Error, err.Error())
+		if isCancelledNotification([]byte(line)) || isClientResponse([]byte(line)) {
+			if err = s.processLine(ctx, line); err != nil {
+				return err
 			}
+			continue
+		}
 
-			if v != "" {
-				s.protocol = v
-			}
-			// no responses for notifications
-			if res != nil {
-				if err = s.write(msgCtx, res); err != nil {
-					return err
-				}
-			}
-			return nil
-		}(); err != nil {
+		select {
+		case lines <- line:
+		case err = <-workerErr:
+			return err
+		case <-ctx.Done():
+			err = ctx.Err()
 			return err
 		}
 	}
 }
 
+// processLine processes a single message and writes the response, if any, to
+// stdout.
+func (s *stdioSession) processLine(ctx context.Context, line string) error {
+	// This ensures the transport span becomes a child of the client span
+	msgCtx := extractTraceContext(ctx, []byte(line))
+
+	// Create span for STDIO transport
+	msgCtx, span := s.server.instrumentation.Tracer.Start(msgCtx, "toolbox/server/mcp/stdio",
+		trace.WithSpanKind(trace.SpanKindServer),
+	)
+	defer span.End()
+	msgCtx = util.WithMessageSender(msgCtx, s.write)
+
+	header := s.server.stdioHeader(msgCtx, []byte(line))
+	v, res, err := processMcpMessage(msgCtx, []byte(line), s.server, s.getProtocol(), "", "", header, "", s.mcpSession)
+	if err != nil {
+		// errors during the processing of message will generate a valid MCP Error response.
+		// server can continue to run.
+		s.server.logger.ErrorContext(msgCtx, err.Error())
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	if v != "" {
+		s.setProtocol(v)
+	}
+	// no responses for notifications
+	if res != nil {
+		return s.write(msgCtx, res)
+	}
+	return nil
+}
+
+func (s *stdioSession) getProtocol() string {
+	s.protocolMu.Lock()
+	defer s.protocolMu.Unlock()
+	return s.protocol
+}
+
+func (s *stdioSession) setProtocol(protocol string) {
+	s.protocolMu.Lock()
+	defer s.protocolMu.Unlock()
+	s.protocol = protocol
+}
+
 // readLine process each line within the input stream.
 func (s *stdioSession) readLine(ctx context.Context) (string, error) {
 	readChan := make(chan string, 1)
@@ -321,6 +445,8 @@ func (s *stdioSession) write(_ context.Context, response any) error {
 		return fmt.Errorf("failed to marshal response to JSON: %w", err)
 	}
 
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
 	_, err = fmt.Fprintf(s.writer, "%s\n", res)
 	return err
 }
@@ -333,16 +459,28 @@ func mcpRouter(s *Server) (chi.Router, error) {
 	r.Use(middleware.StripSlashes)
 	r.Use(render.SetContentType(render.ContentTypeJSON))
 
-	r.Get("/sse", func(w http.ResponseWriter, r *http.Request) { sseHandler(s, w, r) })
-	r.Get("/", func(w http.ResponseWriter, r *http.Request) { methodNotAllowed(s, w, r) })
-	r.Post("/", func(w http.ResponseWriter, r *http.Request) { httpHandler(s, w, r) })
-	r.Delete("/", func(w http.ResponseWriter, r *http.Request) {})
+	// with OAuth, every request requires a bearer token with a scope that
+	// grants access to the toolset
+	authorizeToolset := func(next http.Handler) http.Handler { return next }
+	if s.oauth != nil {
+		r.Use(s.oauth.authenticate)
+		authorizeToolset = s.oauth.authorizeToolset
+	}
+
+	r.Group(func(r chi.Router) {
+		r.Use(authorizeToolset)
+		r.Get("/sse", func(w http.ResponseWriter, r *http.Request) { sseHandler(s, w, r) })
+		r.Get("/", func(w http.ResponseWriter, r *http.Request) { streamHandler(s, w, r) })
+		r.Post("/", func(w http.ResponseWriter, r *http.Request) { httpHandler(s, w, r) })
+		r.Delete("/", func(w http.ResponseWriter, r *http.Request) { deleteHandler(s, w, r) })
+	})
 
 	r.Route("/{toolsetName}", func(r chi.Router) {
+		r.Use(authorizeToolset)
 		r.Get("/sse", func(w http.ResponseWriter, r *http.Request) { sseHandler(s, w, r) })
-		r.Get("/", func(w http.ResponseWriter, r *http.Request) { methodNotAllowed(s, w, r) })
+		r.Get("/", func(w http.ResponseWriter, r *http.Request) { streamHandler(s, w, r) })
 		r.Post("/", func(w http.ResponseWriter, r *http.Request) { httpHandler(s, w, r) })
-		r.Delete("/", func(w http.ResponseWriter, r *http.Request) {})
+		r.Delete("/", func(w http.ResponseWriter, r *http.Request) { deleteHandler(s, w, r) })
 	})
 
 	return r, nil
@@ -404,10 +542,13 @@ func sseHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		_ = render.Render(w, r, newErrResponse(err, http.StatusInternalServerError))
 	}
 	session := &sseSession{
-		writer:     w,
-		flusher:    flusher,
-		done:       make(chan struct{}),
-		eventQueue: make(chan string, 100),
+		writer:      w,
+		flusher:     flusher,
+		done:        make(chan struct{}),
+		eventQueue:  make(chan string, 100),
+		toolsetName: toolsetName,
+		protocol:    v20241105.PROTOCOL_VERSION,
+		mcpSession:  newMcpSession(s.taskPool),
 	}
 	s.sseManager.add(sessionId, session)
 	defer s.sseManager.remove(sessionId)
@@ -442,18 +583,225 @@ func sseHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 			flusher.Flush()
 			// channel for client disconnection
 		case <-clientClose:
-			close(session.done)
+			session.close()
 			s.logger.DebugContext(ctx, "client disconnected")
 			return
 		}
 	}
 }
 
-// methodNotAllowed handles all mcp messages.
-func methodNotAllowed(s *Server, w http.ResponseWriter, r *http.Request) {
-	err := fmt.Errorf("toolbox does not support streaming in streamable HTTP transport")
-	s.logger.DebugContext(r.Context(), err.Error())
-	_ = render.Render(w, r, newErrResponse(err, http.StatusMethodNotAllowed))
+// acceptsEventStream returns true if the client accepts `text/event-stream`
+// responses.
+func acceptsEventStream(r *http.Request) bool {
+	for _, accept := range r.Header.Values("Accept") {
+		for _, mediaType := range strings.Split(accept, ",") {
+			mediaType, _, _ = strings.Cut(mediaType, ";")
+			if strings.TrimSpace(mediaType) == "text/event-stream" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// streamHandler opens the server-to-client stream of a streamable HTTP session.
+// Messages that are not related to a specific client request, such as
+// list-changed notifications, are written to this stream.
+func streamHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	ctx, span := s.instrumentation.Tracer.Start(r.Context(), "toolbox/server/mcp/stream",
+		trace.WithSpanKind(trace.SpanKindServer),
+	)
+	r = r.WithContext(ctx)
+
+	var err error
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	if !acceptsEventStream(r) {
+		err = fmt.Errorf("client must accept text/event-stream")
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusNotAcceptable))
+		return
+	}
+
+	sessionId := r.Header.Get("Mcp-Session-Id")
+	if sessionId == "" {
+		err = fmt.Errorf("missing Mcp-Session-Id header")
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+		return
+	}
+	session, ok := s.sseManager.get(sessionId)
+	if !ok {
+		err = fmt.Errorf("session not found")
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusNotFound))
+		return
+	}
+
+	toolsetName := chi.URLParam(r, "toolsetName")
+	span.SetAttributes(attribute.String("mcp.session.id", sessionId))
+	span.SetAttributes(attribute.String("toolset.name", toolsetName))
+	if session.toolsetName != toolsetName {
+		err = fmt.Errorf("toolset %q does not match the toolset of session %s", toolsetName, sessionId)
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusForbidden))
+		return
+	}
+	// legacy sse sessions already deliver messages through their own stream
+	if session.writer != nil {
+		err = fmt.Errorf("session %s does not use the streamable HTTP transport", sessionId)
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		err = fmt.Errorf("unable to retrieve flusher for stream")
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	// only a single stream can be open per session, so that each message is
+	// delivered once
+	if !session.streamOpen.CompareAndSwap(false, true) {
+		err = fmt.Errorf("stream already open for session %s", sessionId)
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusConflict))
+		return
+	}
+	defer session.streamOpen.Store(false)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	clientClose := r.Context().Done()
+	for {
+		select {
+		case event := <-session.eventQueue:
+			fmt.Fprint(w, event)
+			s.logger.DebugContext(ctx, fmt.Sprintf("sending event: %s", event))
+			flusher.Flush()
+		case <-session.done:
+			s.logger.DebugContext(ctx, "session terminated")
+			return
+		case <-clientClose:
+			s.logger.DebugContext(ctx, "client disconnected")
+			return
+		}
+	}
+}
+
+// streamableResponse writes the response to a POST request of the streamable
+// HTTP transport. Messages sent by the server while the request is processed
+// upgrade the response to an SSE stream if the client accepts it. Otherwise
+// they are delivered through the session's GET stream.
+type streamableResponse struct {
+	mu        sync.Mutex
+	w         http.ResponseWriter
+	flusher   http.Flusher
+	session   *sseSession
+	canStream bool
+	streaming bool
+	// finished is set once the response has been written. Later messages go
+	// to the session's GET stream.
+	finished bool
+}
+
+func newStreamableResponse(w http.ResponseWriter, r *http.Request, session *sseSession) *streamableResponse {
+	flusher, ok := w.(http.Flusher)
+	return &streamableResponse{
+		w:         w,
+		flusher:   flusher,
+		session:   session,
+		canStream: ok && acceptsEventStream(r),
+	}
+}
+
+// send writes a message from the server to the client ahead of the response.
+func (sr *streamableResponse) send(_ context.Context, message any) error {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	if !sr.canStream || sr.finished {
+		if sr.session == nil {
+			return fmt.Errorf("no stream available to send message")
+		}
+		return sr.session.send(message)
+	}
+	return sr.writeEvent(message)
+}
+
+// writeEvent writes message as an SSE event, starting the stream if needed.
+// The caller must hold sr.mu.
+func (sr *streamableResponse) writeEvent(message any) error {
+	eventData, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message to JSON: %w", err)
+	}
+	if !sr.streaming {
+		sr.w.Header().Set("Content-Type", "text/event-stream")
+		sr.w.Header().Set("Cache-Control", "no-cache")
+		sr.w.WriteHeader(http.StatusOK)
+		sr.streaming = true
+	}
+	if _, err := fmt.Fprintf(sr.w, "event: message\ndata: %s\n\n", eventData); err != nil {
+		return err
+	}
+	sr.flusher.Flush()
+	return nil
+}
+
+// isStreaming returns true if the response has been upgraded to an SSE stream.
+func (sr *streamableResponse) isStreaming() bool {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	return sr.streaming
+}
+
+// detach stops writing messages to the response once the handler returns.
+func (sr *streamableResponse) detach() {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	sr.finished = true
+}
+
+// finish writes the response as the last event if the response has been
+// upgraded to an SSE stream. It returns false if the response still has to be
+// written as JSON.
+func (sr *streamableResponse) finish(response any) (bool, error) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	sr.finished = true
+	if !sr.streaming {
+		return false, nil
+	}
+	return true, sr.writeEvent(response)
+}
+
+// deleteHandler terminates the streamable HTTP session given by the
+// `Mcp-Session-Id` header.
+func deleteHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	sessionId := r.Header.Get("Mcp-Session-Id")
+	if sessionId == "" {
+		return
+	}
+	if _, ok := s.sseManager.get(sessionId); !ok {
+		err := fmt.Errorf("session not found")
+		s.logger.DebugContext(r.Context(), err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusNotFound))
+		return
+	}
+	s.sseManager.remove(sessionId)
+	s.logger.DebugContext(r.Context(), fmt.Sprintf("session %s terminated", sessionId))
 }
 
 // httpHandler handles all mcp messages.
@@ -502,10 +850,19 @@ func httpHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 	}
 
 	// check if client have `Mcp-Session-Id` header
-	// `Mcp-Session-Id` is only set for v2025-03-26 in Toolbox
+	// `Mcp-Session-Id` is set for v2025-03-26+ in Toolbox
 	headerSessionId := r.Header.Get("Mcp-Session-Id")
-	if headerSessionId != "" {
-		protocolVersion = v20250326.PROTOCOL_VERSION
+	if headerSessionId != "" && session == nil {
+		sessionId = headerSessionId
+		var ok bool
+		session, ok = s.sseManager.get(sessionId)
+		if !ok {
+			err := fmt.Errorf("session not found")
+			s.logger.DebugContext(ctx, err.Error())
+			_ = render.Render(w, r, newErrResponse(err, http.StatusNotFound))
+			return
+		}
+		protocolVersion = session.protocol
 	}
 
 	// check if client have `MCP-Protocol-Version` header
@@ -532,37 +889,83 @@ func httpHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		span.End()
 	}()
 
+	// the toolset is bound to the session and cannot be swapped mid-session
+	if session != nil && session.toolsetName != toolsetName {
+		err = fmt.Errorf("toolset %q does not match the toolset of session %s", toolsetName, sessionId)
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusForbidden))
+		return
+	}
+
 	networkProtocolVersion := fmt.Sprintf("%d.%d", r.ProtoMajor, r.ProtoMinor)
 
-	v, res, err := processMcpMessage(ctx, body, s, protocolVersion, toolsetName, promptsetName, r.Header, networkProtocolVersion)
+	// legacy sse sessions receive all server messages through the event
+	// stream, streamable HTTP requests may upgrade their response to a stream
+	var streamResp *streamableResponse
+	if session != nil && session.writer != nil {
+		ctx = util.WithMessageSender(ctx, func(_ context.Context, message any) error {
+			return session.send(message)
+		})
+	} else {
+		streamResp = newStreamableResponse(w, r, session)
+		defer streamResp.detach()
+		ctx = util.WithMessageSender(ctx, streamResp.send)
+	}
+
+	var mcpSess *mcpSession
+	if session != nil {
+		mcpSess = session.mcpSession
+	}
+
+	v, res, err := processMcpMessage(ctx, body, s, protocolVersion, toolsetName, promptsetName, r.Header, networkProtocolVersion, mcpSess)
 	if err != nil {
 		s.logger.DebugContext(ctx, fmt.Errorf("error processing message: %w", err).Error())
 	}
 
 	// notifications will return empty string
 	if res == nil {
-		// Notifications do not expect a response
-		// Toolbox doesn't do anything with notifications yet
+		// Notifications and cancelled requests do not expect a response
+		if streamResp != nil && streamResp.isStreaming() {
+			return
+		}
 		w.WriteHeader(http.StatusAccepted)
 		return
 	}
 
-	// for v20250326, add the `Mcp-Session-Id` header
-	if v == v20250326.PROTOCOL_VERSION {
+	// for v20250326+, create a session bound to the toolset and add the
+	// `Mcp-Session-Id` header
+	if v != "" && v != v20241105.PROTOCOL_VERSION && session == nil {
 		sessionId = uuid.New().String()
+		mcpSess = newMcpSession(s.taskPool)
+		mcpSess.client.initialize(body, v)
+		s.sseManager.add(sessionId, &sseSession{
+			done:        make(chan struct{}),
+			eventQueue:  make(chan string, 100),
+			toolsetName: toolsetName,
+			protocol:    v,
+			mcpSession:  mcpSess,
+		})
 		w.Header().Set("Mcp-Session-Id", sessionId)
 	}
 
-	if session != nil {
+	// only legacy sse sessions receive responses through the event stream
+	if session != nil && session.writer != nil {
 		// queue sse event
-		eventData, _ := json.Marshal(res)
-		select {
-		case session.eventQueue <- fmt.Sprintf("event: message\ndata: %s\n\n", eventData):
+		if err := session.send(res); err != nil {
+			s.logger.DebugContext(ctx, err.Error())
+		} else {
 			s.logger.DebugContext(ctx, "event queue successful")
-		case <-session.done:
-			s.logger.DebugContext(ctx, "session is close")
-		default:
-			s.logger.DebugContext(ctx, "unable to add to event queue")
+		}
+	}
+
+	// the response is the last event of an upgraded response stream
+	if streamResp != nil {
+		streamed, err := streamResp.finish(res)
+		if err != nil {
+			s.logger.DebugContext(ctx, fmt.Errorf("error writing response event: %w", err).Error())
+		}
+		if streamed {
+			return
 		}
 	}
 	if rpcResponse, ok := res.(jsonrpc.JSONRPCError); ok {
@@ -584,13 +987,31 @@ func httpHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 }
 
 // processMcpMessage process the messages received from clients
-func processMcpMessage(ctx context.Context, body []byte, s *Server, protocolVersion string, toolsetName string, promptsetName string, header http.Header, networkProtocolVersion string) (string, any, error) {
+func processMcpMessage(ctx context.Context, body []byte, s *Server, protocolVersion string, toolsetName string, promptsetName string, header http.Header, networkProtocolVersion string, session *mcpSession) (string, any, error) {
 	operationStart := time.Now()
+	// the resources used by the message are not closed by a reload until it
+	// is processed
+	ctx, release := s.ResourceMgr.Acquire(ctx)
+	defer release()
+
+	var requests *inflightRequests
+	var logging *sessionLogging
+	var client *sessionClient
+	var tasks *mcputil.TaskStore
+	if session != nil {
+		requests, logging, client, tasks = session.requests, session.logging, session.client, session.tasks
+	}
 
 	logger, err := util.LoggerFromContext(ctx)
 	if err != nil {
 		return "", jsonrpc.NewError("", jsonrpc.INTERNAL_ERROR, err.Error(), nil), err
 	}
+	// records logged while processing the message are also sent to the client
+	if logging != nil {
+		logger = log.NewForwardingLogger(logger)
+		ctx = util.WithLogger(ctx, logger)
+		ctx = log.WithForwarder(ctx, logging.forward)
+	}
 
 	// Generic baseMessage could either be a JSONRPCNotification or JSONRPCRequest
 	var baseMessage jsonrpc.BaseMessage
@@ -609,6 +1030,11 @@ func processMcpMessage(ctx context.Context, body []byte, s *Server, protocolVers
 		return "", jsonrpc.NewError(id, jsonrpc.PARSE_ERROR, err.Error(), nil), err
 	}
 
+	// responses to the requests sent to the client do not expect a response
+	if isClientResponse(body) {
+		return "", nil, client.resolve(body)
+	}
+
 	// Check if method is present
 	if baseMessage.Method == "" {
 		err = fmt.Errorf("method not found")
@@ -629,10 +1055,11 @@ func processMcpMessage(ctx context.Context, body []byte, s *Server, protocolVers
 	)
 	defer span.End()
 
-	// Determine network transport and protocol based on header presence
+	// Determine network transport and protocol based on the HTTP version,
+	// which is only known for HTTP requests
 	networkTransport := "pipe" // default for stdio
 	networkProtocolName := "stdio"
-	if header != nil {
+	if networkProtocolVersion != "" {
 		networkTransport = "tcp" // HTTP/SSE transport
 		networkProtocolName = "http"
 	}
@@ -703,6 +1130,13 @@ func processMcpMessage(ctx context.Context, body []byte, s *Server, protocolVers
 
 	// Check if message is a notification
 	if baseMessage.Id == nil {
+		if baseMessage.Method == mcputil.NOTIFICATIONS_CANCELLED {
+			err := requests.cancelFromNotification(body)
+			if err != nil {
+				span.SetStatus(codes.Error, err.Error())
+			}
+			return "", nil, err
+		}
 		err := mcp.NotificationHandler(ctx, body)
 		if err != nil {
 			span.SetStatus(codes.Error, err.Error())
@@ -716,7 +1150,9 @@ func processMcpMessage(ctx context.Context, body []byte, s *Server, protocolVers
 	// Process the method
 	switch baseMessage.Method {
 	case mcputil.INITIALIZE:
-		result, version, err := mcp.InitializeResponse(ctx, baseMessage.Id, body, s.version)
+		// the server metadata may be overridden by the toolset of the session
+		toolset, _ := s.ResourceMgr.GetToolset(toolsetName)
+		result, version, err := mcp.InitializeResponse(ctx, baseMessage.Id, body, s.version, toolset.ServerMetadata)
 		if err != nil {
 			span.SetStatus(codes.Error, err.Error())
 			if rpcErr, ok := result.(jsonrpc.JSONRPCError); ok {
@@ -726,7 +1162,18 @@ func processMcpMessage(ctx context.Context, body []byte, s *Server, protocolVers
 			return "", result, err
 		}
 		span.SetAttributes(attribute.String("mcp.protocol.version", version))
+		client.initialize(body, version)
 		return version, result, err
+	case mcputil.LOGGING_SET_LEVEL:
+		result, err := logging.setLevel(baseMessage.Id, body)
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			if rpcErr, ok := result.(jsonrpc.JSONRPCError); ok {
+				metricErrorType = rpcErr.Error.String()
+				span.SetAttributes(attribute.String("error.type", metricErrorType))
+			}
+		}
+		return "", result, err
 	default:
 		toolset, ok := s.ResourceMgr.GetToolset(toolsetName)
 		if !ok {
@@ -746,7 +1193,23 @@ func processMcpMessage(ctx context.Context, body []byte, s *Server, protocolVers
 			span.SetAttributes(attribute.String("error.type", metricErrorType))
 			return "", rpcErr, err
 		}
+		// the client can cancel the request while it is processed
+		ctx, done := requests.track(ctx, baseMessage.Id)
+		defer done()
+		ctx = withProgressReporter(ctx, body, protocolVersion)
+		ctx = util.WithPageSize(ctx, s.pageSize)
+		ctx = client.withRequester(ctx)
+		if tasks != nil {
+			ctx = mcputil.WithTaskStore(ctx, tasks)
+		}
+
 		result, err := mcp.ProcessMethod(ctx, protocolVersion, baseMessage.Id, baseMessage.Method, toolset, promptset, s.ResourceMgr, body, header)
+		if errors.Is(context.Cause(ctx), errRequestCancelled) {
+			// no response is sent for cancelled requests
+			err := fmt.Errorf("request %v cancelled by client", baseMessage.Id)
+			span.SetStatus(codes.Error, err.Error())
+			return "", nil, err
+		}
 		if err != nil {
 			span.SetStatus(codes.Error, err.Error())
 			// Set error.type based on JSON-RPC error code
@@ -759,3 +1222,371 @@ func processMcpMessage(ctx context.Context, body []byte, s *Server, protocolVers
 		return "", result, err
 	}
 }
+
+// mcpSession is the state of an MCP session that is shared by the messages of
+// the session, regardless of its transport. A nil *mcpSession belongs to a
+// request without a session.
+type mcpSession struct {
+	// requests are the in-flight requests of the session that the client can
+	// cancel.
+	requests *inflightRequests
+	// logging is the level of the log messages sent to the client.
+	logging *sessionLogging
+	// client holds the capabilities of the client and the requests sent to it.
+	client *sessionClient
+	// tasks are the tool calls that run in the background.
+	tasks *mcputil.TaskStore
+}
+
+func newMcpSession(taskPool *mcputil.TaskPool) *mcpSession {
+	return &mcpSession{
+		requests: newInflightRequests(),
+		logging:  &sessionLogging{},
+		client:   newSessionClient(),
+		tasks:    mcputil.NewTaskStore(taskPool),
+	}
+}
+
+// errRequestCancelled is the cause of the context of a request cancelled by the
+// client.
+var errRequestCancelled = errors.New("request cancelled by client")
+
+// inflightRequests tracks the requests of a session that are being processed,
+// so that they can be cancelled with `notifications/cancelled`. A nil
+// *inflightRequests does not track requests.
+type inflightRequests struct {
+	mu      sync.Mutex
+	cancels map[string]context.CancelCauseFunc
+}
+
+func newInflightRequests() *inflightRequests {
+	return &inflightRequests{cancels: make(map[string]context.CancelCauseFunc)}
+}
+
+// requestKey returns the key of a request id decoded with util.DecodeJSON.
+// Ids of different JSON types do not collide.
+func requestKey(id jsonrpc.RequestId) string {
+	return fmt.Sprintf("%T:%v", id, id)
+}
+
+// track returns a context that is cancelled when the client cancels the
+// request, and a function to call once the request is processed.
+func (r *inflightRequests) track(ctx context.Context, id jsonrpc.RequestId) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	if r == nil {
+		return ctx, func() { cancel(nil) }
+	}
+	key := requestKey(id)
+	r.mu.Lock()
+	r.cancels[key] = cancel
+	r.mu.Unlock()
+	return ctx, func() {
+		r.mu.Lock()
+		delete(r.cancels, key)
+		r.mu.Unlock()
+		cancel(nil)
+	}
+}
+
+// cancelFromNotification cancels the request identified by a
+// `notifications/cancelled` message. Unknown or finished requests are ignored.
+func (r *inflightRequests) cancelFromNotification(body []byte) error {
+	var notification mcputil.CancelledNotification
+	if err := util.DecodeJSON(bytes.NewBuffer(body), &notification); err != nil {
+		return fmt.Errorf("invalid notification request: %w", err)
+	}
+	if r == nil || notification.Params.RequestId == nil {
+		return nil
+	}
+	r.mu.Lock()
+	cancel, ok := r.cancels[requestKey(notification.Params.RequestId)]
+	r.mu.Unlock()
+	if ok {
+		cancel(errRequestCancelled)
+	}
+	return nil
+}
+
+// sessionLogging holds the level of the log messages sent to the client of a
+// session. No log messages are sent until the client sets a level with
+// `logging/setLevel`.
+type sessionLogging struct {
+	mu      sync.Mutex
+	enabled bool
+	level   slog.Level
+}
+
+// setLevel handles a `logging/setLevel` request. A nil *sessionLogging belongs
+// to a request without a session, for which no level can be kept.
+func (l *sessionLogging) setLevel(id jsonrpc.RequestId, body []byte) (any, error) {
+	if l == nil {
+		err := fmt.Errorf("%s requires a session", mcputil.LOGGING_SET_LEVEL)
+		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
+	}
+	var req mcputil.SetLevelRequest
+	if err := util.DecodeJSON(bytes.NewBuffer(body), &req); err != nil {
+		err = fmt.Errorf("invalid mcp logging/setLevel request: %w", err)
+		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
+	}
+	level, err := mcputil.SlogLevel(req.Params.Level)
+	if err != nil {
+		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
+	}
+
+	l.mu.Lock()
+	l.enabled = true
+	l.level = level
+	l.mu.Unlock()
+	return jsonrpc.JSONRPCResponse{
+		Jsonrpc: jsonrpc.JSONRPC_VERSION,
+		Id:      id,
+		Result:  jsonrpc.Result{},
+	}, nil
+}
+
+// forward implements log.Forwarder. Records at or above the level set by the
+// client are sent as `notifications/message` over the transport of the request
+// that logged them.
+func (l *sessionLogging) forward(ctx context.Context, level slog.Level, msg string, keysAndValues ...any) {
+	l.mu.Lock()
+	enabled, minLevel := l.enabled, l.level
+	l.mu.Unlock()
+	if !enabled || level < minLevel {
+		return
+	}
+	send, err := util.MessageSenderFromContext(ctx)
+	if err != nil {
+		return
+	}
+	// a failure to deliver a log message does not fail the request
+	_ = send(ctx, mcputil.NewLoggingMessageNotification(level, msg, keysAndValues...))
+}
+
+// sessionClient holds the capabilities the client announced when it
+// initialized the session, and the requests sent to the client that are
+// waiting for its response. A nil *sessionClient cannot send requests.
+type sessionClient struct {
+	mu           sync.Mutex
+	capabilities mcputil.ClientCapabilities
+	pending      map[string]chan clientResponse
+}
+
+func newSessionClient() *sessionClient {
+	return &sessionClient{pending: make(map[string]chan clientResponse)}
+}
+
+// clientResponse is the response of the client to a request of the server.
+type clientResponse struct {
+	Id     jsonrpc.RequestId `json:"id"`
+	Result json.RawMessage   `json:"result,omitempty"`
+	Error  *jsonrpc.Error    `json:"error,omitempty"`
+}
+
+// initialize records the capabilities of the client from its initialize
+// request. Capabilities that are not supported by the negotiated protocol
+// version are ignored.
+func (c *sessionClient) initialize(body []byte, protocolVersion string) {
+	if c == nil {
+		return
+	}
+	var req mcputil.InitializeRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return
+	}
+	capabilities := req.Params.Capabilities
+	// elicitation was introduced in v2025-06-18
+	if protocolVersion == v20241105.PROTOCOL_VERSION || protocolVersion == v20250326.PROTOCOL_VERSION {
+		capabilities.Elicitation = nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.capabilities = capabilities
+}
+
+// withRequester adds the capabilities of the client and a ClientRequester
+// that sends requests over the transport of the current request to the
+// context.
+func (c *sessionClient) withRequester(ctx context.Context) context.Context {
+	if c == nil {
+		return ctx
+	}
+	c.mu.Lock()
+	capabilities := c.capabilities
+	c.mu.Unlock()
+	ctx = mcputil.WithClientCapabilities(ctx, capabilities)
+	return util.WithClientRequester(ctx, c.request)
+}
+
+// request implements util.ClientRequester.
+func (c *sessionClient) request(ctx context.Context, method string, params any) (json.RawMessage, error) {
+	send, err := util.MessageSenderFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id := uuid.New().String()
+	responses := make(chan clientResponse, 1)
+	c.mu.Lock()
+	c.pending[id] = responses
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	req := jsonrpc.JSONRPCRequest{
+		Jsonrpc: jsonrpc.JSONRPC_VERSION,
+		Id:      id,
+		Request: jsonrpc.Request{Method: method},
+		Params:  params,
+	}
+	if err := send(ctx, req); err != nil {
+		return nil, fmt.Errorf("unable to send %s request: %w", method, err)
+	}
+	select {
+	case resp := <-responses:
+		if resp.Error != nil {
+			return nil, fmt.Errorf("client returned an error for %s request: %s", method, resp.Error.Message)
+		}
+		return resp.Result, nil
+	case <-ctx.Done():
+		return nil, context.Cause(ctx)
+	}
+}
+
+// resolve passes the response of the client to the request waiting for it.
+func (c *sessionClient) resolve(body []byte) error {
+	var resp clientResponse
+	if err := util.DecodeJSON(bytes.NewBuffer(body), &resp); err != nil {
+		return fmt.Errorf("invalid response: %w", err)
+	}
+	id, ok := resp.Id.(string)
+	if c == nil || !ok {
+		return fmt.Errorf("response to unknown request %v", resp.Id)
+	}
+	c.mu.Lock()
+	responses, ok := c.pending[id]
+	delete(c.pending, id)
+	c.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("response to unknown request %v", resp.Id)
+	}
+	responses <- resp
+	return nil
+}
+
+// isClientResponse returns true if body is a response of the client to a
+// request of the server.
+func isClientResponse(body []byte) bool {
+	var msg struct {
+		Method string            `json:"method"`
+		Id     jsonrpc.RequestId `json:"id"`
+		Result json.RawMessage   `json:"result"`
+		Error  json.RawMessage   `json:"error"`
+	}
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return false
+	}
+	return msg.Method == "" && msg.Id != nil && (msg.Result != nil || msg.Error != nil)
+}
+
+// isCancelledNotification returns true if body is a `notifications/cancelled`
+// message.
+func isCancelledNotification(body []byte) bool {
+	var baseMessage jsonrpc.BaseMessage
+	if err := json.Unmarshal(body, &baseMessage); err != nil {
+		return false
+	}
+	return baseMessage.Id == nil && baseMessage.Method == mcputil.NOTIFICATIONS_CANCELLED
+}
+
+// withProgressReporter adds a progress reporter to the context if the request
+// has a progress token. Progress notifications are sent over the transport of
+// the request.
+func withProgressReporter(ctx context.Context, body []byte, protocolVersion string) context.Context {
+	var req jsonrpc.Request
+	if err := json.Unmarshal(body, &req); err != nil || req.Params.Meta.ProgressToken == nil {
+		return ctx
+	}
+	send, err := util.MessageSenderFromContext(ctx)
+	if err != nil {
+		return ctx
+	}
+	token := req.Params.Meta.ProgressToken
+	return util.WithProgressReporter(ctx, func(ctx context.Context, progress float64, total float64, message string) error {
+		// progress messages are only supported for v2025-03-26+
+		if protocolVersion == v20241105.PROTOCOL_VERSION {
+			message = ""
+		}
+		return send(ctx, mcputil.NewProgressNotification(token, progress, total, message))
+	})
+}
+
+// NotifyListChanged notifies the live MCP sessions that the tools or prompts
+// available to them changed since previousToolsets and previousPromptsets were
+// loaded. notifications/tools/list_changed is only sent to the sessions whose
+// toolset changed. It is called after the resources are dynamically reloaded.
+func (s *Server) NotifyListChanged(ctx context.Context, previousToolsets map[string]tools.Toolset, previousPromptsets map[string]prompts.Promptset) {
+	toolsetsChanged := make(map[string]bool)
+	toolsetChanged := func(name string) bool {
+		changed, ok := toolsetsChanged[name]
+		if !ok {
+			previous, hadToolset := previousToolsets[name]
+			current, hasToolset := s.ResourceMgr.GetToolset(name)
+			changed = hadToolset != hasToolset || !reflect.DeepEqual(previous.McpManifest, current.McpManifest)
+			toolsetsChanged[name] = changed
+		}
+		return changed
+	}
+	// all sessions use the default promptset
+	previousPromptset, hadPromptset := previousPromptsets[""]
+	currentPromptset, hasPromptset := s.ResourceMgr.GetPromptset("")
+	promptsChanged := hadPromptset != hasPromptset || !reflect.DeepEqual(previousPromptset.McpManifest, currentPromptset.McpManifest)
+
+	notify := func(toolsetName string, send func(message any) error) {
+		if toolsetChanged(toolsetName) {
+			if err := send(mcputil.NewListChangedNotification(mcputil.NOTIFICATIONS_TOOLS_LIST_CHANGED)); err != nil {
+				s.logger.DebugContext(ctx, fmt.Sprintf("unable to send tools list changed notification: %s", err))
+			}
+		}
+		if promptsChanged {
+			if err := send(mcputil.NewListChangedNotification(mcputil.NOTIFICATIONS_PROMPTS_LIST_CHANGED)); err != nil {
+				s.logger.DebugContext(ctx, fmt.Sprintf("unable to send prompts list changed notification: %s", err))
+			}
+		}
+	}
+
+	for _, session := range s.sseManager.list() {
+		notify(session.toolsetName, session.send)
+	}
+
+	s.stdioMu.Lock()
+	stdioSessions := make([]*stdioSession, 0, len(s.stdioSessions))
+	for session := range s.stdioSessions {
+		stdioSessions = append(stdioSessions, session)
+	}
+	s.stdioMu.Unlock()
+	for _, session := range stdioSessions {
+		// sessions that are not initialized do not receive notifications
+		if session.getProtocol() == "" {
+			continue
+		}
+		notify("", func(message any) error { return session.write(ctx, message) })
+	}
+}
+
+func (s *Server) addStdioSession(session *stdioSession) {
+	s.stdioMu.Lock()
+	defer s.stdioMu.Unlock()
+	if s.stdioSessions == nil {
+		s.stdioSessions = make(map[*stdioSession]struct{})
+	}
+	s.stdioSessions[session] = struct{}{}
+}
+
+func (s *Server) removeStdioSession(session *stdioSession) {
+	s.stdioMu.Lock()
+	defer s.stdioMu.Unlock()
+	delete(s.stdioSessions, session)
+	session.tasks.CancelAll()
+}
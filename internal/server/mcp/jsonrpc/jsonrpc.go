@@ -26,6 +26,11 @@ const (
 	INTERNAL_ERROR   = -32603
 )
 
+// MCP specific error codes
+const (
+	RESOURCE_NOT_FOUND = -32002
+)
+
 // ProgressToken is used to associate progress notifications with the original request.
 type ProgressToken interface{}
 
@@ -46,8 +46,9 @@ var SUPPORTED_PROTOCOL_VERSIONS = []string{
 
 // InitializeResponse runs capability negotiation and protocol version agreement.
 // This is the Initialization phase of the lifecycle for MCP client-server connections.
-// Always start with the latest protocol version supported.
-func InitializeResponse(ctx context.Context, id jsonrpc.RequestId, body []byte, toolboxVersion string) (any, string, error) {
+// Always start with the latest protocol version supported. The server metadata
+// is included as far as the agreed protocol version supports it.
+func InitializeResponse(ctx context.Context, id jsonrpc.RequestId, body []byte, toolboxVersion string, metadata tools.ServerMetadata) (any, string, error) {
 	var req mcputil.InitializeRequest
 	if err := json.Unmarshal(body, &req); err != nil {
 		err = fmt.Errorf("invalid mcp initialize request: %w", err)
@@ -62,8 +63,10 @@ func InitializeResponse(ctx context.Context, id jsonrpc.RequestId, body []byte,
 		protocolVersion = LATEST_PROTOCOL_VERSION
 	}
 
-	toolsListChanged := false
-	promptsListChanged := false
+	// clients are notified when tools and prompts change on dynamic reload
+	toolsListChanged := true
+	promptsListChanged := true
+	resourcesListChanged := false
 	result := mcputil.InitializeResult{
 		ProtocolVersion: protocolVersion,
 		Capabilities: mcputil.ServerCapabilities{
@@ -73,6 +76,10 @@ func InitializeResponse(ctx context.Context, id jsonrpc.RequestId, body []byte,
 			Prompts: &mcputil.ListChanged{
 				ListChanged: &promptsListChanged,
 			},
+			Resources: &mcputil.ListChanged{
+				ListChanged: &resourcesListChanged,
+			},
+			Logging: &struct{}{},
 		},
 		ServerInfo: mcputil.Implementation{
 			BaseMetadata: mcputil.BaseMetadata{
@@ -80,6 +87,20 @@ func InitializeResponse(ctx context.Context, id jsonrpc.RequestId, body []byte,
 			},
 			Version: toolboxVersion,
 		},
+		Instructions: metadata.Instructions,
+	}
+	// titles were introduced in v2025-06-18
+	if protocolVersion != v20241105.PROTOCOL_VERSION && protocolVersion != v20250326.PROTOCOL_VERSION {
+		result.ServerInfo.Title = metadata.Title
+	}
+	// the completions capability was introduced in v2025-03-26
+	if protocolVersion != v20241105.PROTOCOL_VERSION {
+		result.Capabilities.Completions = &struct{}{}
+	}
+	// tasks were introduced in v2025-11-25
+	if protocolVersion == v20251125.PROTOCOL_VERSION {
+		result.Capabilities.Tasks = mcputil.NewTaskCapabilities()
+		result.ServerInfo.Icons = metadata.Icons
 	}
 	res := jsonrpc.JSONRPCResponse{
 		Jsonrpc: jsonrpc.JSONRPC_VERSION,
@@ -0,0 +1,50 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+// ClaimsFromHeader returns the claims verified by each auth service from the
+// header, by auth service name. Auth services that fail to verify the header
+// are skipped.
+func ClaimsFromHeader(ctx context.Context, authServices map[string]auth.AuthService, header http.Header) map[string]map[string]any {
+	claimsFromAuth := make(map[string]map[string]any)
+	// if using stdio without identity material, header will be nil
+	if header == nil {
+		return claimsFromAuth
+	}
+	logger, _ := util.LoggerFromContext(ctx)
+	for _, aS := range authServices {
+		claims, err := aS.GetClaimsFromHeader(ctx, header)
+		if err != nil {
+			if logger != nil {
+				logger.DebugContext(ctx, err.Error())
+			}
+			continue
+		}
+		if claims == nil {
+			// authService not present in header
+			continue
+		}
+		claimsFromAuth[aS.GetName()] = claims
+	}
+	return claimsFromAuth
+}
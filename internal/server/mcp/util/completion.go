@@ -0,0 +1,237 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/googleapis/genai-toolbox/internal/mcpresources"
+	"github.com/googleapis/genai-toolbox/internal/prompts"
+	"github.com/googleapis/genai-toolbox/internal/server/resources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"github.com/googleapis/genai-toolbox/internal/util/orderedmap"
+	"github.com/googleapis/genai-toolbox/internal/util/parameters"
+)
+
+const (
+	// references that can be completed
+	REF_PROMPT   = "ref/prompt"
+	REF_RESOURCE = "ref/resource"
+
+	// MAX_COMPLETION_VALUES is the maximum number of values returned in a
+	// completion result.
+	MAX_COMPLETION_VALUES = 100
+)
+
+/* Completion */
+
+// CompletionReference identifies the prompt or resource template whose
+// argument is completed.
+type CompletionReference struct {
+	// Either REF_PROMPT or REF_RESOURCE.
+	Type string `json:"type"`
+	// The name of the prompt, for REF_PROMPT.
+	Name string `json:"name,omitempty"`
+	// The URI template of the resource, for REF_RESOURCE.
+	URI string `json:"uri,omitempty"`
+}
+
+// CompletionArgument is the argument the client requests completions for.
+type CompletionArgument struct {
+	// The name of the argument.
+	Name string `json:"name"`
+	// The value of the argument to use for completion matching.
+	Value string `json:"value"`
+}
+
+// Completion is the list of values suggested for an argument.
+type Completion struct {
+	// An array of completion values. Must not exceed 100 items.
+	Values []string `json:"values"`
+	// The total number of completion options available. This can exceed the
+	// number of values actually sent in the response.
+	Total int `json:"total,omitempty"`
+	// Indicates whether there are additional completion options beyond those
+	// provided in the current response.
+	HasMore bool `json:"hasMore,omitempty"`
+}
+
+// FindCompletion returns how values are suggested for the argument of the
+// prompt or resource template identified by ref. Prompts are looked up in the
+// promptset, and resource templates among the resources of the toolset. It
+// returns nil if the argument has no suggestions.
+func FindCompletion(toolset tools.Toolset, promptset prompts.Promptset, resourceMgr *resources.ResourceManager, ref CompletionReference, argument string) (*parameters.Completion, error) {
+	switch ref.Type {
+	case REF_PROMPT:
+		prompt, ok := promptset.GetPrompt(ref.Name)
+		if !ok {
+			return nil, fmt.Errorf("prompt with name %q does not exist", ref.Name)
+		}
+		for _, arg := range prompt.GetArguments() {
+			if arg.GetName() == argument {
+				return arg.GetCompletion(), nil
+			}
+		}
+		return nil, fmt.Errorf("prompt %q does not have an argument named %q", ref.Name, argument)
+	case REF_RESOURCE:
+		for _, r := range ToolsetResources(toolset, resourceMgr) {
+			if r.McpManifest().URITemplate != ref.URI {
+				continue
+			}
+			completer, ok := r.(mcpresources.TemplateCompleter)
+			if !ok {
+				return nil, nil
+			}
+			return completer.GetCompletion(argument), nil
+		}
+		return nil, fmt.Errorf("resource template %q does not exist", ref.URI)
+	default:
+		return nil, fmt.Errorf("invalid reference type %q", ref.Type)
+	}
+}
+
+// Complete returns the values suggested by completion that start with the
+// value of argument. contextArgs are the arguments the client has already
+// filled in, which are passed to the completion tool. The completion tool is
+// invoked through the toolset of the request.
+func Complete(ctx context.Context, toolset tools.Toolset, resourceMgr *resources.ResourceManager, completion *parameters.Completion, argument CompletionArgument, contextArgs map[string]string) (Completion, error) {
+	var candidates []string
+	if completion != nil {
+		for _, v := range completion.Values {
+			candidates = append(candidates, fmt.Sprint(v))
+		}
+		if completion.Tool != "" {
+			values, err := completionToolValues(ctx, toolset, resourceMgr, completion.Tool, argument.Name, contextArgs)
+			if err != nil {
+				return Completion{}, err
+			}
+			candidates = append(candidates, values...)
+		}
+	}
+
+	prefix := strings.ToLower(argument.Value)
+	seen := make(map[string]bool)
+	values := make([]string, 0)
+	for _, c := range candidates {
+		if seen[c] || !strings.HasPrefix(strings.ToLower(c), prefix) {
+			continue
+		}
+		seen[c] = true
+		values = append(values, c)
+	}
+
+	result := Completion{Values: values, Total: len(values)}
+	if len(values) > MAX_COMPLETION_VALUES {
+		result.Values = values[:MAX_COMPLETION_VALUES]
+		result.HasMore = true
+	}
+	return result, nil
+}
+
+// completionToolValues invokes the completion tool and returns the values of
+// its result.
+func completionToolValues(ctx context.Context, toolset tools.Toolset, resourceMgr *resources.ResourceManager, toolName string, argument string, contextArgs map[string]string) ([]string, error) {
+	tool, ok := resourceMgr.GetTool(toolName)
+	if !ok {
+		return nil, fmt.Errorf("completion tool %q does not exist", toolName)
+	}
+	// completion requests do not carry credentials
+	clientAuth, err := tool.RequiresClientAuthorization(resourceMgr)
+	if err != nil {
+		return nil, err
+	}
+	if clientAuth || !tool.Authorized(nil) {
+		return nil, fmt.Errorf("completion tool %q requires authorization", toolName)
+	}
+
+	// only the arguments accepted by the tool are passed to it
+	data := make(map[string]any)
+	for _, p := range tool.GetParameters() {
+		s, ok := contextArgs[p.GetName()]
+		if !ok {
+			continue
+		}
+		var v any = s
+		if p.GetType() != parameters.TypeString {
+			// context arguments are strings, decode values of other types
+			if err := util.DecodeJSON(bytes.NewBufferString(s), &v); err != nil {
+				v = s
+			}
+		}
+		data[p.GetName()] = v
+	}
+	params, err := parameters.ParseParams(tool.GetParameters(), data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameters for completion tool %q: %w", toolName, err)
+	}
+	results, toolErr := InvokeTool(ctx, resourceMgr, toolset, toolName, tool, params, "")
+	if toolErr != nil {
+		return nil, fmt.Errorf("error invoking completion tool %q: %w", toolName, toolErr)
+	}
+	return resultValues(results, argument)
+}
+
+// resultValues returns the values in the result of a tool. For rows, the value
+// is taken from the column named after the argument, or from the first column
+// if there is no such column.
+func resultValues(results any, column string) ([]string, error) {
+	rows, ok := results.([]any)
+	if !ok {
+		// normalize other results, such as slices of maps
+		b, err := json.Marshal(results)
+		if err != nil {
+			return nil, fmt.Errorf("unable to marshal completion tool result: %w", err)
+		}
+		if err := util.DecodeJSON(bytes.NewBuffer(b), &rows); err != nil {
+			rows = []any{results}
+		}
+	}
+
+	values := make([]string, 0, len(rows))
+	for _, row := range rows {
+		var value any
+		switch r := row.(type) {
+		case orderedmap.Row:
+			if len(r.Columns) > 0 {
+				value = r.Columns[0].Value
+			}
+			for _, col := range r.Columns {
+				if col.Name == column {
+					value = col.Value
+					break
+				}
+			}
+		case map[string]any:
+			if v, ok := r[column]; ok {
+				value = v
+			} else if len(r) == 1 {
+				for _, v := range r {
+					value = v
+				}
+			}
+		default:
+			value = r
+		}
+		if value != nil {
+			values = append(values, fmt.Sprint(value))
+		}
+	}
+	return values, nil
+}
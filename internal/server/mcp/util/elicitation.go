@@ -0,0 +1,113 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"github.com/googleapis/genai-toolbox/internal/util/parameters"
+)
+
+const (
+	// method that requests additional information from the user
+	ELICITATION_CREATE = "elicitation/create"
+
+	// actions the user can take in response to an elicitation
+	ELICIT_ACCEPT  = "accept"
+	ELICIT_DECLINE = "decline"
+	ELICIT_CANCEL  = "cancel"
+)
+
+/* Elicitation */
+
+// ElicitRequestParams is a request from the server to elicit additional
+// information from the user via the client.
+type ElicitRequestParams struct {
+	// The message to present to the user.
+	Message string `json:"message"`
+	// A restricted subset of JSON Schema. Only top-level properties are
+	// allowed, without nesting.
+	RequestedSchema parameters.McpToolsSchema `json:"requestedSchema"`
+}
+
+// ElicitResult is the client's response to an elicitation request.
+type ElicitResult struct {
+	// The user action in response to the elicitation: ELICIT_ACCEPT,
+	// ELICIT_DECLINE or ELICIT_CANCEL.
+	Action string `json:"action"`
+	// The submitted form data, only present when action is ELICIT_ACCEPT.
+	Content map[string]any `json:"content,omitempty"`
+}
+
+// ConfirmInvocation asks the user to confirm the invocation of a tool that
+// requires confirmation, and returns an error unless the user explicitly
+// accepts. Invocations fail if the client does not support elicitation.
+func ConfirmInvocation(ctx context.Context, toolName string, tool tools.Tool, params parameters.ParamValues) util.ToolboxError {
+	if !tools.RequiresConfirmation(tool) {
+		return nil
+	}
+	if ClientCapabilitiesFromContext(ctx).Elicitation == nil {
+		return util.NewAgentError(fmt.Sprintf("tool %q requires confirmation, but the client does not support elicitation", toolName), nil)
+	}
+	request, err := util.ClientRequesterFromContext(ctx)
+	if err != nil {
+		return util.NewAgentError(fmt.Sprintf("tool %q requires confirmation, but the client cannot be reached", toolName), err)
+	}
+
+	raw, err := request(ctx, ELICITATION_CREATE, ElicitRequestParams{
+		Message: confirmationMessage(toolName, params),
+		RequestedSchema: parameters.McpToolsSchema{
+			Type:       "object",
+			Properties: map[string]parameters.ParameterMcpManifest{},
+			Required:   []string{},
+		},
+	})
+	if err != nil {
+		return util.NewAgentError(fmt.Sprintf("unable to confirm the invocation of tool %q", toolName), err)
+	}
+	var result ElicitResult
+	if err := util.DecodeJSON(bytes.NewBuffer(raw), &result); err != nil {
+		return util.NewAgentError(fmt.Sprintf("unable to confirm the invocation of tool %q", toolName), err)
+	}
+	if result.Action != ELICIT_ACCEPT {
+		return util.NewAgentError(fmt.Sprintf("the user did not confirm the invocation of tool %q (action: %s)", toolName, result.Action), nil)
+	}
+	return nil
+}
+
+// confirmationMessage summarizes the invocation of a tool for the user.
+func confirmationMessage(toolName string, params parameters.ParamValues) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Allow the tool %q to run", toolName)
+	if len(params) == 0 {
+		sb.WriteString("?")
+		return sb.String()
+	}
+	sb.WriteString(" with the following parameters?")
+	for _, p := range params {
+		value, err := json.Marshal(p.Value)
+		if err != nil {
+			value = []byte(fmt.Sprint(p.Value))
+		}
+		fmt.Fprintf(&sb, "\n- %s: %s", p.Name, value)
+	}
+	return sb.String()
+}
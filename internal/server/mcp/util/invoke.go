@@ -0,0 +1,89 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/googleapis/genai-toolbox/internal/embeddingmodels"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"github.com/googleapis/genai-toolbox/internal/util/parameters"
+)
+
+// ToolResources provides the resources needed to invoke a tool.
+type ToolResources interface {
+	tools.SourceProvider
+	GetEmbeddingModelMap() map[string]embeddingmodels.EmbeddingModel
+}
+
+type toolsetKey struct{}
+
+// WithToolset adds the toolset the current tool is invoked through to the
+// context.
+func WithToolset(ctx context.Context, toolset tools.Toolset) context.Context {
+	return context.WithValue(ctx, toolsetKey{}, toolset)
+}
+
+// ToolsetFromContext retrieves the toolset the current tool is invoked
+// through, which tools that invoke other tools invoke them through too.
+func ToolsetFromContext(ctx context.Context) (tools.Toolset, bool) {
+	toolset, ok := ctx.Value(toolsetKey{}).(tools.Toolset)
+	return toolset, ok
+}
+
+// PrepareInvocation checks the invocation of a tool through a toolset once
+// its parameters are parsed, and must be used by every path that invokes a
+// tool. The authorization policies of the toolset and the tool must allow the
+// caller, whose claims are taken from the context, the invocation policies
+// must not deny it, and the user must confirm it if the tool requires
+// confirmation. The parameters are then embedded, after the confirmation so
+// that the user confirms them as entered.
+//
+// It returns the context to invoke the tool with, which carries the toolset,
+// and the embedded parameters. A caller that is not allowed gets a
+// *util.ClientServerError with status 403, and parameters that cannot be
+// embedded one with status 400; other denials are agent errors.
+func PrepareInvocation(ctx context.Context, toolset tools.Toolset, toolName string, tool tools.Tool, params parameters.ParamValues, embeddingModels map[string]embeddingmodels.EmbeddingModel) (context.Context, parameters.ParamValues, util.ToolboxError) {
+	claimsFromAuth := util.AuthClaimsFromContext(ctx)
+	if !toolset.Allows(toolName, tool, claimsFromAuth) {
+		return ctx, nil, util.NewClientServerError("unauthorized Tool call: the authorization policy does not allow the caller to call this tool", http.StatusForbidden, nil)
+	}
+	// policies may deny the invocation based on its parameters
+	if err := toolset.EvaluatePolicies(toolName, tool, claimsFromAuth, params); err != nil {
+		return ctx, nil, err
+	}
+	// tools that require confirmation only run once the user accepts
+	if err := ConfirmInvocation(ctx, toolName, tool, params); err != nil {
+		return ctx, nil, err
+	}
+	params, err := tool.EmbedParams(ctx, params, embeddingModels)
+	if err != nil {
+		return ctx, nil, util.NewClientServerError("error embedding parameters", http.StatusBadRequest, err)
+	}
+	return WithToolset(ctx, toolset), params, nil
+}
+
+// InvokeTool invokes a tool from another tool or a request that is not a
+// tool call, such as a completion, through the toolset of the current
+// request. The parameters must be parsed.
+func InvokeTool(ctx context.Context, resourceMgr ToolResources, toolset tools.Toolset, toolName string, tool tools.Tool, params parameters.ParamValues, accessToken tools.AccessToken) (any, util.ToolboxError) {
+	ctx, params, err := PrepareInvocation(ctx, toolset, toolName, tool, params, resourceMgr.GetEmbeddingModelMap())
+	if err != nil {
+		return nil, err
+	}
+	return tool.Invoke(ctx, resourceMgr, params, accessToken)
+}
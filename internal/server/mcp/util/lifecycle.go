@@ -15,7 +15,10 @@
 package util
 
 import (
+	"context"
+
 	"github.com/googleapis/genai-toolbox/internal/server/mcp/jsonrpc"
+	"github.com/googleapis/genai-toolbox/internal/tools"
 )
 
 const (
@@ -81,15 +84,40 @@ type ClientCapabilities struct {
 	// Present if the client supports listing roots.
 	Roots *ListChanged `json:"roots,omitempty"`
 	// Present if the client supports sampling from an LLM.
-	Sampling struct{} `json:"sampling,omitempty"`
+	Sampling *struct{} `json:"sampling,omitempty"`
+	// Present if the client supports elicitation from the server. Only
+	// supported for v2025-06-18+.
+	Elicitation *struct{} `json:"elicitation,omitempty"`
+}
+
+type clientCapabilitiesKey struct{}
+
+// WithClientCapabilities adds the capabilities announced by the client of the
+// current session to the context.
+func WithClientCapabilities(ctx context.Context, capabilities ClientCapabilities) context.Context {
+	return context.WithValue(ctx, clientCapabilitiesKey{}, capabilities)
+}
+
+// ClientCapabilitiesFromContext retrieves the capabilities of the client. It
+// returns the zero value if the client did not announce any capabilities.
+func ClientCapabilitiesFromContext(ctx context.Context) ClientCapabilities {
+	capabilities, _ := ctx.Value(clientCapabilitiesKey{}).(ClientCapabilities)
+	return capabilities
 }
 
 // ServerCapabilities represents capabilities that a server may support. Known
 // capabilities are defined here, in this schema, but this is not a closed set: any
 // server can define its own, additional capabilities.
 type ServerCapabilities struct {
-	Tools   *ListChanged `json:"tools,omitempty"`
-	Prompts *ListChanged `json:"prompts,omitempty"`
+	Tools     *ListChanged `json:"tools,omitempty"`
+	Prompts   *ListChanged `json:"prompts,omitempty"`
+	Resources *ListChanged `json:"resources,omitempty"`
+	// Present if the server supports sending log messages to the client.
+	Logging *struct{} `json:"logging,omitempty"`
+	// Present if the server supports argument autocompletion suggestions.
+	Completions *struct{} `json:"completions,omitempty"`
+	// Present if the server supports task-augmented requests.
+	Tasks *TaskCapabilities `json:"tasks,omitempty"`
 }
 
 // Base interface for metadata with name (identifier) and title (display name) properties.
@@ -110,4 +138,6 @@ type BaseMetadata struct {
 type Implementation struct {
 	BaseMetadata
 	Version string `json:"version"`
+	// Icons of the implementation. Only supported for v2025-11-25+.
+	Icons []tools.Icon `json:"icons,omitempty"`
 }
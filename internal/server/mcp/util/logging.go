@@ -0,0 +1,147 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/server/mcp/jsonrpc"
+)
+
+const (
+	// method that sets the level of the log messages sent to the client
+	LOGGING_SET_LEVEL = "logging/setLevel"
+	// notification that sends a log message to the client
+	NOTIFICATIONS_MESSAGE = "notifications/message"
+
+	// LOGGER_NAME is the name of the logger reported in log messages.
+	LOGGER_NAME = "toolbox"
+)
+
+// LoggingLevel is the severity of a log message. These map to syslog message
+// severities, as specified in RFC-5424.
+type LoggingLevel string
+
+const (
+	LOGGING_DEBUG     LoggingLevel = "debug"
+	LOGGING_INFO      LoggingLevel = "info"
+	LOGGING_NOTICE    LoggingLevel = "notice"
+	LOGGING_WARNING   LoggingLevel = "warning"
+	LOGGING_ERROR     LoggingLevel = "error"
+	LOGGING_CRITICAL  LoggingLevel = "critical"
+	LOGGING_ALERT     LoggingLevel = "alert"
+	LOGGING_EMERGENCY LoggingLevel = "emergency"
+)
+
+// loggingLevels maps the MCP logging levels to slog levels. Levels above
+// error have no slog counterpart and are placed above slog.LevelError.
+var loggingLevels = map[LoggingLevel]slog.Level{
+	LOGGING_DEBUG:     slog.LevelDebug,
+	LOGGING_INFO:      slog.LevelInfo,
+	LOGGING_NOTICE:    slog.LevelInfo + 2,
+	LOGGING_WARNING:   slog.LevelWarn,
+	LOGGING_ERROR:     slog.LevelError,
+	LOGGING_CRITICAL:  slog.LevelError + 4,
+	LOGGING_ALERT:     slog.LevelError + 8,
+	LOGGING_EMERGENCY: slog.LevelError + 12,
+}
+
+// SlogLevel returns the slog level of an MCP logging level.
+func SlogLevel(level LoggingLevel) (slog.Level, error) {
+	l, ok := loggingLevels[level]
+	if !ok {
+		return 0, fmt.Errorf("invalid logging level %q", level)
+	}
+	return l, nil
+}
+
+// LoggingLevelOf returns the MCP logging level of a slog level.
+func LoggingLevelOf(level slog.Level) LoggingLevel {
+	switch {
+	case level < slog.LevelInfo:
+		return LOGGING_DEBUG
+	case level < slog.LevelWarn:
+		return LOGGING_INFO
+	case level < slog.LevelError:
+		return LOGGING_WARNING
+	default:
+		return LOGGING_ERROR
+	}
+}
+
+/* Logging */
+
+// SetLevelRequest is a request from the client to the server, to enable or
+// adjust logging.
+type SetLevelRequest struct {
+	jsonrpc.Request
+	Params struct {
+		// The level of logging that the client wants to receive from the
+		// server. The server should send all logs at this level and higher
+		// (i.e., more severe) to the client as notifications/message.
+		Level LoggingLevel `json:"level"`
+	} `json:"params"`
+}
+
+// LoggingMessageNotificationParams is the log message sent to the client.
+type LoggingMessageNotificationParams struct {
+	// The severity of this log message.
+	Level LoggingLevel `json:"level"`
+	// An optional name of the logger issuing this message.
+	Logger string `json:"logger,omitempty"`
+	// The data to be logged, such as a string message or an object.
+	Data any `json:"data"`
+}
+
+// LoggingMessageNotification is a notification of a log message passed from
+// server to client.
+type LoggingMessageNotification struct {
+	Jsonrpc string                           `json:"jsonrpc"`
+	Method  string                           `json:"method"`
+	Params  LoggingMessageNotificationParams `json:"params"`
+}
+
+// NewLoggingMessageNotification creates a log message notification. The
+// message is sent as a string if there are no attributes, otherwise as an
+// object with the message and the attributes.
+func NewLoggingMessageNotification(level slog.Level, msg string, keysAndValues ...any) LoggingMessageNotification {
+	var data any = msg
+	if len(keysAndValues) > 0 {
+		attrs := map[string]any{"message": msg}
+		r := slog.NewRecord(time.Time{}, level, msg, 0)
+		r.Add(keysAndValues...)
+		r.Attrs(func(a slog.Attr) bool {
+			v := a.Value.Resolve().Any()
+			// errors do not marshal to JSON
+			if err, ok := v.(error); ok {
+				v = err.Error()
+			}
+			attrs[a.Key] = v
+			return true
+		})
+		data = attrs
+	}
+	return LoggingMessageNotification{
+		Jsonrpc: jsonrpc.JSONRPC_VERSION,
+		Method:  NOTIFICATIONS_MESSAGE,
+		Params: LoggingMessageNotificationParams{
+			Level:  LoggingLevelOf(level),
+			Logger: LOGGER_NAME,
+			Data:   data,
+		},
+	}
+}
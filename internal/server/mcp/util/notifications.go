@@ -0,0 +1,98 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"github.com/googleapis/genai-toolbox/internal/server/mcp/jsonrpc"
+)
+
+const (
+	// notifications that are supported
+	NOTIFICATIONS_CANCELLED            = "notifications/cancelled"
+	NOTIFICATIONS_PROGRESS             = "notifications/progress"
+	NOTIFICATIONS_TOOLS_LIST_CHANGED   = "notifications/tools/list_changed"
+	NOTIFICATIONS_PROMPTS_LIST_CHANGED = "notifications/prompts/list_changed"
+)
+
+/* Cancellation */
+
+// CancelledNotificationParams identifies the request that is cancelled.
+type CancelledNotificationParams struct {
+	// The ID of the request to cancel.
+	RequestId jsonrpc.RequestId `json:"requestId"`
+	// An optional string describing the reason for the cancellation.
+	Reason string `json:"reason,omitempty"`
+}
+
+// CancelledNotification can be sent by either side to indicate that it is
+// cancelling a previously-issued request.
+type CancelledNotification struct {
+	Jsonrpc string                      `json:"jsonrpc"`
+	Method  string                      `json:"method"`
+	Params  CancelledNotificationParams `json:"params"`
+}
+
+/* Progress */
+
+// ProgressNotificationParams describes the progress of a long-running request.
+type ProgressNotificationParams struct {
+	// The progress token which was given in the initial request.
+	ProgressToken jsonrpc.ProgressToken `json:"progressToken"`
+	// The progress thus far. This should increase every time progress is made,
+	// even if the total is unknown.
+	Progress float64 `json:"progress"`
+	// Total number of items to process (or total progress required), if known.
+	Total float64 `json:"total,omitempty"`
+	// An optional message describing the current progress. Only supported for
+	// v2025-03-26+.
+	Message string `json:"message,omitempty"`
+}
+
+// ProgressNotification is sent from the server to the client to inform it of
+// the progress of a long-running request.
+type ProgressNotification struct {
+	Jsonrpc string                     `json:"jsonrpc"`
+	Method  string                     `json:"method"`
+	Params  ProgressNotificationParams `json:"params"`
+}
+
+// NewProgressNotification creates a progress notification for the request
+// identified by progressToken.
+func NewProgressNotification(progressToken jsonrpc.ProgressToken, progress float64, total float64, message string) ProgressNotification {
+	return ProgressNotification{
+		Jsonrpc: jsonrpc.JSONRPC_VERSION,
+		Method:  NOTIFICATIONS_PROGRESS,
+		Params: ProgressNotificationParams{
+			ProgressToken: progressToken,
+			Progress:      progress,
+			Total:         total,
+			Message:       message,
+		},
+	}
+}
+
+/* List changed */
+
+// NewListChangedNotification creates a notification that informs the client
+// that the list of tools or prompts it can use has changed. method is either
+// NOTIFICATIONS_TOOLS_LIST_CHANGED or NOTIFICATIONS_PROMPTS_LIST_CHANGED.
+func NewListChangedNotification(method string) jsonrpc.JSONRPCNotification {
+	return jsonrpc.JSONRPCNotification{
+		Jsonrpc: jsonrpc.JSONRPC_VERSION,
+		Notification: jsonrpc.Notification{
+			Method: method,
+		},
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"github.com/googleapis/genai-toolbox/internal/mcpresources"
+	"github.com/googleapis/genai-toolbox/internal/server/resources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// ToolsetResources returns the resources of the toolset, by name. Sessions of
+// a toolset can only list, read and complete these resources.
+func ToolsetResources(toolset tools.Toolset, resourceMgr *resources.ResourceManager) map[string]mcpresources.Resource {
+	resourcesMap := make(map[string]mcpresources.Resource)
+	for name, r := range resourceMgr.GetMcpResourcesMap() {
+		if toolset.HasResource(name) {
+			resourcesMap[name] = r
+		}
+	}
+	return resourcesMap
+}
@@ -0,0 +1,146 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+const (
+	// method that requests the client to sample an LLM
+	SAMPLING_CREATE_MESSAGE = "sampling/createMessage"
+
+	// roles of the messages exchanged with the LLM
+	ROLE_USER      = "user"
+	ROLE_ASSISTANT = "assistant"
+)
+
+/* Sampling */
+
+// SamplingContent is the content of a message sent to or received from the
+// LLM.
+type SamplingContent struct {
+	// The type of the content: "text", "image" or "audio".
+	Type string `json:"type"`
+	// The text of the message, for text content.
+	Text string `json:"text,omitempty"`
+	// The base64-encoded data, for image and audio content.
+	Data string `json:"data,omitempty"`
+	// The MIME type of the data, for image and audio content.
+	MimeType string `json:"mimeType,omitempty"`
+}
+
+// SamplingMessage describes a message issued to or received from the LLM.
+type SamplingMessage struct {
+	Role    string          `json:"role"`
+	Content SamplingContent `json:"content"`
+}
+
+// ModelHint is a hint to use for model selection. The client may ignore it.
+type ModelHint struct {
+	// A substring of a model name, for example "sonnet".
+	Name string `json:"name,omitempty"`
+}
+
+// ModelPreferences are the server's preferences for model selection,
+// requested of the client during sampling.
+type ModelPreferences struct {
+	// Hints to use for model selection, evaluated in order.
+	Hints []ModelHint `json:"hints,omitempty"`
+}
+
+// CreateMessageRequestParams is a request from the server to sample an LLM
+// via the client.
+type CreateMessageRequestParams struct {
+	Messages         []SamplingMessage `json:"messages"`
+	ModelPreferences *ModelPreferences `json:"modelPreferences,omitempty"`
+	// An optional system prompt the server wants to use for sampling.
+	SystemPrompt string `json:"systemPrompt,omitempty"`
+	// The maximum number of tokens to sample.
+	MaxTokens   int      `json:"maxTokens"`
+	Temperature *float64 `json:"temperature,omitempty"`
+}
+
+// CreateMessageResult is the client's response to a sampling request.
+type CreateMessageResult struct {
+	Role string `json:"role"`
+	// The content of the message: a single content item, or a list of
+	// content items since v2025-11-25.
+	Content json.RawMessage `json:"content"`
+	// The name of the model that generated the message.
+	Model string `json:"model"`
+	// The reason why sampling stopped, if known.
+	StopReason string `json:"stopReason,omitempty"`
+}
+
+// ContentItems returns the content items of the message.
+func (r CreateMessageResult) ContentItems() ([]SamplingContent, error) {
+	trimmed := bytes.TrimSpace(r.Content)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var items []SamplingContent
+		if err := json.Unmarshal(trimmed, &items); err != nil {
+			return nil, err
+		}
+		return items, nil
+	}
+	var item SamplingContent
+	if err := json.Unmarshal(trimmed, &item); err != nil {
+		return nil, err
+	}
+	return []SamplingContent{item}, nil
+}
+
+// Text joins the text content items of the message. It returns false if the
+// message has no text content.
+func (r CreateMessageResult) Text() (string, bool, error) {
+	items, err := r.ContentItems()
+	if err != nil {
+		return "", false, err
+	}
+	var texts []string
+	for _, item := range items {
+		if item.Type == "text" {
+			texts = append(texts, item.Text)
+		}
+	}
+	return strings.Join(texts, "\n"), len(texts) > 0, nil
+}
+
+// CreateMessage asks the client to sample its LLM. It fails if the client
+// does not support sampling.
+func CreateMessage(ctx context.Context, params CreateMessageRequestParams) (CreateMessageResult, error) {
+	if ClientCapabilitiesFromContext(ctx).Sampling == nil {
+		return CreateMessageResult{}, fmt.Errorf("the client does not support sampling")
+	}
+	request, err := util.ClientRequesterFromContext(ctx)
+	if err != nil {
+		return CreateMessageResult{}, fmt.Errorf("the client cannot be reached: %w", err)
+	}
+	raw, err := request(ctx, SAMPLING_CREATE_MESSAGE, params)
+	if err != nil {
+		return CreateMessageResult{}, err
+	}
+	var result CreateMessageResult
+	if err := util.DecodeJSON(bytes.NewBuffer(raw), &result); err != nil {
+		return CreateMessageResult{}, fmt.Errorf("invalid sampling result: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,465 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/googleapis/genai-toolbox/internal/server/mcp/jsonrpc"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+const (
+	// methods that manage the tasks of a session
+	TASKS_GET    = "tasks/get"
+	TASKS_RESULT = "tasks/result"
+	TASKS_LIST   = "tasks/list"
+	TASKS_CANCEL = "tasks/cancel"
+
+	// notification sent when the status of a task changes
+	NOTIFICATIONS_TASKS_STATUS = "notifications/tasks/status"
+
+	// key of the `_meta` entry that relates a message to a task
+	RELATED_TASK_META_KEY = "io.modelcontextprotocol/related-task"
+)
+
+// TaskStatus is the status of a task.
+type TaskStatus string
+
+const (
+	TaskWorking       TaskStatus = "working"
+	TaskInputRequired TaskStatus = "input_required"
+	TaskCompleted     TaskStatus = "completed"
+	TaskFailed        TaskStatus = "failed"
+	TaskCancelled     TaskStatus = "cancelled"
+)
+
+// isTerminal returns true if the task cannot change status anymore.
+func (s TaskStatus) isTerminal() bool {
+	return s == TaskCompleted || s == TaskFailed || s == TaskCancelled
+}
+
+const (
+	// DefaultTaskTTL is the time a task is kept when the request does not
+	// specify it.
+	DefaultTaskTTL = time.Hour
+	// MaxTaskTTL is the longest time a task is kept, regardless of the
+	// request.
+	MaxTaskTTL = 24 * time.Hour
+	// TaskPollInterval is the interval at which clients are advised to poll
+	// the status of a task.
+	TaskPollInterval = time.Second
+)
+
+/* Tasks */
+
+// Task is the state of a task.
+type Task struct {
+	// The identifier of the task, generated by the server.
+	TaskId string     `json:"taskId"`
+	Status TaskStatus `json:"status"`
+	// A human-readable description of the status.
+	StatusMessage string `json:"statusMessage,omitempty"`
+	// ISO 8601 timestamps of the creation and last update of the task.
+	CreatedAt     string `json:"createdAt"`
+	LastUpdatedAt string `json:"lastUpdatedAt"`
+	// The number of milliseconds the task is kept after its creation.
+	Ttl int64 `json:"ttl"`
+	// The suggested number of milliseconds between status polls.
+	PollInterval int64 `json:"pollInterval,omitempty"`
+}
+
+// TaskMetadata augments a request with a task.
+type TaskMetadata struct {
+	// The requested number of milliseconds the task is kept.
+	Ttl *int64 `json:"ttl,omitempty"`
+}
+
+// RelatedTaskMetadata relates a message to a task in its `_meta`.
+type RelatedTaskMetadata struct {
+	TaskId string `json:"taskId"`
+}
+
+// TaskCapabilities are the capabilities of a server that supports tasks.
+type TaskCapabilities struct {
+	List     *struct{} `json:"list,omitempty"`
+	Cancel   *struct{} `json:"cancel,omitempty"`
+	Requests struct {
+		Tools struct {
+			Call *struct{} `json:"call,omitempty"`
+		} `json:"tools"`
+	} `json:"requests"`
+}
+
+// NewTaskCapabilities returns the task capabilities of Toolbox: tool calls can
+// be augmented with tasks, which can be listed and cancelled.
+func NewTaskCapabilities() *TaskCapabilities {
+	capabilities := &TaskCapabilities{List: &struct{}{}, Cancel: &struct{}{}}
+	capabilities.Requests.Tools.Call = &struct{}{}
+	return capabilities
+}
+
+// CreateTaskResult is the response to a request augmented with a task.
+type CreateTaskResult struct {
+	jsonrpc.Result
+	Task Task `json:"task"`
+}
+
+// TaskRequest is a request about a single task: tasks/get, tasks/result or
+// tasks/cancel.
+type TaskRequest struct {
+	jsonrpc.Request
+	Params struct {
+		TaskId string `json:"taskId"`
+	} `json:"params"`
+}
+
+// GetTaskResult is the response to tasks/get and tasks/cancel.
+type GetTaskResult struct {
+	jsonrpc.Result
+	Task
+}
+
+// ListTasksRequest is sent from the client to list the tasks of the session.
+type ListTasksRequest struct {
+	jsonrpc.Request
+	Params struct {
+		Cursor string `json:"cursor,omitempty"`
+	} `json:"params,omitempty"`
+}
+
+// ListTasksResult is the response to tasks/list.
+type ListTasksResult struct {
+	jsonrpc.Result
+	Tasks      []Task `json:"tasks"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// TaskStatusNotification is sent to the client when a task completes.
+type TaskStatusNotification struct {
+	Jsonrpc string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  Task   `json:"params"`
+}
+
+// TaskOutcome is the outcome of the request augmented with a task.
+type TaskOutcome struct {
+	// Result is the result of the request, when it succeeded.
+	Result any
+	// Error is the error of the request, when it failed with a protocol
+	// error.
+	Error *jsonrpc.Error
+	// Failed is true if the result reports a failure, such as a tool result
+	// with `isError`.
+	Failed bool
+}
+
+// TaskPool runs the tasks of every session with a bounded number of workers.
+// Tasks wait in a bounded queue until a worker is available.
+type TaskPool struct {
+	queue chan func()
+}
+
+// NewTaskPool starts a pool of workers. At most queueSize tasks wait for a
+// worker.
+func NewTaskPool(workers, queueSize int) *TaskPool {
+	p := &TaskPool{queue: make(chan func(), queueSize)}
+	for range workers {
+		go func() {
+			for run := range p.queue {
+				run()
+			}
+		}()
+	}
+	return p
+}
+
+// submit queues run, or returns false if the queue is full. A nil *TaskPool
+// has no room for tasks.
+func (p *TaskPool) submit(run func()) bool {
+	if p == nil {
+		return false
+	}
+	select {
+	case p.queue <- run:
+		return true
+	default:
+		return false
+	}
+}
+
+// ErrTaskNotFound is returned for a task that does not exist or expired.
+var ErrTaskNotFound = errors.New("task not found")
+
+// ErrTaskQueueFull is returned when a task cannot be queued.
+var ErrTaskQueueFull = errors.New("too many tasks are waiting to run, retry later")
+
+// taskEntry is a task of a session and its outcome.
+type taskEntry struct {
+	task      Task
+	createdAt time.Time
+	ttl       time.Duration
+	cancel    context.CancelFunc
+	// done is closed once the task reaches a terminal status
+	done    chan struct{}
+	outcome TaskOutcome
+}
+
+// TaskStore holds the tasks of a session, which are run by a TaskPool.
+type TaskStore struct {
+	pool *TaskPool
+
+	mu    sync.Mutex
+	tasks map[string]*taskEntry
+	// order lists the ids of the tasks by creation
+	order []string
+}
+
+// NewTaskStore creates the task store of a session.
+func NewTaskStore(pool *TaskPool) *TaskStore {
+	return &TaskStore{pool: pool, tasks: make(map[string]*taskEntry)}
+}
+
+type taskStoreKey struct{}
+
+// WithTaskStore adds the task store of the current session to the context.
+func WithTaskStore(ctx context.Context, store *TaskStore) context.Context {
+	return context.WithValue(ctx, taskStoreKey{}, store)
+}
+
+// TaskStoreFromContext retrieves the task store of the session. It returns
+// nil if the request does not belong to a session that supports tasks.
+func TaskStoreFromContext(ctx context.Context) *TaskStore {
+	store, _ := ctx.Value(taskStoreKey{}).(*TaskStore)
+	return store
+}
+
+func timestamp(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// Start creates a task that runs the request in the background, and returns
+// immediately. The task outlives the context of the request, but keeps its
+// values. cleanup, if not nil, is called once the task is done with, whether
+// it ran, was cancelled while waiting, or could not be queued.
+func (s *TaskStore) Start(ctx context.Context, metadata TaskMetadata, run func(context.Context) TaskOutcome, cleanup func()) (Task, error) {
+	if cleanup == nil {
+		cleanup = func() {}
+	}
+	ttl := DefaultTaskTTL
+	if metadata.Ttl != nil && *metadata.Ttl > 0 {
+		ttl = min(time.Duration(*metadata.Ttl)*time.Millisecond, MaxTaskTTL)
+	}
+	now := time.Now()
+	entry := &taskEntry{
+		task: Task{
+			TaskId:        uuid.New().String(),
+			Status:        TaskWorking,
+			StatusMessage: "The task is waiting to run.",
+			CreatedAt:     timestamp(now),
+			LastUpdatedAt: timestamp(now),
+			Ttl:           ttl.Milliseconds(),
+			PollInterval:  TaskPollInterval.Milliseconds(),
+		},
+		createdAt: now,
+		ttl:       ttl,
+		done:      make(chan struct{}),
+	}
+	taskCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	entry.cancel = cancel
+
+	s.mu.Lock()
+	s.purge(now)
+	s.tasks[entry.task.TaskId] = entry
+	s.order = append(s.order, entry.task.TaskId)
+	task := entry.task
+	s.mu.Unlock()
+
+	queued := s.pool.submit(func() {
+		defer cleanup()
+		// tasks cancelled while waiting do not run
+		if taskCtx.Err() != nil {
+			return
+		}
+		s.update(entry, TaskWorking, "", nil)
+		outcome := run(taskCtx)
+		status, message := TaskCompleted, ""
+		if outcome.Error != nil {
+			status, message = TaskFailed, outcome.Error.Message
+		} else if outcome.Failed {
+			status = TaskFailed
+		}
+		if s.update(entry, status, message, &outcome) {
+			s.notify(taskCtx, entry)
+		}
+	})
+	if !queued {
+		cleanup()
+		cancel()
+		s.mu.Lock()
+		s.remove(entry.task.TaskId)
+		s.mu.Unlock()
+		return Task{}, ErrTaskQueueFull
+	}
+	return task, nil
+}
+
+// update sets the status of a task that has not reached a terminal status
+// yet, and returns false otherwise.
+func (s *TaskStore) update(entry *taskEntry, status TaskStatus, message string, outcome *TaskOutcome) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if entry.task.Status.isTerminal() {
+		return false
+	}
+	entry.task.Status = status
+	entry.task.StatusMessage = message
+	entry.task.LastUpdatedAt = timestamp(time.Now())
+	if outcome != nil {
+		entry.outcome = *outcome
+	}
+	if status.isTerminal() {
+		entry.cancel()
+		close(entry.done)
+	}
+	return true
+}
+
+// notify sends the status of a task to the client, if it can be reached.
+func (s *TaskStore) notify(ctx context.Context, entry *taskEntry) {
+	send, err := util.MessageSenderFromContext(ctx)
+	if err != nil {
+		return
+	}
+	s.mu.Lock()
+	task := entry.task
+	s.mu.Unlock()
+	// a failure to deliver the notification does not affect the task
+	_ = send(ctx, TaskStatusNotification{
+		Jsonrpc: jsonrpc.JSONRPC_VERSION,
+		Method:  NOTIFICATIONS_TASKS_STATUS,
+		Params:  task,
+	})
+}
+
+// purge removes the tasks that reached a terminal status and outlived their
+// ttl. The caller must hold s.mu.
+func (s *TaskStore) purge(now time.Time) {
+	for _, id := range slices.Clone(s.order) {
+		entry := s.tasks[id]
+		if entry.task.Status.isTerminal() && now.Sub(entry.createdAt) > entry.ttl {
+			s.remove(id)
+		}
+	}
+}
+
+// remove deletes a task. The caller must hold s.mu.
+func (s *TaskStore) remove(id string) {
+	delete(s.tasks, id)
+	s.order = slices.DeleteFunc(s.order, func(v string) bool { return v == id })
+}
+
+func (s *TaskStore) get(id string) (*taskEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purge(time.Now())
+	entry, ok := s.tasks[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrTaskNotFound, id)
+	}
+	return entry, nil
+}
+
+// Get returns the state of a task.
+func (s *TaskStore) Get(id string) (Task, error) {
+	entry, err := s.get(id)
+	if err != nil {
+		return Task{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return entry.task, nil
+}
+
+// List returns the tasks of the session, oldest first.
+func (s *TaskStore) List() []Task {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purge(time.Now())
+	tasks := make([]Task, 0, len(s.order))
+	for _, id := range s.order {
+		tasks = append(tasks, s.tasks[id].task)
+	}
+	return tasks
+}
+
+// Result waits until a task reaches a terminal status and returns its
+// outcome. Cancelled tasks have no outcome.
+func (s *TaskStore) Result(ctx context.Context, id string) (TaskOutcome, error) {
+	entry, err := s.get(id)
+	if err != nil {
+		return TaskOutcome{}, err
+	}
+	select {
+	case <-entry.done:
+	case <-ctx.Done():
+		return TaskOutcome{}, ctx.Err()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if entry.task.Status == TaskCancelled {
+		return TaskOutcome{}, fmt.Errorf("task %q was cancelled", id)
+	}
+	return entry.outcome, nil
+}
+
+// Cancel stops a task that has not reached a terminal status yet.
+func (s *TaskStore) Cancel(id string) (Task, error) {
+	entry, err := s.get(id)
+	if err != nil {
+		return Task{}, err
+	}
+	if !s.update(entry, TaskCancelled, "The task was cancelled by the client.", nil) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return Task{}, fmt.Errorf("task %q cannot be cancelled in status %q", id, entry.task.Status)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return entry.task, nil
+}
+
+// CancelAll stops every task of the session, when the session ends. A nil
+// *TaskStore has no tasks.
+func (s *TaskStore) CancelAll() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	entries := make([]*taskEntry, 0, len(s.tasks))
+	for _, entry := range s.tasks {
+		entries = append(entries, entry)
+	}
+	s.mu.Unlock()
+	for _, entry := range entries {
+		s.update(entry, TaskCancelled, "The session ended.", nil)
+	}
+}
@@ -15,8 +15,10 @@
 package v20241105
 
 import (
+	"github.com/googleapis/genai-toolbox/internal/mcpresources"
 	"github.com/googleapis/genai-toolbox/internal/prompts"
 	"github.com/googleapis/genai-toolbox/internal/server/mcp/jsonrpc"
+	mcputil "github.com/googleapis/genai-toolbox/internal/server/mcp/util"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 )
 
@@ -33,6 +35,12 @@ const (
 	TOOLS_CALL   = "tools/call"
 	PROMPTS_LIST = "prompts/list"
 	PROMPTS_GET  = "prompts/get"
+
+	RESOURCES_LIST           = "resources/list"
+	RESOURCES_READ           = "resources/read"
+	RESOURCES_TEMPLATES_LIST = "resources/templates/list"
+
+	COMPLETION_COMPLETE = "completion/complete"
 )
 
 /* Empty result */
@@ -174,3 +182,61 @@ type PromptMessage struct {
 	Role    string      `json:"role"`
 	Content TextContent `json:"content"`
 }
+
+/* Resources */
+
+// Sent from the client to request a list of resources the server has.
+type ListResourcesRequest struct {
+	PaginatedRequest
+}
+
+// The server's response to a resources/list request from the client.
+type ListResourcesResult struct {
+	PaginatedResult
+	Resources []mcpresources.McpManifest `json:"resources"`
+}
+
+// Sent from the client to request a list of resource templates the server has.
+type ListResourceTemplatesRequest struct {
+	PaginatedRequest
+}
+
+// The server's response to a resources/templates/list request from the client.
+type ListResourceTemplatesResult struct {
+	PaginatedResult
+	ResourceTemplates []mcpresources.McpManifest `json:"resourceTemplates"`
+}
+
+// Sent from the client to the server, to read a specific resource URI.
+type ReadResourceRequest struct {
+	jsonrpc.Request
+	Params struct {
+		// The URI of the resource to read.
+		URI string `json:"uri"`
+	} `json:"params"`
+}
+
+// The server's response to a resources/read request from the client.
+type ReadResourceResult struct {
+	jsonrpc.Result
+	Contents []mcpresources.Contents `json:"contents"`
+}
+
+/* Autocomplete */
+
+// A request from the client to the server, to ask for completion options.
+type CompleteRequest struct {
+	jsonrpc.Request
+	Params struct {
+		// The prompt or resource template to complete an argument of.
+		Ref mcputil.CompletionReference `json:"ref"`
+		// The argument's information.
+		Argument mcputil.CompletionArgument `json:"argument"`
+	} `json:"params"`
+}
+
+// The server's response to a completion/complete request
+type CompleteResult struct {
+	jsonrpc.Result
+	Completion mcputil.Completion `json:"completion"`
+}
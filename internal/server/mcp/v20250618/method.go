@@ -23,8 +23,10 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/googleapis/genai-toolbox/internal/mcpresources"
 	"github.com/googleapis/genai-toolbox/internal/prompts"
 	"github.com/googleapis/genai-toolbox/internal/server/mcp/jsonrpc"
+	mcputil "github.com/googleapis/genai-toolbox/internal/server/mcp/util"
 	"github.com/googleapis/genai-toolbox/internal/server/resources"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/util"
@@ -40,13 +42,21 @@ func ProcessMethod(ctx context.Context, id jsonrpc.RequestId, method string, too
 	case PING:
 		return pingHandler(id)
 	case TOOLS_LIST:
-		return toolsListHandler(id, toolset, body)
+		return toolsListHandler(ctx, id, toolset, resourceMgr, body, header)
 	case TOOLS_CALL:
-		return toolsCallHandler(ctx, id, resourceMgr, body, header)
+		return toolsCallHandler(ctx, id, toolset, resourceMgr, body, header)
 	case PROMPTS_LIST:
 		return promptsListHandler(ctx, id, promptset, body)
 	case PROMPTS_GET:
 		return promptsGetHandler(ctx, id, resourceMgr, body)
+	case RESOURCES_LIST:
+		return resourcesListHandler(ctx, id, toolset, resourceMgr, body)
+	case RESOURCES_TEMPLATES_LIST:
+		return resourceTemplatesListHandler(ctx, id, toolset, resourceMgr, body)
+	case RESOURCES_READ:
+		return resourcesReadHandler(ctx, id, toolset, resourceMgr, body)
+	case COMPLETION_COMPLETE:
+		return completionCompleteHandler(ctx, id, toolset, promptset, resourceMgr, body)
 	default:
 		err := fmt.Errorf("invalid method %s", method)
 		return jsonrpc.NewError(id, jsonrpc.METHOD_NOT_FOUND, err.Error(), nil), err
@@ -62,15 +72,30 @@ func pingHandler(id jsonrpc.RequestId) (any, error) {
 	}, nil
 }
 
-func toolsListHandler(id jsonrpc.RequestId, toolset tools.Toolset, body []byte) (any, error) {
+func toolsListHandler(ctx context.Context, id jsonrpc.RequestId, toolset tools.Toolset, resourceMgr *resources.ResourceManager, body []byte, header http.Header) (any, error) {
 	var req ListToolsRequest
 	if err := json.Unmarshal(body, &req); err != nil {
 		err = fmt.Errorf("invalid mcp tools list request: %w", err)
 		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
 	}
 
+	// only list the tools that the principal may call
+	claimsFromAuth := mcputil.ClaimsFromHeader(ctx, resourceMgr.GetAuthServiceMap(), header)
+	page, nextCursor, err := util.Paginate(toolset.AllowedMcpManifest(claimsFromAuth), string(req.Params.Cursor), util.PageSizeFromContext(ctx))
+	if err != nil {
+		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
+	}
+
+	// exclude icons from this version
+	manifests := make([]tools.McpManifest, len(page))
+	for i, m := range page {
+		m.Icons = nil
+		manifests[i] = m
+	}
+
 	result := ListToolsResult{
-		Tools: toolset.McpManifest,
+		PaginatedResult: PaginatedResult{NextCursor: Cursor(nextCursor)},
+		Tools:           manifests,
 	}
 	return jsonrpc.JSONRPCResponse{
 		Jsonrpc: jsonrpc.JSONRPC_VERSION,
@@ -80,7 +105,7 @@ func toolsListHandler(id jsonrpc.RequestId, toolset tools.Toolset, body []byte)
 }
 
 // toolsCallHandler generate a response for tools call.
-func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, resourceMgr *resources.ResourceManager, body []byte, header http.Header) (any, error) {
+func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, toolset tools.Toolset, resourceMgr *resources.ResourceManager, body []byte, header http.Header) (any, error) {
 	authServices := resourceMgr.GetAuthServiceMap()
 
 	// retrieve logger from context
@@ -107,8 +132,9 @@ func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, resourceMgr *re
 		attribute.String("gen_ai.operation.name", "execute_tool"),
 	)
 
+	// only tools within the session's toolset can be invoked
 	tool, ok := resourceMgr.GetTool(toolName)
-	if !ok {
+	if !ok || !toolset.HasTool(toolName) {
 		err = fmt.Errorf("invalid tool name: tool with name %q does not exist", toolName)
 		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
 	}
@@ -159,23 +185,7 @@ func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, resourceMgr *re
 
 	// Tool authentication
 	// claimsFromAuth maps the name of the authservice to the claims retrieved from it.
-	claimsFromAuth := make(map[string]map[string]any)
-
-	// if using stdio, header will be nil and auth will not be supported
-	if header != nil {
-		for _, aS := range authServices {
-			claims, err := aS.GetClaimsFromHeader(ctx, header)
-			if err != nil {
-				logger.DebugContext(ctx, err.Error())
-				continue
-			}
-			if claims == nil {
-				// authService not present in header
-				continue
-			}
-			claimsFromAuth[aS.GetName()] = claims
-		}
-	}
+	claimsFromAuth := mcputil.ClaimsFromHeader(ctx, authServices, header)
 
 	// Tool authorization check
 	verifiedAuthServices := make([]string, len(claimsFromAuth))
@@ -195,7 +205,10 @@ func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, resourceMgr *re
 		)
 		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
 	}
+
 	logger.DebugContext(ctx, "tool invocation authorized")
+	// sources may run the invocation as the caller
+	ctx = util.WithAuthClaims(ctx, claimsFromAuth)
 
 	params, err := parameters.ParseParams(tool.GetParameters(), data, claimsFromAuth)
 	if err != nil {
@@ -204,11 +217,28 @@ func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, resourceMgr *re
 	}
 	logger.DebugContext(ctx, fmt.Sprintf("invocation params: %s", params))
 
-	embeddingModels := resourceMgr.GetEmbeddingModelMap()
-	params, err = tool.EmbedParams(ctx, params, embeddingModels)
-	if err != nil {
-		err = fmt.Errorf("error embedding parameters: %w", err)
-		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
+	// the policies, and the user if the tool requires confirmation, must
+	// allow the invocation before the parameters are embedded
+	ctx, params, prepareErr := mcputil.PrepareInvocation(ctx, toolset, toolName, tool, params, resourceMgr.GetEmbeddingModelMap())
+	if prepareErr != nil {
+		logger.DebugContext(ctx, fmt.Sprintf("invocation denied: %v", prepareErr))
+		var clientServerErr *util.ClientServerError
+		if errors.As(prepareErr, &clientServerErr) {
+			rpcCode := jsonrpc.INVALID_PARAMS
+			if clientServerErr.Code == http.StatusForbidden {
+				rpcCode = jsonrpc.INVALID_REQUEST
+			}
+			return jsonrpc.NewError(id, rpcCode, prepareErr.Error(), nil), prepareErr
+		}
+		text := TextContent{
+			Type: "text",
+			Text: prepareErr.Error(),
+		}
+		return jsonrpc.JSONRPCResponse{
+			Jsonrpc: jsonrpc.JSONRPC_VERSION,
+			Id:      id,
+			Result:  CallToolResult{Content: []TextContent{text}, IsError: true},
+		}, nil
 	}
 
 	// Get instrumentation for recording tool execution duration
@@ -298,10 +328,15 @@ func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, resourceMgr *re
 		content = append(content, text)
 	}
 
+	result := CallToolResult{Content: content}
+	// tools with an output schema return structured content alongside the
+	// text content
+	result.StructuredContent = tools.StructuredContent(tool, results)
+
 	return jsonrpc.JSONRPCResponse{
 		Jsonrpc: jsonrpc.JSONRPC_VERSION,
 		Id:      id,
-		Result:  CallToolResult{Content: content},
+		Result:  result,
 	}, nil
 }
 
@@ -320,10 +355,23 @@ func promptsListHandler(ctx context.Context, id jsonrpc.RequestId, promptset pro
 		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
 	}
 
+	page, nextCursor, err := util.Paginate(promptset.McpManifest, string(req.Params.Cursor), util.PageSizeFromContext(ctx))
+	if err != nil {
+		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
+	}
+
+	// exclude icons from this version
+	manifests := make([]prompts.McpManifest, len(page))
+	for i, m := range page {
+		m.Icons = nil
+		manifests[i] = m
+	}
+
 	result := ListPromptsResult{
-		Prompts: promptset.McpManifest,
+		PaginatedResult: PaginatedResult{NextCursor: Cursor(nextCursor)},
+		Prompts:         manifests,
 	}
-	logger.DebugContext(ctx, fmt.Sprintf("returning %d prompts", len(promptset.McpManifest)))
+	logger.DebugContext(ctx, fmt.Sprintf("returning %d prompts", len(manifests)))
 	return jsonrpc.JSONRPCResponse{
 		Jsonrpc: jsonrpc.JSONRPC_VERSION,
 		Id:      id,
@@ -412,3 +460,134 @@ func promptsGetHandler(ctx context.Context, id jsonrpc.RequestId, resourceMgr *r
 		Result:  result,
 	}, nil
 }
+
+// resourcesListHandler handles the "resources/list" method.
+func resourcesListHandler(ctx context.Context, id jsonrpc.RequestId, toolset tools.Toolset, resourceMgr *resources.ResourceManager, body []byte) (any, error) {
+	// retrieve logger from context
+	logger, err := util.LoggerFromContext(ctx)
+	if err != nil {
+		return jsonrpc.NewError(id, jsonrpc.INTERNAL_ERROR, err.Error(), nil), err
+	}
+	logger.DebugContext(ctx, "handling resources/list request")
+
+	var req ListResourcesRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		err = fmt.Errorf("invalid mcp resources list request: %w", err)
+		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
+	}
+
+	all, _ := mcpresources.Manifests(mcputil.ToolsetResources(toolset, resourceMgr))
+	manifests, nextCursor, err := util.Paginate(all, string(req.Params.Cursor), util.PageSizeFromContext(ctx))
+	if err != nil {
+		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
+	}
+	logger.DebugContext(ctx, fmt.Sprintf("returning %d resources", len(manifests)))
+	return jsonrpc.JSONRPCResponse{
+		Jsonrpc: jsonrpc.JSONRPC_VERSION,
+		Id:      id,
+		Result: ListResourcesResult{
+			PaginatedResult: PaginatedResult{NextCursor: Cursor(nextCursor)},
+			Resources:       manifests,
+		},
+	}, nil
+}
+
+// resourceTemplatesListHandler handles the "resources/templates/list" method.
+func resourceTemplatesListHandler(ctx context.Context, id jsonrpc.RequestId, toolset tools.Toolset, resourceMgr *resources.ResourceManager, body []byte) (any, error) {
+	// retrieve logger from context
+	logger, err := util.LoggerFromContext(ctx)
+	if err != nil {
+		return jsonrpc.NewError(id, jsonrpc.INTERNAL_ERROR, err.Error(), nil), err
+	}
+	logger.DebugContext(ctx, "handling resources/templates/list request")
+
+	var req ListResourceTemplatesRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		err = fmt.Errorf("invalid mcp resources/templates/list request: %w", err)
+		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
+	}
+
+	_, all := mcpresources.Manifests(mcputil.ToolsetResources(toolset, resourceMgr))
+	templates, nextCursor, err := util.Paginate(all, string(req.Params.Cursor), util.PageSizeFromContext(ctx))
+	if err != nil {
+		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
+	}
+	logger.DebugContext(ctx, fmt.Sprintf("returning %d resource templates", len(templates)))
+	return jsonrpc.JSONRPCResponse{
+		Jsonrpc: jsonrpc.JSONRPC_VERSION,
+		Id:      id,
+		Result: ListResourceTemplatesResult{
+			PaginatedResult:   PaginatedResult{NextCursor: Cursor(nextCursor)},
+			ResourceTemplates: templates,
+		},
+	}, nil
+}
+
+// resourcesReadHandler handles the "resources/read" method.
+func resourcesReadHandler(ctx context.Context, id jsonrpc.RequestId, toolset tools.Toolset, resourceMgr *resources.ResourceManager, body []byte) (any, error) {
+	// retrieve logger from context
+	logger, err := util.LoggerFromContext(ctx)
+	if err != nil {
+		return jsonrpc.NewError(id, jsonrpc.INTERNAL_ERROR, err.Error(), nil), err
+	}
+	logger.DebugContext(ctx, "handling resources/read request")
+
+	var req ReadResourceRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		err = fmt.Errorf("invalid mcp resources/read request: %w", err)
+		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
+	}
+
+	uri := req.Params.URI
+	logger.DebugContext(ctx, fmt.Sprintf("resource uri: %s", uri))
+
+	resource, ok := mcpresources.Find(mcputil.ToolsetResources(toolset, resourceMgr), uri)
+	if !ok {
+		err := fmt.Errorf("resource %q not found", uri)
+		return jsonrpc.NewError(id, jsonrpc.RESOURCE_NOT_FOUND, err.Error(), map[string]string{"uri": uri}), err
+	}
+
+	contents, err := resource.Read(ctx, resourceMgr, uri)
+	if err != nil {
+		err = fmt.Errorf("unable to read resource %q: %w", uri, err)
+		return jsonrpc.NewError(id, jsonrpc.INTERNAL_ERROR, err.Error(), nil), err
+	}
+
+	return jsonrpc.JSONRPCResponse{
+		Jsonrpc: jsonrpc.JSONRPC_VERSION,
+		Id:      id,
+		Result:  ReadResourceResult{Contents: contents},
+	}, nil
+}
+
+// completionCompleteHandler handles the "completion/complete" method.
+func completionCompleteHandler(ctx context.Context, id jsonrpc.RequestId, toolset tools.Toolset, promptset prompts.Promptset, resourceMgr *resources.ResourceManager, body []byte) (any, error) {
+	// retrieve logger from context
+	logger, err := util.LoggerFromContext(ctx)
+	if err != nil {
+		return jsonrpc.NewError(id, jsonrpc.INTERNAL_ERROR, err.Error(), nil), err
+	}
+	logger.DebugContext(ctx, "handling completion/complete request")
+
+	var req CompleteRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		err = fmt.Errorf("invalid mcp completion/complete request: %w", err)
+		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
+	}
+
+	completion, err := mcputil.FindCompletion(toolset, promptset, resourceMgr, req.Params.Ref, req.Params.Argument.Name)
+	if err != nil {
+		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
+	}
+	result, err := mcputil.Complete(ctx, toolset, resourceMgr, completion, req.Params.Argument, req.Params.Context.Arguments)
+	if err != nil {
+		err = fmt.Errorf("unable to complete argument %q: %w", req.Params.Argument.Name, err)
+		return jsonrpc.NewError(id, jsonrpc.INTERNAL_ERROR, err.Error(), nil), err
+	}
+	logger.DebugContext(ctx, fmt.Sprintf("returning %d completion values", len(result.Values)))
+	return jsonrpc.JSONRPCResponse{
+		Jsonrpc: jsonrpc.JSONRPC_VERSION,
+		Id:      id,
+		Result:  CompleteResult{Completion: result},
+	}, nil
+}
@@ -21,10 +21,13 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"time"
 
+	"github.com/googleapis/genai-toolbox/internal/mcpresources"
 	"github.com/googleapis/genai-toolbox/internal/prompts"
 	"github.com/googleapis/genai-toolbox/internal/server/mcp/jsonrpc"
+	mcputil "github.com/googleapis/genai-toolbox/internal/server/mcp/util"
 	"github.com/googleapis/genai-toolbox/internal/server/resources"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/util"
@@ -40,13 +43,29 @@ func ProcessMethod(ctx context.Context, id jsonrpc.RequestId, method string, too
 	case PING:
 		return pingHandler(id)
 	case TOOLS_LIST:
-		return toolsListHandler(id, toolset, body)
+		return toolsListHandler(ctx, id, toolset, resourceMgr, body, header)
 	case TOOLS_CALL:
-		return toolsCallHandler(ctx, id, resourceMgr, body, header)
+		return toolsCallHandler(ctx, id, toolset, resourceMgr, body, header)
 	case PROMPTS_LIST:
 		return promptsListHandler(ctx, id, promptset, body)
 	case PROMPTS_GET:
 		return promptsGetHandler(ctx, id, resourceMgr, body)
+	case RESOURCES_LIST:
+		return resourcesListHandler(ctx, id, toolset, resourceMgr, body)
+	case RESOURCES_TEMPLATES_LIST:
+		return resourceTemplatesListHandler(ctx, id, toolset, resourceMgr, body)
+	case RESOURCES_READ:
+		return resourcesReadHandler(ctx, id, toolset, resourceMgr, body)
+	case COMPLETION_COMPLETE:
+		return completionCompleteHandler(ctx, id, toolset, promptset, resourceMgr, body)
+	case mcputil.TASKS_GET:
+		return tasksGetHandler(ctx, id, body)
+	case mcputil.TASKS_RESULT:
+		return tasksResultHandler(ctx, id, body)
+	case mcputil.TASKS_LIST:
+		return tasksListHandler(ctx, id, body)
+	case mcputil.TASKS_CANCEL:
+		return tasksCancelHandler(ctx, id, body)
 	default:
 		err := fmt.Errorf("invalid method %s", method)
 		return jsonrpc.NewError(id, jsonrpc.METHOD_NOT_FOUND, err.Error(), nil), err
@@ -62,15 +81,31 @@ func pingHandler(id jsonrpc.RequestId) (any, error) {
 	}, nil
 }
 
-func toolsListHandler(id jsonrpc.RequestId, toolset tools.Toolset, body []byte) (any, error) {
+func toolsListHandler(ctx context.Context, id jsonrpc.RequestId, toolset tools.Toolset, resourceMgr *resources.ResourceManager, body []byte, header http.Header) (any, error) {
 	var req ListToolsRequest
 	if err := json.Unmarshal(body, &req); err != nil {
 		err = fmt.Errorf("invalid mcp tools list request: %w", err)
 		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
 	}
 
+	// only list the tools that the principal may call
+	claimsFromAuth := mcputil.ClaimsFromHeader(ctx, resourceMgr.GetAuthServiceMap(), header)
+	manifests, nextCursor, err := util.Paginate(toolset.AllowedMcpManifest(claimsFromAuth), string(req.Params.Cursor), util.PageSizeFromContext(ctx))
+	if err != nil {
+		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
+	}
+
+	// every tool can be called with a task within a session
+	if mcputil.TaskStoreFromContext(ctx) != nil {
+		manifests = slices.Clone(manifests)
+		for i := range manifests {
+			manifests[i].Execution = &tools.ToolExecution{TaskSupport: "optional"}
+		}
+	}
+
 	result := ListToolsResult{
-		Tools: toolset.McpManifest,
+		PaginatedResult: PaginatedResult{NextCursor: Cursor(nextCursor)},
+		Tools:           manifests,
 	}
 	return jsonrpc.JSONRPCResponse{
 		Jsonrpc: jsonrpc.JSONRPC_VERSION,
@@ -80,7 +115,7 @@ func toolsListHandler(id jsonrpc.RequestId, toolset tools.Toolset, body []byte)
 }
 
 // toolsCallHandler generate a response for tools call.
-func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, resourceMgr *resources.ResourceManager, body []byte, header http.Header) (any, error) {
+func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, toolset tools.Toolset, resourceMgr *resources.ResourceManager, body []byte, header http.Header) (any, error) {
 	authServices := resourceMgr.GetAuthServiceMap()
 
 	// retrieve logger from context
@@ -107,8 +142,9 @@ func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, resourceMgr *re
 		attribute.String("gen_ai.operation.name", "execute_tool"),
 	)
 
+	// only tools within the session's toolset can be invoked
 	tool, ok := resourceMgr.GetTool(toolName)
-	if !ok {
+	if !ok || !toolset.HasTool(toolName) {
 		err = fmt.Errorf("invalid tool name: tool with name %q does not exist", toolName)
 		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
 	}
@@ -159,23 +195,7 @@ func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, resourceMgr *re
 
 	// Tool authentication
 	// claimsFromAuth maps the name of the authservice to the claims retrieved from it.
-	claimsFromAuth := make(map[string]map[string]any)
-
-	// if using stdio, header will be nil and auth will not be supported
-	if header != nil {
-		for _, aS := range authServices {
-			claims, err := aS.GetClaimsFromHeader(ctx, header)
-			if err != nil {
-				logger.DebugContext(ctx, err.Error())
-				continue
-			}
-			if claims == nil {
-				// authService not present in header
-				continue
-			}
-			claimsFromAuth[aS.GetName()] = claims
-		}
-	}
+	claimsFromAuth := mcputil.ClaimsFromHeader(ctx, authServices, header)
 
 	// Tool authorization check
 	verifiedAuthServices := make([]string, len(claimsFromAuth))
@@ -195,7 +215,10 @@ func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, resourceMgr *re
 		)
 		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
 	}
+
 	logger.DebugContext(ctx, "tool invocation authorized")
+	// sources may run the invocation as the caller
+	ctx = util.WithAuthClaims(ctx, claimsFromAuth)
 
 	params, err := parameters.ParseParams(tool.GetParameters(), data, claimsFromAuth)
 	if err != nil {
@@ -204,13 +227,55 @@ func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, resourceMgr *re
 	}
 	logger.DebugContext(ctx, fmt.Sprintf("invocation params: %s", params))
 
-	embeddingModels := resourceMgr.GetEmbeddingModelMap()
-	params, err = tool.EmbedParams(ctx, params, embeddingModels)
-	if err != nil {
-		err = fmt.Errorf("error embedding parameters: %w", err)
-		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
+	// the policies, and the user if the tool requires confirmation, must
+	// allow the invocation before the parameters are embedded
+	ctx, params, prepareErr := mcputil.PrepareInvocation(ctx, toolset, toolName, tool, params, resourceMgr.GetEmbeddingModelMap())
+	if prepareErr != nil {
+		logger.DebugContext(ctx, fmt.Sprintf("invocation denied: %v", prepareErr))
+		var clientServerErr *util.ClientServerError
+		if errors.As(prepareErr, &clientServerErr) {
+			rpcCode := jsonrpc.INVALID_PARAMS
+			if clientServerErr.Code == http.StatusForbidden {
+				rpcCode = jsonrpc.INVALID_REQUEST
+			}
+			return jsonrpc.NewError(id, rpcCode, prepareErr.Error(), nil), prepareErr
+		}
+		text := TextContent{
+			Type: "text",
+			Text: prepareErr.Error(),
+		}
+		return jsonrpc.JSONRPCResponse{
+			Jsonrpc: jsonrpc.JSONRPC_VERSION,
+			Id:      id,
+			Result:  CallToolResult{Content: []TextContent{text}, IsError: true},
+		}, nil
+	}
+
+	// tool calls augmented with a task run in the background
+	if store := mcputil.TaskStoreFromContext(ctx); store != nil && req.Params.Task != nil {
+		// the task keeps using the resources of the request once it returns
+		ctx, release := resourceMgr.Acquire(ctx)
+		task, err := store.Start(ctx, *req.Params.Task, func(ctx context.Context) mcputil.TaskOutcome {
+			return taskOutcome(invokeTool(ctx, id, toolName, tool, resourceMgr, params, accessToken, clientAuth))
+		}, release)
+		if err != nil {
+			err = fmt.Errorf("unable to create task: %w", err)
+			return jsonrpc.NewError(id, jsonrpc.INTERNAL_ERROR, err.Error(), nil), err
+		}
+		logger.DebugContext(ctx, fmt.Sprintf("created task %s", task.TaskId))
+		return jsonrpc.JSONRPCResponse{
+			Jsonrpc: jsonrpc.JSONRPC_VERSION,
+			Id:      id,
+			Result:  mcputil.CreateTaskResult{Task: task},
+		}, nil
 	}
 
+	return invokeTool(ctx, id, toolName, tool, resourceMgr, params, accessToken, clientAuth)
+}
+
+// invokeTool runs a tool invocation and generates the response to the tool
+// call.
+func invokeTool(ctx context.Context, id jsonrpc.RequestId, toolName string, tool tools.Tool, resourceMgr *resources.ResourceManager, params parameters.ParamValues, accessToken tools.AccessToken, clientAuth bool) (any, error) {
 	// Get instrumentation for recording tool execution duration
 	instrumentation, instrumentationErr := util.InstrumentationFromContext(ctx)
 
@@ -298,10 +363,15 @@ func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, resourceMgr *re
 		content = append(content, text)
 	}
 
+	result := CallToolResult{Content: content}
+	// tools with an output schema return structured content alongside the
+	// text content
+	result.StructuredContent = tools.StructuredContent(tool, results)
+
 	return jsonrpc.JSONRPCResponse{
 		Jsonrpc: jsonrpc.JSONRPC_VERSION,
 		Id:      id,
-		Result:  CallToolResult{Content: content},
+		Result:  result,
 	}, nil
 }
 
@@ -320,10 +390,16 @@ func promptsListHandler(ctx context.Context, id jsonrpc.RequestId, promptset pro
 		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
 	}
 
+	manifests, nextCursor, err := util.Paginate(promptset.McpManifest, string(req.Params.Cursor), util.PageSizeFromContext(ctx))
+	if err != nil {
+		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
+	}
+
 	result := ListPromptsResult{
-		Prompts: promptset.McpManifest,
+		PaginatedResult: PaginatedResult{NextCursor: Cursor(nextCursor)},
+		Prompts:         manifests,
 	}
-	logger.DebugContext(ctx, fmt.Sprintf("returning %d prompts", len(promptset.McpManifest)))
+	logger.DebugContext(ctx, fmt.Sprintf("returning %d prompts", len(manifests)))
 	return jsonrpc.JSONRPCResponse{
 		Jsonrpc: jsonrpc.JSONRPC_VERSION,
 		Id:      id,
@@ -412,3 +488,256 @@ func promptsGetHandler(ctx context.Context, id jsonrpc.RequestId, resourceMgr *r
 		Result:  result,
 	}, nil
 }
+
+// resourcesListHandler handles the "resources/list" method.
+func resourcesListHandler(ctx context.Context, id jsonrpc.RequestId, toolset tools.Toolset, resourceMgr *resources.ResourceManager, body []byte) (any, error) {
+	// retrieve logger from context
+	logger, err := util.LoggerFromContext(ctx)
+	if err != nil {
+		return jsonrpc.NewError(id, jsonrpc.INTERNAL_ERROR, err.Error(), nil), err
+	}
+	logger.DebugContext(ctx, "handling resources/list request")
+
+	var req ListResourcesRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		err = fmt.Errorf("invalid mcp resources list request: %w", err)
+		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
+	}
+
+	all, _ := mcpresources.Manifests(mcputil.ToolsetResources(toolset, resourceMgr))
+	manifests, nextCursor, err := util.Paginate(all, string(req.Params.Cursor), util.PageSizeFromContext(ctx))
+	if err != nil {
+		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
+	}
+	logger.DebugContext(ctx, fmt.Sprintf("returning %d resources", len(manifests)))
+	return jsonrpc.JSONRPCResponse{
+		Jsonrpc: jsonrpc.JSONRPC_VERSION,
+		Id:      id,
+		Result: ListResourcesResult{
+			PaginatedResult: PaginatedResult{NextCursor: Cursor(nextCursor)},
+			Resources:       manifests,
+		},
+	}, nil
+}
+
+// resourceTemplatesListHandler handles the "resources/templates/list" method.
+func resourceTemplatesListHandler(ctx context.Context, id jsonrpc.RequestId, toolset tools.Toolset, resourceMgr *resources.ResourceManager, body []byte) (any, error) {
+	// retrieve logger from context
+	logger, err := util.LoggerFromContext(ctx)
+	if err != nil {
+		return jsonrpc.NewError(id, jsonrpc.INTERNAL_ERROR, err.Error(), nil), err
+	}
+	logger.DebugContext(ctx, "handling resources/templates/list request")
+
+	var req ListResourceTemplatesRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		err = fmt.Errorf("invalid mcp resources/templates/list request: %w", err)
+		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
+	}
+
+	_, all := mcpresources.Manifests(mcputil.ToolsetResources(toolset, resourceMgr))
+	templates, nextCursor, err := util.Paginate(all, string(req.Params.Cursor), util.PageSizeFromContext(ctx))
+	if err != nil {
+		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
+	}
+	logger.DebugContext(ctx, fmt.Sprintf("returning %d resource templates", len(templates)))
+	return jsonrpc.JSONRPCResponse{
+		Jsonrpc: jsonrpc.JSONRPC_VERSION,
+		Id:      id,
+		Result: ListResourceTemplatesResult{
+			PaginatedResult:   PaginatedResult{NextCursor: Cursor(nextCursor)},
+			ResourceTemplates: templates,
+		},
+	}, nil
+}
+
+// resourcesReadHandler handles the "resources/read" method.
+func resourcesReadHandler(ctx context.Context, id jsonrpc.RequestId, toolset tools.Toolset, resourceMgr *resources.ResourceManager, body []byte) (any, error) {
+	// retrieve logger from context
+	logger, err := util.LoggerFromContext(ctx)
+	if err != nil {
+		return jsonrpc.NewError(id, jsonrpc.INTERNAL_ERROR, err.Error(), nil), err
+	}
+	logger.DebugContext(ctx, "handling resources/read request")
+
+	var req ReadResourceRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		err = fmt.Errorf("invalid mcp resources/read request: %w", err)
+		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
+	}
+
+	uri := req.Params.URI
+	logger.DebugContext(ctx, fmt.Sprintf("resource uri: %s", uri))
+
+	resource, ok := mcpresources.Find(mcputil.ToolsetResources(toolset, resourceMgr), uri)
+	if !ok {
+		err := fmt.Errorf("resource %q not found", uri)
+		return jsonrpc.NewError(id, jsonrpc.RESOURCE_NOT_FOUND, err.Error(), map[string]string{"uri": uri}), err
+	}
+
+	contents, err := resource.Read(ctx, resourceMgr, uri)
+	if err != nil {
+		err = fmt.Errorf("unable to read resource %q: %w", uri, err)
+		return jsonrpc.NewError(id, jsonrpc.INTERNAL_ERROR, err.Error(), nil), err
+	}
+
+	return jsonrpc.JSONRPCResponse{
+		Jsonrpc: jsonrpc.JSONRPC_VERSION,
+		Id:      id,
+		Result:  ReadResourceResult{Contents: contents},
+	}, nil
+}
+
+// completionCompleteHandler handles the "completion/complete" method.
+func completionCompleteHandler(ctx context.Context, id jsonrpc.RequestId, toolset tools.Toolset, promptset prompts.Promptset, resourceMgr *resources.ResourceManager, body []byte) (any, error) {
+	// retrieve logger from context
+	logger, err := util.LoggerFromContext(ctx)
+	if err != nil {
+		return jsonrpc.NewError(id, jsonrpc.INTERNAL_ERROR, err.Error(), nil), err
+	}
+	logger.DebugContext(ctx, "handling completion/complete request")
+
+	var req CompleteRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		err = fmt.Errorf("invalid mcp completion/complete request: %w", err)
+		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
+	}
+
+	completion, err := mcputil.FindCompletion(toolset, promptset, resourceMgr, req.Params.Ref, req.Params.Argument.Name)
+	if err != nil {
+		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
+	}
+	result, err := mcputil.Complete(ctx, toolset, resourceMgr, completion, req.Params.Argument, req.Params.Context.Arguments)
+	if err != nil {
+		err = fmt.Errorf("unable to complete argument %q: %w", req.Params.Argument.Name, err)
+		return jsonrpc.NewError(id, jsonrpc.INTERNAL_ERROR, err.Error(), nil), err
+	}
+	logger.DebugContext(ctx, fmt.Sprintf("returning %d completion values", len(result.Values)))
+	return jsonrpc.JSONRPCResponse{
+		Jsonrpc: jsonrpc.JSONRPC_VERSION,
+		Id:      id,
+		Result:  CompleteResult{Completion: result},
+	}, nil
+}
+
+// taskOutcome converts the response to a tool call into the outcome of its
+// task.
+func taskOutcome(res any, _ error) mcputil.TaskOutcome {
+	switch r := res.(type) {
+	case jsonrpc.JSONRPCError:
+		return mcputil.TaskOutcome{Error: &r.Error}
+	case jsonrpc.JSONRPCResponse:
+		result, _ := r.Result.(CallToolResult)
+		return mcputil.TaskOutcome{Result: r.Result, Failed: result.IsError}
+	default:
+		return mcputil.TaskOutcome{Error: &jsonrpc.Error{Code: jsonrpc.INTERNAL_ERROR, Message: "unexpected tool call response"}}
+	}
+}
+
+// taskRequest decodes a request about a task and retrieves the task store of
+// the session.
+func taskRequest(ctx context.Context, id jsonrpc.RequestId, method string, body []byte) (*mcputil.TaskStore, string, any, error) {
+	store := mcputil.TaskStoreFromContext(ctx)
+	if store == nil {
+		err := fmt.Errorf("%s requires a session", method)
+		return nil, "", jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
+	}
+	var req mcputil.TaskRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		err = fmt.Errorf("invalid mcp %s request: %w", method, err)
+		return nil, "", jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
+	}
+	return store, req.Params.TaskId, nil, nil
+}
+
+// tasksGetHandler handles the "tasks/get" method.
+func tasksGetHandler(ctx context.Context, id jsonrpc.RequestId, body []byte) (any, error) {
+	store, taskId, res, err := taskRequest(ctx, id, mcputil.TASKS_GET, body)
+	if err != nil {
+		return res, err
+	}
+	task, err := store.Get(taskId)
+	if err != nil {
+		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
+	}
+	return jsonrpc.JSONRPCResponse{
+		Jsonrpc: jsonrpc.JSONRPC_VERSION,
+		Id:      id,
+		Result:  mcputil.GetTaskResult{Task: task},
+	}, nil
+}
+
+// tasksResultHandler handles the "tasks/result" method. It waits until the
+// task completes, and returns the result of the tool call.
+func tasksResultHandler(ctx context.Context, id jsonrpc.RequestId, body []byte) (any, error) {
+	store, taskId, res, err := taskRequest(ctx, id, mcputil.TASKS_RESULT, body)
+	if err != nil {
+		return res, err
+	}
+	outcome, err := store.Result(ctx, taskId)
+	if err != nil {
+		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
+	}
+	if outcome.Error != nil {
+		err := errors.New(outcome.Error.Message)
+		return jsonrpc.JSONRPCError{
+			Jsonrpc: jsonrpc.JSONRPC_VERSION,
+			Id:      id,
+			Error:   *outcome.Error,
+		}, err
+	}
+
+	result := outcome.Result
+	if callResult, ok := result.(CallToolResult); ok {
+		callResult.Meta = map[string]any{
+			mcputil.RELATED_TASK_META_KEY: mcputil.RelatedTaskMetadata{TaskId: taskId},
+		}
+		result = callResult
+	}
+	return jsonrpc.JSONRPCResponse{
+		Jsonrpc: jsonrpc.JSONRPC_VERSION,
+		Id:      id,
+		Result:  result,
+	}, nil
+}
+
+// tasksListHandler handles the "tasks/list" method.
+func tasksListHandler(ctx context.Context, id jsonrpc.RequestId, body []byte) (any, error) {
+	store := mcputil.TaskStoreFromContext(ctx)
+	if store == nil {
+		err := fmt.Errorf("%s requires a session", mcputil.TASKS_LIST)
+		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
+	}
+	var req mcputil.ListTasksRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		err = fmt.Errorf("invalid mcp tasks/list request: %w", err)
+		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
+	}
+
+	tasks, nextCursor, err := util.Paginate(store.List(), req.Params.Cursor, util.PageSizeFromContext(ctx))
+	if err != nil {
+		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
+	}
+	return jsonrpc.JSONRPCResponse{
+		Jsonrpc: jsonrpc.JSONRPC_VERSION,
+		Id:      id,
+		Result:  mcputil.ListTasksResult{Tasks: tasks, NextCursor: nextCursor},
+	}, nil
+}
+
+// tasksCancelHandler handles the "tasks/cancel" method.
+func tasksCancelHandler(ctx context.Context, id jsonrpc.RequestId, body []byte) (any, error) {
+	store, taskId, res, err := taskRequest(ctx, id, mcputil.TASKS_CANCEL, body)
+	if err != nil {
+		return res, err
+	}
+	task, err := store.Cancel(taskId)
+	if err != nil {
+		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
+	}
+	return jsonrpc.JSONRPCResponse{
+		Jsonrpc: jsonrpc.JSONRPC_VERSION,
+		Id:      id,
+		Result:  mcputil.GetTaskResult{Task: task},
+	}, nil
+}
@@ -20,17 +20,29 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"maps"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"reflect"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/googleapis/genai-toolbox/internal/auth"
 	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/mcpresources"
+	"github.com/googleapis/genai-toolbox/internal/prompts"
 	"github.com/googleapis/genai-toolbox/internal/server/mcp/jsonrpc"
+	mcputil "github.com/googleapis/genai-toolbox/internal/server/mcp/util"
 	"github.com/googleapis/genai-toolbox/internal/server/resources"
 	"github.com/googleapis/genai-toolbox/internal/telemetry"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"github.com/googleapis/genai-toolbox/internal/util/parameters"
 )
 
 const jsonrpcVersion = "2.0"
@@ -363,7 +375,19 @@ func TestMcpEndpointWithoutInitialized(t *testing.T) {
 	}
 }
 
-func runInitializeLifecycle(t *testing.T, ts *httptest.Server, protocolVersion string, initializeWant map[string]any, idHeader bool) string {
+// addTaskSupport adds the task support that v2025-11-25 sessions announce to
+// the tools listed in want.
+func addTaskSupport(want map[string]any) {
+	result, _ := want["result"].(map[string]any)
+	tools, _ := result["tools"].([]any)
+	for _, tool := range tools {
+		if m, ok := tool.(map[string]any); ok {
+			m["execution"] = map[string]any{"taskSupport": "optional"}
+		}
+	}
+}
+
+func runInitializeLifecycle(t *testing.T, ts *httptest.Server, path string, protocolVersion string, initializeWant map[string]any, idHeader bool) string {
 	initializeRequestBody := map[string]any{
 		"jsonrpc": jsonrpcVersion,
 		"id":      "mcp-initialize",
@@ -377,7 +401,7 @@ func runInitializeLifecycle(t *testing.T, ts *httptest.Server, protocolVersion s
 		t.Fatalf("unexpected error during marshaling of body")
 	}
 
-	resp, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal), nil)
+	resp, body, err := runRequest(ts, http.MethodPost, path, bytes.NewBuffer(reqMarshal), nil)
 	if err != nil {
 		t.Fatalf("unexpected error during request: %s", err)
 	}
@@ -413,7 +437,7 @@ func runInitializeLifecycle(t *testing.T, ts *httptest.Server, protocolVersion s
 		t.Fatalf("unexpected error during marshaling of notifications body")
 	}
 
-	_, _, err = runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(notiMarshal), header)
+	_, _, err = runRequest(ts, http.MethodPost, path, bytes.NewBuffer(notiMarshal), header)
 	if err != nil {
 		t.Fatalf("unexpected error during request: %s", err)
 	}
@@ -445,8 +469,10 @@ func TestMcpEndpoint(t *testing.T) {
 				"result": map[string]any{
 					"protocolVersion": "2024-11-05",
 					"capabilities": map[string]any{
-						"tools":   map[string]any{"listChanged": false},
-						"prompts": map[string]any{"listChanged": false},
+						"tools":     map[string]any{"listChanged": true},
+						"prompts":   map[string]any{"listChanged": true},
+						"resources": map[string]any{"listChanged": false},
+						"logging":   map[string]any{},
 					},
 					"serverInfo": map[string]any{"name": serverName, "version": fakeVersionString},
 				},
@@ -462,8 +488,11 @@ func TestMcpEndpoint(t *testing.T) {
 				"result": map[string]any{
 					"protocolVersion": "2025-03-26",
 					"capabilities": map[string]any{
-						"tools":   map[string]any{"listChanged": false},
-						"prompts": map[string]any{"listChanged": false},
+						"tools":       map[string]any{"listChanged": true},
+						"prompts":     map[string]any{"listChanged": true},
+						"resources":   map[string]any{"listChanged": false},
+						"logging":     map[string]any{},
+						"completions": map[string]any{},
 					},
 					"serverInfo": map[string]any{"name": serverName, "version": fakeVersionString},
 				},
@@ -479,8 +508,11 @@ func TestMcpEndpoint(t *testing.T) {
 				"result": map[string]any{
 					"protocolVersion": "2025-06-18",
 					"capabilities": map[string]any{
-						"tools":   map[string]any{"listChanged": false},
-						"prompts": map[string]any{"listChanged": false},
+						"tools":       map[string]any{"listChanged": true},
+						"prompts":     map[string]any{"listChanged": true},
+						"resources":   map[string]any{"listChanged": false},
+						"logging":     map[string]any{},
+						"completions": map[string]any{},
 					},
 					"serverInfo": map[string]any{"name": serverName, "version": fakeVersionString},
 				},
@@ -496,8 +528,16 @@ func TestMcpEndpoint(t *testing.T) {
 				"result": map[string]any{
 					"protocolVersion": "2025-11-25",
 					"capabilities": map[string]any{
-						"tools":   map[string]any{"listChanged": false},
-						"prompts": map[string]any{"listChanged": false},
+						"tools":       map[string]any{"listChanged": true},
+						"prompts":     map[string]any{"listChanged": true},
+						"resources":   map[string]any{"listChanged": false},
+						"logging":     map[string]any{},
+						"completions": map[string]any{},
+						"tasks": map[string]any{
+							"list":     map[string]any{},
+							"cancel":   map[string]any{},
+							"requests": map[string]any{"tools": map[string]any{"call": map[string]any{}}},
+						},
 					},
 					"serverInfo": map[string]any{"name": serverName, "version": fakeVersionString},
 				},
@@ -506,7 +546,7 @@ func TestMcpEndpoint(t *testing.T) {
 	}
 	for _, vtc := range versTestCases {
 		t.Run(vtc.name, func(t *testing.T) {
-			sessionId := runInitializeLifecycle(t, ts, vtc.protocol, vtc.initWant, vtc.idHeader)
+			sessionId := runInitializeLifecycle(t, ts, "/", vtc.protocol, vtc.initWant, vtc.idHeader)
 
 			header := map[string]string{}
 			if sessionId != "" {
@@ -677,6 +717,34 @@ func TestMcpEndpoint(t *testing.T) {
 						},
 					},
 				},
+				{
+					name:  "tools/call outside of tool1_only",
+					url:   "/tool1_only",
+					isErr: true,
+					body: jsonrpc.JSONRPCRequest{
+						Jsonrpc: jsonrpcVersion,
+						Id:      "tools-call-outside-toolset",
+						Request: jsonrpc.Request{
+							Method: "tools/call",
+						},
+						Params: map[string]any{
+							"name": "some_params",
+							"arguments": map[string]any{
+								"param1": 1,
+								"param2": 2,
+							},
+						},
+					},
+					wantStatusCode: http.StatusOK,
+					want: map[string]any{
+						"jsonrpc": "2.0",
+						"id":      "tools-call-outside-toolset",
+						"error": map[string]any{
+							"code":    -32602.0,
+							"message": `invalid tool name: tool with name "some_params" does not exist`,
+						},
+					},
+				},
 				{
 					name:  "tools/list on invalid tool set",
 					url:   "/foo",
@@ -873,7 +941,16 @@ func TestMcpEndpoint(t *testing.T) {
 						t.Fatalf("header is missing")
 					}
 
-					resp, body, err := runRequest(ts, http.MethodPost, tc.url, bytes.NewBuffer(reqMarshal), header)
+					// sessions are bound to a toolset, so other toolsets need their own session
+					reqHeader := header
+					if tc.url != "/" {
+						reqHeader = maps.Clone(header)
+						if toolsetSessionId := runInitializeLifecycle(t, ts, tc.url, vtc.protocol, vtc.initWant, vtc.idHeader); toolsetSessionId != "" {
+							reqHeader["Mcp-Session-Id"] = toolsetSessionId
+						}
+					}
+
+					resp, body, err := runRequest(ts, http.MethodPost, tc.url, bytes.NewBuffer(reqMarshal), reqHeader)
 
 					if err != nil {
 						t.Fatalf("unexpected error during request: %s", err)
@@ -897,6 +974,9 @@ func TestMcpEndpoint(t *testing.T) {
 						if tc.want["id"] == nil {
 							tc.want["id"] = got["id"]
 						}
+						if vtc.protocol == protocolVersion20251125 && sessionId != "" {
+							addTaskSupport(tc.want)
+						}
 						if !reflect.DeepEqual(got, tc.want) {
 							t.Fatalf("unexpected response: got %+v, want %+v", got, tc.want)
 						}
@@ -907,6 +987,161 @@ func TestMcpEndpoint(t *testing.T) {
 	}
 }
 
+func TestMcpResources(t *testing.T) {
+	resource1 := MockResource{Name: "readme", URI: "file:///docs/readme"}
+	resource2 := MockResource{Name: "table_schema", URITemplate: "mock://{schema}/{table}"}
+	toolsMap, toolsets, promptsMap, promptsets := setUpResources(t, []MockTool{tool1, tool2}, []MockPrompt{prompt1})
+	defaultToolset, scopedToolset := toolsets[""], toolsets["tool1_only"]
+	defaultToolset.ResourceNames = []string{resource1.Name, resource2.Name}
+	scopedToolset.ResourceNames = []string{resource1.Name}
+	toolsets[""], toolsets["tool1_only"] = defaultToolset, scopedToolset
+	resourceManager := resources.NewResourceManager(nil, nil, nil, toolsMap, toolsets, promptsMap, promptsets, map[string]mcpresources.Resource{
+		resource1.Name: resource1,
+		resource2.Name: resource2,
+	})
+	r, shutdown := setUpServerWithResourceManager(t, "mcp", resourceManager)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	testCases := []struct {
+		name   string
+		method string
+		params map[string]any
+		want   map[string]any
+	}{
+		{
+			name:   "resources/list",
+			method: "resources/list",
+			want: map[string]any{
+				"resources": []any{
+					map[string]any{"uri": "file:///docs/readme", "name": "readme", "mimeType": "text/plain"},
+				},
+			},
+		},
+		{
+			name:   "resources/templates/list",
+			method: "resources/templates/list",
+			want: map[string]any{
+				"resourceTemplates": []any{
+					map[string]any{"uriTemplate": "mock://{schema}/{table}", "name": "table_schema", "mimeType": "text/plain"},
+				},
+			},
+		},
+		{
+			name:   "resources/read resource",
+			method: "resources/read",
+			params: map[string]any{"uri": "file:///docs/readme"},
+			want: map[string]any{
+				"contents": []any{
+					map[string]any{"uri": "file:///docs/readme", "mimeType": "text/plain", "text": "contents of readme"},
+				},
+			},
+		},
+		{
+			name:   "resources/read resource template",
+			method: "resources/read",
+			params: map[string]any{"uri": "mock://public/orders"},
+			want: map[string]any{
+				"contents": []any{
+					map[string]any{"uri": "mock://public/orders", "mimeType": "text/plain", "text": "contents of table_schema"},
+				},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reqMarshal, err := json.Marshal(map[string]any{
+				"jsonrpc": jsonrpcVersion,
+				"id":      tc.name,
+				"method":  tc.method,
+				"params":  tc.params,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error during marshaling of body")
+			}
+			_, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal), nil)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			var got map[string]any
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unexpected error unmarshalling body: %s", err)
+			}
+			if !reflect.DeepEqual(got["result"], tc.want) {
+				t.Fatalf("unexpected result: got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+
+	t.Run("resources/read unknown uri", func(t *testing.T) {
+		reqMarshal, err := json.Marshal(map[string]any{
+			"jsonrpc": jsonrpcVersion,
+			"id":      "read-unknown",
+			"method":  "resources/read",
+			"params":  map[string]any{"uri": "file:///unknown"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error during marshaling of body")
+		}
+		_, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal), nil)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		var got jsonrpc.JSONRPCError
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Fatalf("unexpected error unmarshalling body: %s", err)
+		}
+		if got.Error.Code != jsonrpc.RESOURCE_NOT_FOUND {
+			t.Fatalf("unexpected error code: got %d, want %d", got.Error.Code, jsonrpc.RESOURCE_NOT_FOUND)
+		}
+	})
+
+	t.Run("resources outside the toolset", func(t *testing.T) {
+		reqMarshal, err := json.Marshal(map[string]any{
+			"jsonrpc": jsonrpcVersion,
+			"id":      "list-scoped",
+			"method":  "resources/templates/list",
+		})
+		if err != nil {
+			t.Fatalf("unexpected error during marshaling of body")
+		}
+		_, body, err := runRequest(ts, http.MethodPost, "/tool1_only", bytes.NewBuffer(reqMarshal), nil)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		var list map[string]any
+		if err := json.Unmarshal(body, &list); err != nil {
+			t.Fatalf("unexpected error unmarshalling body: %s", err)
+		}
+		want := map[string]any{"resourceTemplates": []any{}}
+		if !reflect.DeepEqual(list["result"], want) {
+			t.Fatalf("unexpected result: got %+v, want %+v", list, want)
+		}
+
+		reqMarshal, err = json.Marshal(map[string]any{
+			"jsonrpc": jsonrpcVersion,
+			"id":      "read-scoped",
+			"method":  "resources/read",
+			"params":  map[string]any{"uri": "mock://public/orders"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error during marshaling of body")
+		}
+		_, body, err = runRequest(ts, http.MethodPost, "/tool1_only", bytes.NewBuffer(reqMarshal), nil)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		var got jsonrpc.JSONRPCError
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Fatalf("unexpected error unmarshalling body: %s", err)
+		}
+		if got.Error.Code != jsonrpc.RESOURCE_NOT_FOUND {
+			t.Fatalf("unexpected error code: got %d, want %d", got.Error.Code, jsonrpc.RESOURCE_NOT_FOUND)
+		}
+	})
+}
+
 func TestInvalidProtocolVersionHeader(t *testing.T) {
 	r, shutdown := setUpServer(t, "mcp", nil, nil, nil, nil)
 	defer shutdown()
@@ -933,6 +1168,91 @@ func TestInvalidProtocolVersionHeader(t *testing.T) {
 	}
 }
 
+func TestMcpSessionToolsetBinding(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2}
+	toolsMap, toolsets, _, _ := setUpResources(t, mockTools, nil)
+	r, shutdown := setUpServer(t, "mcp", toolsMap, toolsets, nil, nil)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	initWant := map[string]any{
+		"jsonrpc": "2.0",
+		"id":      "mcp-initialize",
+		"result": map[string]any{
+			"protocolVersion": protocolVersion20250618,
+			"capabilities": map[string]any{
+				"tools":       map[string]any{"listChanged": true},
+				"prompts":     map[string]any{"listChanged": true},
+				"resources":   map[string]any{"listChanged": false},
+				"logging":     map[string]any{},
+				"completions": map[string]any{},
+			},
+			"serverInfo": map[string]any{"name": serverName, "version": fakeVersionString},
+		},
+	}
+	sessionId := runInitializeLifecycle(t, ts, "/tool1_only", protocolVersion20250618, initWant, true)
+	header := map[string]string{"Mcp-Session-Id": sessionId}
+
+	reqMarshal, err := json.Marshal(jsonrpc.JSONRPCRequest{
+		Jsonrpc: jsonrpcVersion,
+		Id:      "tools-list",
+		Request: jsonrpc.Request{Method: "tools/list"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error during marshaling of body")
+	}
+
+	testCases := []struct {
+		name           string
+		method         string
+		url            string
+		wantStatusCode int
+	}{
+		{
+			name:           "same toolset",
+			method:         http.MethodPost,
+			url:            "/tool1_only",
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "default toolset",
+			method:         http.MethodPost,
+			url:            "/",
+			wantStatusCode: http.StatusForbidden,
+		},
+		{
+			name:           "other toolset",
+			method:         http.MethodPost,
+			url:            "/tool2_only",
+			wantStatusCode: http.StatusForbidden,
+		},
+		{
+			name:           "terminate session",
+			method:         http.MethodDelete,
+			url:            "/tool1_only",
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "terminated session",
+			method:         http.MethodPost,
+			url:            "/tool1_only",
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, body, err := runRequest(ts, tc.method, tc.url, bytes.NewBuffer(reqMarshal), header)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != tc.wantStatusCode {
+				t.Fatalf("StatusCode mismatch: got %d, want %d, body: %s", resp.StatusCode, tc.wantStatusCode, body)
+			}
+		})
+	}
+}
+
 func TestDeleteEndpoint(t *testing.T) {
 	r, shutdown := setUpServer(t, "mcp", nil, nil, nil, nil)
 	defer shutdown()
@@ -954,21 +1274,211 @@ func TestGetEndpoint(t *testing.T) {
 	ts := runServer(r, false)
 	defer ts.Close()
 
-	resp, body, err := runRequest(ts, http.MethodGet, "/", nil, nil)
-	if resp.Status != "405 Method Not Allowed" {
-		t.Fatalf("unexpected status: %s", resp.Status)
+	testCases := []struct {
+		name           string
+		header         map[string]string
+		wantStatusCode int
+		wantErr        string
+	}{
+		{
+			name:           "missing accept header",
+			wantStatusCode: http.StatusNotAcceptable,
+			wantErr:        "client must accept text/event-stream",
+		},
+		{
+			name:           "missing session id",
+			header:         map[string]string{"Accept": "text/event-stream"},
+			wantStatusCode: http.StatusBadRequest,
+			wantErr:        "missing Mcp-Session-Id header",
+		},
+		{
+			name:           "unknown session id",
+			header:         map[string]string{"Accept": "text/event-stream", "Mcp-Session-Id": "unknown"},
+			wantStatusCode: http.StatusNotFound,
+			wantErr:        "session not found",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, body, err := runRequest(ts, http.MethodGet, "/", nil, tc.header)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != tc.wantStatusCode {
+				t.Fatalf("unexpected status: got %d, want %d", resp.StatusCode, tc.wantStatusCode)
+			}
+			var got map[string]any
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unexpected error unmarshalling body: %s", err)
+			}
+			if got["error"] != tc.wantErr {
+				t.Fatalf("unexpected error message: got %s, want %s", got["error"], tc.wantErr)
+			}
+		})
+	}
+}
+
+// readSseEvent reads the data of the next SSE event from reader.
+func readSseEvent(reader *bufio.Reader) (map[string]any, error) {
+	var data string
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\n")
+		if line == "" && data != "" {
+			break
+		}
+		if d, ok := strings.CutPrefix(line, "data: "); ok {
+			data = d
+		}
 	}
 	var got map[string]any
-	if err := json.Unmarshal(body, &got); err != nil {
-		t.Fatalf("unexpected error unmarshalling body: %s", err)
+	if err := json.Unmarshal([]byte(data), &got); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal event data %q: %w", data, err)
 	}
-	want := "toolbox does not support streaming in streamable HTTP transport"
-	if got["error"] != want {
-		t.Fatalf("unexpected error message: %s", got["error"])
+	return got, nil
+}
+
+func TestStreamableHTTPStreaming(t *testing.T) {
+	notification := map[string]any{
+		"jsonrpc": jsonrpcVersion,
+		"method":  "notifications/message",
+		"params":  map[string]any{"level": "info", "data": "working"},
+	}
+	notifyingTool := MockTool{
+		Name:          "notifying_tool",
+		Params:        []parameters.Parameter{},
+		notifications: []any{notification},
+	}
+	toolsMap, toolsets, promptsMap, promptsets := setUpResources(t, []MockTool{tool1, tool2, notifyingTool}, []MockPrompt{prompt1})
+	r, shutdown := setUpServer(t, "mcp", toolsMap, toolsets, promptsMap, promptsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	initWant := map[string]any{
+		"jsonrpc": "2.0",
+		"id":      "mcp-initialize",
+		"result": map[string]any{
+			"protocolVersion": protocolVersion20250618,
+			"capabilities": map[string]any{
+				"tools":       map[string]any{"listChanged": true},
+				"prompts":     map[string]any{"listChanged": true},
+				"resources":   map[string]any{"listChanged": false},
+				"logging":     map[string]any{},
+				"completions": map[string]any{},
+			},
+			"serverInfo": map[string]any{"name": serverName, "version": fakeVersionString},
+		},
 	}
+	sessionId := runInitializeLifecycle(t, ts, "/", protocolVersion20250618, initWant, true)
+
+	reqMarshal, err := json.Marshal(map[string]any{
+		"jsonrpc": jsonrpcVersion,
+		"id":      "tools-call",
+		"method":  "tools/call",
+		"params":  map[string]any{"name": "notifying_tool"},
+	})
 	if err != nil {
-		t.Fatalf("unexpected error during request: %s", err)
+		t.Fatalf("unexpected error during marshaling of body")
+	}
+	wantResponse := map[string]any{
+		"jsonrpc": jsonrpcVersion,
+		"id":      "tools-call",
+		"result": map[string]any{
+			"content": []any{map[string]any{"type": "text", "text": `"notifying_tool"`}},
+		},
 	}
+
+	t.Run("POST response is upgraded to a stream", func(t *testing.T) {
+		header := map[string]string{
+			"Mcp-Session-Id": sessionId,
+			"Accept":         "application/json, text/event-stream",
+		}
+		resp, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal), header)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		if contentType := resp.Header.Get("Content-Type"); contentType != "text/event-stream" {
+			t.Fatalf("unexpected content-type header: got %s, want text/event-stream", contentType)
+		}
+		reader := bufio.NewReader(bytes.NewReader(body))
+		got, err := readSseEvent(reader)
+		if err != nil {
+			t.Fatalf("unable to read notification event: %s", err)
+		}
+		if !reflect.DeepEqual(got, notification) {
+			t.Fatalf("unexpected notification: got %v, want %v", got, notification)
+		}
+		got, err = readSseEvent(reader)
+		if err != nil {
+			t.Fatalf("unable to read response event: %s", err)
+		}
+		if !reflect.DeepEqual(got, wantResponse) {
+			t.Fatalf("unexpected response: got %v, want %v", got, wantResponse)
+		}
+	})
+
+	t.Run("messages are delivered through the GET stream", func(t *testing.T) {
+		// without text/event-stream, the response is plain JSON and the
+		// notification is queued for the GET stream
+		resp, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal), map[string]string{"Mcp-Session-Id": sessionId})
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		if contentType := resp.Header.Get("Content-Type"); contentType != "application/json" {
+			t.Fatalf("unexpected content-type header: got %s, want application/json", contentType)
+		}
+		var got map[string]any
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Fatalf("unexpected error unmarshalling body: %s", err)
+		}
+		if !reflect.DeepEqual(got, wantResponse) {
+			t.Fatalf("unexpected response: got %v, want %v", got, wantResponse)
+		}
+
+		req, err := http.NewRequest(http.MethodGet, ts.URL+"/", nil)
+		if err != nil {
+			t.Fatalf("unable to create request: %s", err)
+		}
+		req.Header.Set("Accept", "text/event-stream")
+		req.Header.Set("Mcp-Session-Id", sessionId)
+		streamResp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("unable to send request: %s", err)
+		}
+		defer streamResp.Body.Close()
+		if streamResp.StatusCode != http.StatusOK {
+			t.Fatalf("unexpected status: got %d, want %d", streamResp.StatusCode, http.StatusOK)
+		}
+		reader := bufio.NewReader(streamResp.Body)
+		got, err = readSseEvent(reader)
+		if err != nil {
+			t.Fatalf("unable to read notification event: %s", err)
+		}
+		if !reflect.DeepEqual(got, notification) {
+			t.Fatalf("unexpected notification: got %v, want %v", got, notification)
+		}
+
+		// only one stream may be open per session
+		conflictResp, _, err := runRequest(ts, http.MethodGet, "/", nil, map[string]string{"Accept": "text/event-stream", "Mcp-Session-Id": sessionId})
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		if conflictResp.StatusCode != http.StatusConflict {
+			t.Fatalf("unexpected status: got %d, want %d", conflictResp.StatusCode, http.StatusConflict)
+		}
+
+		// terminating the session closes the stream
+		if _, _, err := runRequest(ts, http.MethodDelete, "/", nil, map[string]string{"Mcp-Session-Id": sessionId}); err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		if _, err := io.ReadAll(reader); err != nil {
+			t.Fatalf("unexpected error reading stream: %s", err)
+		}
+	})
 }
 
 func TestSseEndpoint(t *testing.T) {
@@ -1124,7 +1634,7 @@ func TestStdioSession(t *testing.T) {
 
 	sseManager := newSseManager(ctx)
 
-	resourceManager := resources.NewResourceManager(nil, nil, nil, toolsMap, toolsets, promptsMap, promptsets)
+	resourceManager := resources.NewResourceManager(nil, nil, nil, toolsMap, toolsets, promptsMap, promptsets, nil)
 
 	server := &Server{
 		version:         fakeVersionString,
@@ -1200,3 +1710,1655 @@ func TestSseManagerGetNilSessionValue(t *testing.T) {
 		t.Error("expected nil session for nil session value")
 	}
 }
+
+func TestSseManagerRemoveIdle(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m := newSseManager(ctx)
+	idle := &sseSession{done: make(chan struct{}), mcpSession: newMcpSession(mcputil.NewTaskPool(1, 1))}
+	active := &sseSession{done: make(chan struct{})}
+	m.add("idle", idle)
+	m.add("active", active)
+	idle.lastActive = time.Now().Add(-time.Hour)
+
+	started := make(chan struct{})
+	task, err := idle.tasks.Start(ctx, mcputil.TaskMetadata{}, func(ctx context.Context) mcputil.TaskOutcome {
+		close(started)
+		<-ctx.Done()
+		return mcputil.TaskOutcome{}
+	}, nil)
+	if err != nil {
+		t.Fatalf("unable to start task: %s", err)
+	}
+	<-started
+
+	m.removeIdle(time.Minute)
+	if _, ok := m.get("idle"); ok {
+		t.Fatalf("expected the idle session to be removed")
+	}
+	if _, ok := m.get("active"); !ok {
+		t.Fatalf("expected the active session to be kept")
+	}
+	select {
+	case <-idle.done:
+	default:
+		t.Fatalf("expected the idle session to be closed")
+	}
+	got, err := idle.tasks.Get(task.TaskId)
+	if err != nil {
+		t.Fatalf("unable to get task: %s", err)
+	}
+	if got.Status != mcputil.TaskCancelled {
+		t.Fatalf("expected the task of the idle session to be cancelled, got %s", got.Status)
+	}
+}
+
+func TestProgressNotifications(t *testing.T) {
+	progressTool := MockTool{
+		Name:     "progress_tool",
+		Params:   []parameters.Parameter{},
+		progress: []float64{1, 2},
+	}
+	toolsMap, toolsets, promptsMap, promptsets := setUpResources(t, []MockTool{tool1, tool2, progressTool}, []MockPrompt{prompt1})
+	r, shutdown := setUpServer(t, "mcp", toolsMap, toolsets, promptsMap, promptsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	reqMarshal, err := json.Marshal(map[string]any{
+		"jsonrpc": jsonrpcVersion,
+		"id":      "tools-call",
+		"method":  "tools/call",
+		"params": map[string]any{
+			"name":  "progress_tool",
+			"_meta": map[string]any{"progressToken": "my-token"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error during marshaling of body")
+	}
+
+	testCases := []struct {
+		name     string
+		protocol string
+		initWant map[string]any
+		want     []map[string]any
+	}{
+		{
+			name:     "version 2025-06-18",
+			protocol: protocolVersion20250618,
+			initWant: map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "mcp-initialize",
+				"result": map[string]any{
+					"protocolVersion": protocolVersion20250618,
+					"capabilities": map[string]any{
+						"tools":       map[string]any{"listChanged": true},
+						"prompts":     map[string]any{"listChanged": true},
+						"resources":   map[string]any{"listChanged": false},
+						"logging":     map[string]any{},
+						"completions": map[string]any{},
+					},
+					"serverInfo": map[string]any{"name": serverName, "version": fakeVersionString},
+				},
+			},
+			want: []map[string]any{
+				{
+					"jsonrpc": jsonrpcVersion,
+					"method":  "notifications/progress",
+					"params":  map[string]any{"progressToken": "my-token", "progress": 1.0, "total": 2.0, "message": "step 1"},
+				},
+				{
+					"jsonrpc": jsonrpcVersion,
+					"method":  "notifications/progress",
+					"params":  map[string]any{"progressToken": "my-token", "progress": 2.0, "total": 2.0, "message": "step 2"},
+				},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sessionId := runInitializeLifecycle(t, ts, "/", tc.protocol, tc.initWant, true)
+			header := map[string]string{
+				"Mcp-Session-Id": sessionId,
+				"Accept":         "application/json, text/event-stream",
+			}
+			_, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal), header)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			reader := bufio.NewReader(bytes.NewReader(body))
+			for _, want := range tc.want {
+				got, err := readSseEvent(reader)
+				if err != nil {
+					t.Fatalf("unable to read progress event: %s", err)
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Fatalf("unexpected progress notification: got %v, want %v", got, want)
+				}
+			}
+			got, err := readSseEvent(reader)
+			if err != nil {
+				t.Fatalf("unable to read response event: %s", err)
+			}
+			if got["id"] != "tools-call" || got["result"] == nil {
+				t.Fatalf("unexpected response: %v", got)
+			}
+		})
+	}
+}
+
+func TestRequestCancellation(t *testing.T) {
+	started := make(chan struct{}, 1)
+	blockingTool := MockTool{
+		Name:    "blocking_tool",
+		Params:  []parameters.Parameter{},
+		started: started,
+	}
+	toolsMap, toolsets, promptsMap, promptsets := setUpResources(t, []MockTool{tool1, tool2, blockingTool}, []MockPrompt{prompt1})
+	r, shutdown := setUpServer(t, "mcp", toolsMap, toolsets, promptsMap, promptsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	initWant := map[string]any{
+		"jsonrpc": "2.0",
+		"id":      "mcp-initialize",
+		"result": map[string]any{
+			"protocolVersion": protocolVersion20250618,
+			"capabilities": map[string]any{
+				"tools":       map[string]any{"listChanged": true},
+				"prompts":     map[string]any{"listChanged": true},
+				"resources":   map[string]any{"listChanged": false},
+				"logging":     map[string]any{},
+				"completions": map[string]any{},
+			},
+			"serverInfo": map[string]any{"name": serverName, "version": fakeVersionString},
+		},
+	}
+	sessionId := runInitializeLifecycle(t, ts, "/", protocolVersion20250618, initWant, true)
+	header := map[string]string{"Mcp-Session-Id": sessionId}
+
+	callMarshal, err := json.Marshal(map[string]any{
+		"jsonrpc": jsonrpcVersion,
+		"id":      42,
+		"method":  "tools/call",
+		"params":  map[string]any{"name": "blocking_tool"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error during marshaling of body")
+	}
+	type result struct {
+		resp *http.Response
+		body []byte
+		err  error
+	}
+	results := make(chan result, 1)
+	go func() {
+		resp, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(callMarshal), header)
+		results <- result{resp, body, err}
+	}()
+	<-started
+
+	cancelMarshal, err := json.Marshal(map[string]any{
+		"jsonrpc": jsonrpcVersion,
+		"method":  "notifications/cancelled",
+		"params":  map[string]any{"requestId": 42, "reason": "user cancelled"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error during marshaling of body")
+	}
+	resp, _, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(cancelMarshal), header)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("unexpected status for notification: got %d, want %d", resp.StatusCode, http.StatusAccepted)
+	}
+
+	res := <-results
+	if res.err != nil {
+		t.Fatalf("unexpected error during request: %s", res.err)
+	}
+	// no response is sent for a cancelled request
+	if res.resp.StatusCode != http.StatusAccepted || len(res.body) != 0 {
+		t.Fatalf("unexpected response for cancelled request: status %d, body %s", res.resp.StatusCode, res.body)
+	}
+}
+
+func TestStdioSessionCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	started := make(chan struct{}, 1)
+	blockingTool := MockTool{
+		Name:    "blocking_tool",
+		Params:  []parameters.Parameter{},
+		started: started,
+	}
+	toolsMap, toolsets, promptsMap, promptsets := setUpResources(t, []MockTool{tool1, tool2, blockingTool}, []MockPrompt{prompt1})
+
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "warn")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(fakeVersionString)
+	if err != nil {
+		t.Fatalf("unable to create custom metrics: %s", err)
+	}
+	server := &Server{
+		version:         fakeVersionString,
+		logger:          testLogger,
+		instrumentation: instrumentation,
+		sseManager:      newSseManager(ctx),
+		ResourceMgr:     resources.NewResourceManager(nil, nil, nil, toolsMap, toolsets, promptsMap, promptsets, nil),
+	}
+
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	stdioSession := NewStdioSession(server, inR, outW)
+	go func() {
+		_ = stdioSession.Start(util.WithLogger(ctx, testLogger))
+	}()
+	out := bufio.NewReader(outR)
+
+	send := func(message map[string]any) {
+		b, err := json.Marshal(message)
+		if err != nil {
+			t.Fatalf("unexpected error during marshaling of message")
+		}
+		if _, err := fmt.Fprintf(inW, "%s\n", b); err != nil {
+			t.Fatalf("unable to write message: %s", err)
+		}
+	}
+	read := func() map[string]any {
+		line, err := out.ReadString('\n')
+		if err != nil {
+			t.Fatalf("unable to read response: %s", err)
+		}
+		var got map[string]any
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatalf("unexpected error unmarshalling response: %s", err)
+		}
+		return got
+	}
+
+	send(map[string]any{
+		"jsonrpc": jsonrpcVersion,
+		"id":      "mcp-initialize",
+		"method":  "initialize",
+		"params":  map[string]any{"protocolVersion": protocolVersion20250618},
+	})
+	if got := read(); got["id"] != "mcp-initialize" {
+		t.Fatalf("unexpected initialize response: %v", got)
+	}
+
+	send(map[string]any{
+		"jsonrpc": jsonrpcVersion,
+		"id":      "tools-call",
+		"method":  "tools/call",
+		"params":  map[string]any{"name": "blocking_tool"},
+	})
+	<-started
+	send(map[string]any{
+		"jsonrpc": jsonrpcVersion,
+		"method":  "notifications/cancelled",
+		"params":  map[string]any{"requestId": "tools-call"},
+	})
+	send(map[string]any{
+		"jsonrpc": jsonrpcVersion,
+		"id":      "tools-list",
+		"method":  "tools/list",
+	})
+
+	// the cancelled request has no response, so the next response is for
+	// tools/list
+	if got := read(); got["id"] != "tools-list" {
+		t.Fatalf("unexpected response: got %v, want response to tools-list", got)
+	}
+}
+
+func TestMcpStructuredOutput(t *testing.T) {
+	structuredTool := MockTool{
+		Name:         "structured_tool",
+		Params:       []parameters.Parameter{},
+		outputSchema: tools.NewRowsOutputSchema(),
+	}
+	toolsMap, toolsets, promptsMap, promptsets := setUpResources(t, []MockTool{tool1, tool2, structuredTool}, []MockPrompt{prompt1})
+	r, shutdown := setUpServer(t, "mcp", toolsMap, toolsets, promptsMap, promptsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	wantSchema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"result": map[string]any{
+				"type":        "array",
+				"description": "The rows returned by the tool.",
+				"items":       map[string]any{"type": "object", "description": "A row, keyed by column name."},
+			},
+		},
+		"required": []any{"result"},
+	}
+
+	testCases := []struct {
+		name           string
+		protocol       string
+		wantStructured bool
+	}{
+		{name: "version 2024-11-05", protocol: protocolVersion20241105, wantStructured: false},
+		{name: "version 2025-03-26", protocol: protocolVersion20250326, wantStructured: false},
+		{name: "version 2025-06-18", protocol: protocolVersion20250618, wantStructured: true},
+		{name: "version 2025-11-25", protocol: protocolVersion20251125, wantStructured: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			header := map[string]string{"MCP-Protocol-Version": tc.protocol}
+			run := func(method string, params map[string]any) map[string]any {
+				reqMarshal, err := json.Marshal(map[string]any{
+					"jsonrpc": jsonrpcVersion,
+					"id":      method,
+					"method":  method,
+					"params":  params,
+				})
+				if err != nil {
+					t.Fatalf("unexpected error during marshaling of body")
+				}
+				_, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal), header)
+				if err != nil {
+					t.Fatalf("unexpected error during request: %s", err)
+				}
+				var got map[string]any
+				if err := json.Unmarshal(body, &got); err != nil {
+					t.Fatalf("unexpected error unmarshalling body: %s", err)
+				}
+				result, ok := got["result"].(map[string]any)
+				if !ok {
+					t.Fatalf("unexpected response: %v", got)
+				}
+				return result
+			}
+
+			var manifest map[string]any
+			for _, m := range run("tools/list", nil)["tools"].([]any) {
+				if m.(map[string]any)["name"] == "structured_tool" {
+					manifest = m.(map[string]any)
+				}
+			}
+			gotSchema, ok := manifest["outputSchema"]
+			if ok != tc.wantStructured {
+				t.Fatalf("unexpected outputSchema presence: got %v", manifest)
+			}
+			if ok && !reflect.DeepEqual(gotSchema, wantSchema) {
+				t.Fatalf("unexpected outputSchema: got %v, want %v", gotSchema, wantSchema)
+			}
+
+			result := run("tools/call", map[string]any{"name": "structured_tool"})
+			gotStructured, ok := result["structuredContent"]
+			if ok != tc.wantStructured {
+				t.Fatalf("unexpected structuredContent presence: got %v", result)
+			}
+			wantStructured := map[string]any{"result": []any{"structured_tool"}}
+			if ok && !reflect.DeepEqual(gotStructured, wantStructured) {
+				t.Fatalf("unexpected structuredContent: got %v, want %v", gotStructured, wantStructured)
+			}
+			wantContent := []any{map[string]any{"type": "text", "text": `"structured_tool"`}}
+			if !reflect.DeepEqual(result["content"], wantContent) {
+				t.Fatalf("unexpected content: got %v, want %v", result["content"], wantContent)
+			}
+		})
+	}
+}
+
+func TestMcpPagination(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2, tool3, tool4, tool5}
+	toolsMap, toolsets, promptsMap, promptsets := setUpResources(t, mockTools, []MockPrompt{prompt1, prompt2})
+	resourceManager := resources.NewResourceManager(nil, nil, nil, toolsMap, toolsets, promptsMap, promptsets, nil)
+	r, shutdown := setUpServerWithPageSize(t, "mcp", resourceManager, 2)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	header := map[string]string{"MCP-Protocol-Version": protocolVersion20250618}
+	run := func(method string, cursor string) map[string]any {
+		params := map[string]any{}
+		if cursor != "" {
+			params["cursor"] = cursor
+		}
+		reqMarshal, err := json.Marshal(map[string]any{
+			"jsonrpc": jsonrpcVersion,
+			"id":      method,
+			"method":  method,
+			"params":  params,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error during marshaling of body")
+		}
+		_, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal), header)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		var got map[string]any
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Fatalf("unexpected error unmarshalling body: %s", err)
+		}
+		return got
+	}
+
+	t.Run("tools/list", func(t *testing.T) {
+		var gotNames []string
+		var gotPages int
+		cursor := ""
+		for {
+			got := run("tools/list", cursor)
+			result, ok := got["result"].(map[string]any)
+			if !ok {
+				t.Fatalf("unexpected response: %v", got)
+			}
+			page := result["tools"].([]any)
+			if len(page) > 2 {
+				t.Fatalf("page exceeds page size: got %d tools", len(page))
+			}
+			for _, m := range page {
+				gotNames = append(gotNames, m.(map[string]any)["name"].(string))
+			}
+			gotPages++
+			next, ok := result["nextCursor"].(string)
+			if !ok {
+				break
+			}
+			cursor = next
+		}
+		wantNames := []string{tool1.Name, tool2.Name, tool3.Name, tool4.Name, tool5.Name}
+		if !reflect.DeepEqual(gotNames, wantNames) {
+			t.Fatalf("unexpected tools: got %v, want %v", gotNames, wantNames)
+		}
+		if gotPages != 3 {
+			t.Fatalf("unexpected number of pages: got %d, want 3", gotPages)
+		}
+	})
+
+	t.Run("tools/list across a reload", func(t *testing.T) {
+		ctx, err := testutils.ContextWithNewLogger()
+		if err != nil {
+			t.Fatalf("unable to set up logger: %s", err)
+		}
+		instrumentation, err := telemetry.CreateTelemetryInstrumentation(fakeVersionString)
+		if err != nil {
+			t.Fatalf("unable to create instrumentation: %s", err)
+		}
+		ctx = util.WithInstrumentation(ctx, instrumentation)
+		cfg := ServerConfig{Version: fakeVersionString, ToolConfigs: ToolConfigs{}}
+		for _, tool := range mockTools {
+			cfg.ToolConfigs[tool.Name] = MockToolConfig{Tool: tool}
+		}
+		reload := func() {
+			initialized, err := InitializeConfigs(ctx, cfg)
+			if err != nil {
+				t.Fatalf("unable to initialize configs: %s", err)
+			}
+			resourceManager.SetResources(nil, nil, nil, initialized.Tools, initialized.Toolsets, promptsMap, promptsets, nil)
+		}
+		reload()
+
+		var gotNames []string
+		cursor := ""
+		for {
+			got := run("tools/list", cursor)
+			result, ok := got["result"].(map[string]any)
+			if !ok {
+				t.Fatalf("unexpected response: %v", got)
+			}
+			for _, m := range result["tools"].([]any) {
+				gotNames = append(gotNames, m.(map[string]any)["name"].(string))
+			}
+			next, ok := result["nextCursor"].(string)
+			if !ok {
+				break
+			}
+			cursor = next
+			// the tools are reloaded between the pages
+			reload()
+		}
+		wantNames := []string{tool1.Name, tool2.Name, tool3.Name, tool4.Name, tool5.Name}
+		slices.Sort(wantNames)
+		if !reflect.DeepEqual(gotNames, wantNames) {
+			t.Fatalf("unexpected tools: got %v, want %v", gotNames, wantNames)
+		}
+	})
+
+	t.Run("prompts/list fits in one page", func(t *testing.T) {
+		got := run("prompts/list", "")
+		result, ok := got["result"].(map[string]any)
+		if !ok {
+			t.Fatalf("unexpected response: %v", got)
+		}
+		if n := len(result["prompts"].([]any)); n != 2 {
+			t.Fatalf("unexpected number of prompts: got %d, want 2", n)
+		}
+		if _, ok := result["nextCursor"]; ok {
+			t.Fatalf("unexpected nextCursor: %v", result)
+		}
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		got := run("tools/list", "not-a-cursor")
+		rpcErr, ok := got["error"].(map[string]any)
+		if !ok {
+			t.Fatalf("expected an error, got %v", got)
+		}
+		if code := rpcErr["code"].(float64); code != jsonrpc.INVALID_PARAMS {
+			t.Fatalf("unexpected error code: got %v, want %d", code, jsonrpc.INVALID_PARAMS)
+		}
+	})
+}
+
+func TestNotifyListChanged(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	toolsMap, toolsets, promptsMap, promptsets := setUpResources(t, []MockTool{tool1, tool2}, []MockPrompt{prompt1})
+
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "warn")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(fakeVersionString)
+	if err != nil {
+		t.Fatalf("unable to create custom metrics: %s", err)
+	}
+	server := &Server{
+		version:         fakeVersionString,
+		logger:          testLogger,
+		instrumentation: instrumentation,
+		sseManager:      newSseManager(ctx),
+		ResourceMgr:     resources.NewResourceManager(nil, nil, nil, toolsMap, toolsets, promptsMap, promptsets, nil),
+	}
+
+	// streamable HTTP sessions bound to different toolsets
+	httpSessions := map[string]*sseSession{}
+	for _, toolsetName := range []string{"tool1_only", "tool2_only"} {
+		session := &sseSession{
+			done:        make(chan struct{}),
+			eventQueue:  make(chan string, 10),
+			toolsetName: toolsetName,
+			protocol:    protocolVersion20250618,
+			mcpSession:  newMcpSession(server.taskPool),
+		}
+		server.sseManager.add(toolsetName, session)
+		httpSessions[toolsetName] = session
+	}
+
+	// stdio session using the default toolset
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	stdioSession := NewStdioSession(server, inR, outW)
+	go func() {
+		_ = stdioSession.Start(util.WithLogger(ctx, testLogger))
+	}()
+	// read stdout in the background so that writes to the pipe don't block
+	lines := make(chan string, 10)
+	go func() {
+		out := bufio.NewReader(outR)
+		for {
+			line, err := out.ReadString('\n')
+			if err != nil {
+				close(lines)
+				return
+			}
+			lines <- line
+		}
+	}()
+	readStdio := func() map[string]any {
+		line, ok := <-lines
+		if !ok {
+			t.Fatalf("unable to read message")
+		}
+		var got map[string]any
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatalf("unexpected error unmarshalling message: %s", err)
+		}
+		return got
+	}
+	b, err := json.Marshal(map[string]any{
+		"jsonrpc": jsonrpcVersion,
+		"id":      "mcp-initialize",
+		"method":  "initialize",
+		"params":  map[string]any{"protocolVersion": protocolVersion20250618},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error during marshaling of message")
+	}
+	if _, err := fmt.Fprintf(inW, "%s\n", b); err != nil {
+		t.Fatalf("unable to write message: %s", err)
+	}
+	if got := readStdio(); got["id"] != "mcp-initialize" {
+		t.Fatalf("unexpected initialize response: %v", got)
+	}
+
+	queuedMethods := func(session *sseSession) []string {
+		methods := []string{}
+		for {
+			select {
+			case event := <-session.eventQueue:
+				var got map[string]any
+				data := strings.TrimSuffix(strings.TrimPrefix(event, "event: message\ndata: "), "\n\n")
+				if err := json.Unmarshal([]byte(data), &got); err != nil {
+					t.Fatalf("unexpected error unmarshalling event %q: %s", event, err)
+				}
+				methods = append(methods, got["method"].(string))
+			default:
+				return methods
+			}
+		}
+	}
+	reload := func(mockTools []MockTool, mockPrompts []MockPrompt) {
+		previousToolsets, previousPromptsets := server.ResourceMgr.GetToolsetsMap(), server.ResourceMgr.GetPromptsetsMap()
+		toolsMap, toolsets, promptsMap, promptsets := setUpResources(t, mockTools, mockPrompts)
+		server.ResourceMgr.SetResources(nil, nil, nil, toolsMap, toolsets, promptsMap, promptsets, nil)
+		server.NotifyListChanged(ctx, previousToolsets, previousPromptsets)
+	}
+
+	// replacing tool2 only changes the default toolset and tool2_only
+	reload([]MockTool{tool1, tool3}, []MockPrompt{prompt1})
+	if got := queuedMethods(httpSessions["tool1_only"]); len(got) != 0 {
+		t.Fatalf("unexpected notifications for unchanged toolset: %v", got)
+	}
+	want := []string{"notifications/tools/list_changed"}
+	if got := queuedMethods(httpSessions["tool2_only"]); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected notifications: got %v, want %v", got, want)
+	}
+	if got := readStdio(); got["method"] != "notifications/tools/list_changed" {
+		t.Fatalf("unexpected stdio notification: %v", got)
+	}
+
+	// replacing the prompt notifies every session
+	reload([]MockTool{tool1, tool3}, []MockPrompt{prompt2})
+	want = []string{"notifications/prompts/list_changed"}
+	for toolsetName, session := range httpSessions {
+		if got := queuedMethods(session); !reflect.DeepEqual(got, want) {
+			t.Fatalf("unexpected notifications for %q: got %v, want %v", toolsetName, got, want)
+		}
+	}
+	if got := readStdio(); got["method"] != "notifications/prompts/list_changed" {
+		t.Fatalf("unexpected stdio notification: %v", got)
+	}
+}
+
+func TestMcpCompletion(t *testing.T) {
+	listTables := MockTool{
+		Name:   "list_tables",
+		Params: parameters.Parameters{parameters.NewStringParameter("schema", "The schema to list tables from.")},
+		results: func(params parameters.ParamValues) any {
+			schema := params.AsMap()["schema"]
+			return []any{
+				map[string]any{"table_name": fmt.Sprintf("%s_orders", schema)},
+				map[string]any{"table_name": fmt.Sprintf("%s_customers", schema)},
+			}
+		},
+	}
+	listWarehouses := MockTool{
+		Name:    "list_warehouses",
+		results: func(parameters.ParamValues) any { return []any{"main"} },
+	}
+	reportPrompt := MockPrompt{
+		Name: "report",
+		Args: prompts.Arguments{
+			{Parameter: parameters.NewStringParameterWithAllowedValues("region", "The region.", []any{"us-east", "us-west", "europe"})},
+			{Parameter: &parameters.StringParameter{CommonParameter: parameters.CommonParameter{
+				Name:       "table",
+				Type:       parameters.TypeString,
+				Desc:       "The table.",
+				Completion: &parameters.Completion{Tool: "list_tables"},
+			}}},
+			{Parameter: &parameters.StringParameter{CommonParameter: parameters.CommonParameter{
+				Name:       "owner",
+				Type:       parameters.TypeString,
+				Desc:       "The owner.",
+				Completion: &parameters.Completion{Tool: tool4.Name},
+			}}},
+			{Parameter: &parameters.StringParameter{CommonParameter: parameters.CommonParameter{
+				Name:       "warehouse",
+				Type:       parameters.TypeString,
+				Desc:       "The warehouse.",
+				Completion: &parameters.Completion{Tool: listWarehouses.Name},
+			}}},
+			{Parameter: parameters.NewStringParameter("notes", "Free-form notes.")},
+		},
+	}
+	tableSchema := MockResource{
+		Name:        "table_schema",
+		URITemplate: "mock://{schema}/{table}",
+		completions: map[string]*parameters.Completion{
+			"schema": {Values: []any{"public", "sales"}},
+		},
+	}
+	toolsMap, toolsets, promptsMap, promptsets := setUpResources(t, []MockTool{tool1, tool2, tool4, listTables, listWarehouses}, []MockPrompt{prompt1, reportPrompt})
+	defaultToolset := toolsets[""]
+	defaultToolset.ResourceNames = []string{tableSchema.Name}
+	// completion requests carry no claims, which the policy does not allow
+	defaultToolset.ToolAuthorizations = map[string][]*tools.AuthorizationPolicy{
+		listWarehouses.Name: {{Allow: []tools.AuthorizationRule{{AuthServices: []string{"my-google-auth"}}}}},
+	}
+	toolsets[""] = defaultToolset
+	// the prompt is not part of the promptset of the sessions
+	promptsMap["hidden"] = MockPrompt{Name: "hidden", Args: reportPrompt.Args}
+	resourceManager := resources.NewResourceManager(nil, nil, nil, toolsMap, toolsets, promptsMap, promptsets, map[string]mcpresources.Resource{
+		tableSchema.Name: tableSchema,
+	})
+	r, shutdown := setUpServerWithResourceManager(t, "mcp", resourceManager)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	promptRef := map[string]any{"type": "ref/prompt", "name": "report"}
+	testCases := []struct {
+		name      string
+		protocol  string
+		params    map[string]any
+		want      map[string]any
+		wantError int
+	}{
+		{
+			name:     "allowed values of a prompt argument",
+			protocol: protocolVersion20250618,
+			params:   map[string]any{"ref": promptRef, "argument": map[string]any{"name": "region", "value": "US"}},
+			want:     map[string]any{"values": []any{"us-east", "us-west"}, "total": float64(2)},
+		},
+		{
+			name:     "allowed values in version 2024-11-05",
+			protocol: protocolVersion20241105,
+			params:   map[string]any{"ref": promptRef, "argument": map[string]any{"name": "region", "value": "eu"}},
+			want:     map[string]any{"values": []any{"europe"}, "total": float64(1)},
+		},
+		{
+			name:     "completion tool receives the context arguments",
+			protocol: protocolVersion20250618,
+			params: map[string]any{
+				"ref":      promptRef,
+				"argument": map[string]any{"name": "table", "value": ""},
+				"context":  map[string]any{"arguments": map[string]any{"schema": "sales", "region": "europe"}},
+			},
+			want: map[string]any{"values": []any{"sales_orders", "sales_customers"}, "total": float64(2)},
+		},
+		{
+			name:     "argument without completion",
+			protocol: protocolVersion20250618,
+			params:   map[string]any{"ref": promptRef, "argument": map[string]any{"name": "notes", "value": "a"}},
+			want:     map[string]any{"values": []any{}},
+		},
+		{
+			name:     "resource template variable",
+			protocol: protocolVersion20251125,
+			params: map[string]any{
+				"ref":      map[string]any{"type": "ref/resource", "uri": "mock://{schema}/{table}"},
+				"argument": map[string]any{"name": "schema", "value": "s"},
+			},
+			want: map[string]any{"values": []any{"sales"}, "total": float64(1)},
+		},
+		{
+			name:      "unknown prompt",
+			protocol:  protocolVersion20250618,
+			params:    map[string]any{"ref": map[string]any{"type": "ref/prompt", "name": "missing"}, "argument": map[string]any{"name": "region", "value": ""}},
+			wantError: jsonrpc.INVALID_PARAMS,
+		},
+		{
+			name:      "prompt outside the promptset",
+			protocol:  protocolVersion20250618,
+			params:    map[string]any{"ref": map[string]any{"type": "ref/prompt", "name": "hidden"}, "argument": map[string]any{"name": "region", "value": ""}},
+			wantError: jsonrpc.INVALID_PARAMS,
+		},
+		{
+			name:      "unknown argument",
+			protocol:  protocolVersion20250618,
+			params:    map[string]any{"ref": promptRef, "argument": map[string]any{"name": "missing", "value": ""}},
+			wantError: jsonrpc.INVALID_PARAMS,
+		},
+		{
+			name:      "completion tool requires authorization",
+			protocol:  protocolVersion20250618,
+			params:    map[string]any{"ref": promptRef, "argument": map[string]any{"name": "owner", "value": ""}},
+			wantError: jsonrpc.INTERNAL_ERROR,
+		},
+		{
+			name:      "completion tool denied by the authorization policy of the toolset",
+			protocol:  protocolVersion20250618,
+			params:    map[string]any{"ref": promptRef, "argument": map[string]any{"name": "warehouse", "value": ""}},
+			wantError: jsonrpc.INTERNAL_ERROR,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reqMarshal, err := json.Marshal(map[string]any{
+				"jsonrpc": jsonrpcVersion,
+				"id":      "completion",
+				"method":  "completion/complete",
+				"params":  tc.params,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error during marshaling of body")
+			}
+			header := map[string]string{"MCP-Protocol-Version": tc.protocol}
+			_, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal), header)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			var got map[string]any
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unexpected error unmarshalling body: %s", err)
+			}
+			if tc.wantError != 0 {
+				rpcErr, ok := got["error"].(map[string]any)
+				if !ok {
+					t.Fatalf("expected an error, got %v", got)
+				}
+				if code := rpcErr["code"].(float64); int(code) != tc.wantError {
+					t.Fatalf("unexpected error code: got %v, want %d", code, tc.wantError)
+				}
+				return
+			}
+			result, ok := got["result"].(map[string]any)
+			if !ok {
+				t.Fatalf("unexpected response: %v", got)
+			}
+			if !reflect.DeepEqual(result["completion"], tc.want) {
+				t.Fatalf("unexpected completion: got %v, want %v", result["completion"], tc.want)
+			}
+		})
+	}
+}
+
+// startStdioTestSession starts a stdio session on a server with the given
+// tools. It returns functions to write a message to the session and to read
+// the next message written by the server.
+func startStdioTestSession(t *testing.T, ctx context.Context, mockTools []MockTool) (func(map[string]any), func() map[string]any) {
+	toolsMap, toolsets, promptsMap, promptsets := setUpResources(t, mockTools, []MockPrompt{prompt1})
+
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "warn")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(fakeVersionString)
+	if err != nil {
+		t.Fatalf("unable to create custom metrics: %s", err)
+	}
+	server := &Server{
+		version:         fakeVersionString,
+		logger:          testLogger,
+		instrumentation: instrumentation,
+		sseManager:      newSseManager(ctx),
+		ResourceMgr:     resources.NewResourceManager(nil, nil, nil, toolsMap, toolsets, promptsMap, promptsets, nil),
+		taskPool:        mcputil.NewTaskPool(2, 10),
+	}
+
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	stdioSession := NewStdioSession(server, inR, outW)
+	go func() {
+		_ = stdioSession.Start(util.WithLogger(ctx, testLogger))
+	}()
+	// read stdout in the background so that writes to the pipe don't block
+	lines := make(chan string, 10)
+	go func() {
+		out := bufio.NewReader(outR)
+		for {
+			line, err := out.ReadString('\n')
+			if err != nil {
+				close(lines)
+				return
+			}
+			lines <- line
+		}
+	}()
+	write := func(message map[string]any) {
+		b, err := json.Marshal(message)
+		if err != nil {
+			t.Fatalf("unexpected error during marshaling of message")
+		}
+		if _, err := fmt.Fprintf(inW, "%s\n", b); err != nil {
+			t.Fatalf("unable to write message: %s", err)
+		}
+	}
+	read := func() map[string]any {
+		line, ok := <-lines
+		if !ok {
+			t.Fatalf("unable to read message")
+		}
+		var got map[string]any
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatalf("unexpected error unmarshalling message: %s", err)
+		}
+		return got
+	}
+	return write, read
+}
+
+func TestMcpLogging(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	write, read := startStdioTestSession(t, ctx, []MockTool{tool1, tool2})
+	request := func(id string, method string, params map[string]any) {
+		write(map[string]any{
+			"jsonrpc": jsonrpcVersion,
+			"id":      id,
+			"method":  method,
+			"params":  params,
+		})
+	}
+	// readResponse returns the data of the log messages received before the
+	// response to the request
+	readResponse := func(id string) (map[string]any, []any) {
+		logs := []any{}
+		for {
+			got := read()
+			if got["method"] == "notifications/message" {
+				params := got["params"].(map[string]any)
+				if params["logger"] != "toolbox" {
+					t.Fatalf("unexpected logger: %v", params["logger"])
+				}
+				logs = append(logs, params["data"])
+				continue
+			}
+			if got["id"] != id {
+				t.Fatalf("expected response to %q, got %v", id, got)
+			}
+			return got, logs
+		}
+	}
+
+	request("mcp-initialize", "initialize", map[string]any{"protocolVersion": protocolVersion20250618})
+	readResponse("mcp-initialize")
+
+	// no log messages are sent before the client sets a level
+	request("tools-list-1", "tools/list", map[string]any{})
+	if _, logs := readResponse("tools-list-1"); len(logs) != 0 {
+		t.Fatalf("unexpected log messages: %v", logs)
+	}
+
+	request("set-level-invalid", "logging/setLevel", map[string]any{"level": "verbose"})
+	got, _ := readResponse("set-level-invalid")
+	rpcErr, ok := got["error"].(map[string]any)
+	if !ok || rpcErr["code"] != float64(jsonrpc.INVALID_PARAMS) {
+		t.Fatalf("expected invalid params error, got %v", got)
+	}
+
+	request("set-level-debug", "logging/setLevel", map[string]any{"level": "debug"})
+	want := map[string]any{"jsonrpc": "2.0", "id": "set-level-debug", "result": map[string]any{}}
+	if got, _ := readResponse("set-level-debug"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected setLevel response: got %v, want %v", got, want)
+	}
+
+	// records logged while handling the request precede its response
+	request("tools-list-2", "tools/list", map[string]any{})
+	_, logs := readResponse("tools-list-2")
+	if !slices.Contains(logs, any("method is: tools/list")) {
+		t.Fatalf("expected debug log message, got %v", logs)
+	}
+
+	// debug records are not sent once the level is raised
+	request("set-level-error", "logging/setLevel", map[string]any{"level": "error"})
+	readResponse("set-level-error")
+	request("tools-list-3", "tools/list", map[string]any{})
+	if _, logs := readResponse("tools-list-3"); len(logs) != 0 {
+		t.Fatalf("unexpected log messages: %v", logs)
+	}
+}
+
+func TestMcpConfirmation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	elicitation := map[string]any{"elicitation": map[string]any{}}
+	testCases := []struct {
+		name         string
+		protocol     string
+		capabilities map[string]any
+		// action is the response of the user to the elicitation request, if
+		// any is expected
+		action  string
+		isError bool
+		want    string
+	}{
+		{
+			name:         "accepted",
+			protocol:     protocolVersion20250618,
+			capabilities: elicitation,
+			action:       "accept",
+			want:         `"confirmed_tool"`,
+		},
+		{
+			name:         "declined",
+			protocol:     protocolVersion20251125,
+			capabilities: elicitation,
+			action:       "decline",
+			isError:      true,
+			want:         `the user did not confirm the invocation of tool "confirmed_tool" (action: decline)`,
+		},
+		{
+			name:         "cancelled",
+			protocol:     protocolVersion20250618,
+			capabilities: elicitation,
+			action:       "cancel",
+			isError:      true,
+			want:         `the user did not confirm the invocation of tool "confirmed_tool" (action: cancel)`,
+		},
+		{
+			name:         "client without elicitation",
+			protocol:     protocolVersion20250618,
+			capabilities: map[string]any{},
+			isError:      true,
+			want:         `tool "confirmed_tool" requires confirmation, but the client does not support elicitation`,
+		},
+		{
+			name:         "protocol without elicitation",
+			protocol:     protocolVersion20250326,
+			capabilities: elicitation,
+			isError:      true,
+			want:         `tool "confirmed_tool" requires confirmation, but the client does not support elicitation`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			write, read := startStdioTestSession(t, ctx, []MockTool{tool1, tool6})
+			write(map[string]any{
+				"jsonrpc": jsonrpcVersion,
+				"id":      "mcp-initialize",
+				"method":  "initialize",
+				"params":  map[string]any{"protocolVersion": tc.protocol, "capabilities": tc.capabilities},
+			})
+			if got := read(); got["id"] != "mcp-initialize" {
+				t.Fatalf("unexpected initialize response: %v", got)
+			}
+
+			write(map[string]any{
+				"jsonrpc": jsonrpcVersion,
+				"id":      "tools-call",
+				"method":  "tools/call",
+				"params": map[string]any{
+					"name":      tool6.Name,
+					"arguments": map[string]any{"name": "orders"},
+				},
+			})
+			got := read()
+			if tc.action != "" {
+				if got["method"] != "elicitation/create" {
+					t.Fatalf("expected elicitation request, got %v", got)
+				}
+				params := got["params"].(map[string]any)
+				wantMessage := "Allow the tool \"confirmed_tool\" to run with the following parameters?\n- name: \"orders\""
+				if params["message"] != wantMessage {
+					t.Fatalf("unexpected elicitation message: got %q, want %q", params["message"], wantMessage)
+				}
+				write(map[string]any{
+					"jsonrpc": jsonrpcVersion,
+					"id":      got["id"],
+					"result":  map[string]any{"action": tc.action},
+				})
+				got = read()
+			}
+
+			if got["id"] != "tools-call" {
+				t.Fatalf("expected tools/call response, got %v", got)
+			}
+			result, ok := got["result"].(map[string]any)
+			if !ok {
+				t.Fatalf("expected tools/call result, got %v", got)
+			}
+			isError, _ := result["isError"].(bool)
+			if isError != tc.isError {
+				t.Fatalf("unexpected isError: got %v, want %v", isError, tc.isError)
+			}
+			content := result["content"].([]any)
+			if text := content[0].(map[string]any)["text"]; text != tc.want {
+				t.Fatalf("unexpected result: got %q, want %q", text, tc.want)
+			}
+		})
+	}
+}
+
+func TestMcpConfirmationBeforeEmbedding(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the embedding model is not configured, so embedding fails if it runs
+	embeddedTool := MockTool{
+		Name: "confirmed_search",
+		Params: parameters.Parameters{&parameters.StringParameter{CommonParameter: parameters.CommonParameter{
+			Name:       "query",
+			Type:       parameters.TypeString,
+			Desc:       "The search query.",
+			EmbeddedBy: "missing-model",
+		}}},
+		requireConfirmation: true,
+	}
+	write, read := startStdioTestSession(t, ctx, []MockTool{tool1, embeddedTool})
+	write(map[string]any{
+		"jsonrpc": jsonrpcVersion,
+		"id":      "mcp-initialize",
+		"method":  "initialize",
+		"params":  map[string]any{"protocolVersion": protocolVersion20250618, "capabilities": map[string]any{"elicitation": map[string]any{}}},
+	})
+	if got := read(); got["id"] != "mcp-initialize" {
+		t.Fatalf("unexpected initialize response: %v", got)
+	}
+
+	write(map[string]any{
+		"jsonrpc": jsonrpcVersion,
+		"id":      "tools-call",
+		"method":  "tools/call",
+		"params": map[string]any{
+			"name":      embeddedTool.Name,
+			"arguments": map[string]any{"query": "late orders"},
+		},
+	})
+	got := read()
+	if got["method"] != "elicitation/create" {
+		t.Fatalf("expected elicitation request, got %v", got)
+	}
+	// the user confirms the parameters as entered, not their embeddings
+	wantMessage := "Allow the tool \"confirmed_search\" to run with the following parameters?\n- query: \"late orders\""
+	if message := got["params"].(map[string]any)["message"]; message != wantMessage {
+		t.Fatalf("unexpected elicitation message: got %q, want %q", message, wantMessage)
+	}
+	write(map[string]any{
+		"jsonrpc": jsonrpcVersion,
+		"id":      got["id"],
+		"result":  map[string]any{"action": "decline"},
+	})
+	got = read()
+	result, ok := got["result"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected tools/call result, got %v", got)
+	}
+	want := `the user did not confirm the invocation of tool "confirmed_search" (action: decline)`
+	if text := result["content"].([]any)[0].(map[string]any)["text"]; text != want {
+		t.Fatalf("unexpected result: got %q, want %q", text, want)
+	}
+}
+
+func TestStreamableHTTPConfirmation(t *testing.T) {
+	toolsMap, toolsets, promptsMap, promptsets := setUpResources(t, []MockTool{tool1, tool6}, []MockPrompt{prompt1})
+	r, shutdown := setUpServer(t, "mcp", toolsMap, toolsets, promptsMap, promptsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	post := func(message map[string]any, header map[string]string) *http.Response {
+		reqMarshal, err := json.Marshal(message)
+		if err != nil {
+			t.Fatalf("unexpected error during marshaling of body")
+		}
+		req, err := http.NewRequest(http.MethodPost, ts.URL+"/", bytes.NewBuffer(reqMarshal))
+		if err != nil {
+			t.Fatalf("unable to create request: %s", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("unable to send request: %s", err)
+		}
+		return resp
+	}
+
+	initResp := post(map[string]any{
+		"jsonrpc": jsonrpcVersion,
+		"id":      "mcp-initialize",
+		"method":  "initialize",
+		"params": map[string]any{
+			"protocolVersion": protocolVersion20250618,
+			"capabilities":    map[string]any{"elicitation": map[string]any{}},
+		},
+	}, nil)
+	initResp.Body.Close()
+	sessionId := initResp.Header.Get("Mcp-Session-Id")
+	if sessionId == "" {
+		t.Fatalf("Mcp-Session-Id header is expected")
+	}
+
+	// the elicitation request is sent on the stream of the tools/call response
+	resp := post(map[string]any{
+		"jsonrpc": jsonrpcVersion,
+		"id":      "tools-call",
+		"method":  "tools/call",
+		"params": map[string]any{
+			"name":      tool6.Name,
+			"arguments": map[string]any{"name": "orders"},
+		},
+	}, map[string]string{"Mcp-Session-Id": sessionId, "Accept": "application/json, text/event-stream"})
+	defer resp.Body.Close()
+	reader := bufio.NewReader(resp.Body)
+	got, err := readSseEvent(reader)
+	if err != nil {
+		t.Fatalf("unable to read elicitation event: %s", err)
+	}
+	if got["method"] != "elicitation/create" {
+		t.Fatalf("expected elicitation request, got %v", got)
+	}
+
+	// the user's answer is posted to the session as a separate request
+	answerResp := post(map[string]any{
+		"jsonrpc": jsonrpcVersion,
+		"id":      got["id"],
+		"result":  map[string]any{"action": "accept"},
+	}, map[string]string{"Mcp-Session-Id": sessionId})
+	answerResp.Body.Close()
+	if answerResp.StatusCode != http.StatusAccepted {
+		t.Fatalf("unexpected status: got %d, want %d", answerResp.StatusCode, http.StatusAccepted)
+	}
+
+	got, err = readSseEvent(reader)
+	if err != nil {
+		t.Fatalf("unable to read response event: %s", err)
+	}
+	want := map[string]any{
+		"jsonrpc": jsonrpcVersion,
+		"id":      "tools-call",
+		"result": map[string]any{
+			"content": []any{map[string]any{"type": "text", "text": `"confirmed_tool"`}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected response: got %v, want %v", got, want)
+	}
+}
+
+func TestMcpTasks(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	started := make(chan struct{}, 1)
+	blockingTool := MockTool{
+		Name:    "blocking_tool",
+		Params:  []parameters.Parameter{},
+		started: started,
+	}
+	write, read := startStdioTestSession(t, ctx, []MockTool{tool1, blockingTool})
+	// request returns the response to the request, status notifications
+	// received before the response are collected
+	notifications := []any{}
+	request := func(id string, method string, params map[string]any) map[string]any {
+		write(map[string]any{
+			"jsonrpc": jsonrpcVersion,
+			"id":      id,
+			"method":  method,
+			"params":  params,
+		})
+		for {
+			got := read()
+			if got["method"] == mcputil.NOTIFICATIONS_TASKS_STATUS {
+				notifications = append(notifications, got["params"])
+				continue
+			}
+			if got["id"] != id {
+				t.Fatalf("unexpected message: %v", got)
+			}
+			return got
+		}
+	}
+	result := func(got map[string]any) map[string]any {
+		res, ok := got["result"].(map[string]any)
+		if !ok {
+			t.Fatalf("unexpected response: %v", got)
+		}
+		return res
+	}
+	errorCode := func(got map[string]any) float64 {
+		e, ok := got["error"].(map[string]any)
+		if !ok {
+			t.Fatalf("expected an error response: %v", got)
+		}
+		return e["code"].(float64)
+	}
+
+	initResult := result(request("init", "initialize", map[string]any{"protocolVersion": protocolVersion20251125}))
+	capabilities := initResult["capabilities"].(map[string]any)
+	if capabilities["tasks"] == nil {
+		t.Fatalf("expected tasks capability, got %v", capabilities)
+	}
+	write(map[string]any{"jsonrpc": jsonrpcVersion, "method": "notifications/initialized"})
+
+	listResult := result(request("list", "tools/list", map[string]any{}))
+	for _, tool := range listResult["tools"].([]any) {
+		execution := tool.(map[string]any)["execution"]
+		if !reflect.DeepEqual(execution, map[string]any{"taskSupport": "optional"}) {
+			t.Fatalf("unexpected execution of tool: %v", tool)
+		}
+	}
+
+	// a completed task
+	created := result(request("call", "tools/call", map[string]any{
+		"name": "no_params",
+		"task": map[string]any{"ttl": 60000},
+	}))
+	task := created["task"].(map[string]any)
+	taskId := task["taskId"].(string)
+	if task["status"] != "working" || task["ttl"] != float64(60000) || task["pollInterval"] != float64(1000) {
+		t.Fatalf("unexpected task: %v", task)
+	}
+	got := result(request("result", mcputil.TASKS_RESULT, map[string]any{"taskId": taskId}))
+	want := map[string]any{
+		"_meta": map[string]any{
+			mcputil.RELATED_TASK_META_KEY: map[string]any{"taskId": taskId},
+		},
+		"content": []any{map[string]any{"type": "text", "text": `"no_params"`}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected task result: got %v, want %v", got, want)
+	}
+	got = result(request("get", mcputil.TASKS_GET, map[string]any{"taskId": taskId}))
+	if got["taskId"] != taskId || got["status"] != "completed" {
+		t.Fatalf("unexpected task: %v", got)
+	}
+
+	// a cancelled task
+	created = result(request("call-blocking", "tools/call", map[string]any{
+		"name": "blocking_tool",
+		"task": map[string]any{},
+	}))
+	blockingId := created["task"].(map[string]any)["taskId"].(string)
+	<-started
+	got = result(request("cancel", mcputil.TASKS_CANCEL, map[string]any{"taskId": blockingId}))
+	if got["status"] != "cancelled" {
+		t.Fatalf("unexpected cancelled task: %v", got)
+	}
+	if code := errorCode(request("result-cancelled", mcputil.TASKS_RESULT, map[string]any{"taskId": blockingId})); code != jsonrpc.INVALID_PARAMS {
+		t.Fatalf("unexpected error code: got %v, want %v", code, jsonrpc.INVALID_PARAMS)
+	}
+	if code := errorCode(request("cancel-again", mcputil.TASKS_CANCEL, map[string]any{"taskId": blockingId})); code != jsonrpc.INVALID_PARAMS {
+		t.Fatalf("unexpected error code: got %v, want %v", code, jsonrpc.INVALID_PARAMS)
+	}
+
+	listResult = result(request("list-tasks", mcputil.TASKS_LIST, map[string]any{}))
+	statuses := map[string]any{}
+	for _, task := range listResult["tasks"].([]any) {
+		statuses[task.(map[string]any)["taskId"].(string)] = task.(map[string]any)["status"]
+	}
+	wantStatuses := map[string]any{taskId: "completed", blockingId: "cancelled"}
+	if !reflect.DeepEqual(statuses, wantStatuses) {
+		t.Fatalf("unexpected tasks: got %v, want %v", statuses, wantStatuses)
+	}
+
+	if code := errorCode(request("get-unknown", mcputil.TASKS_GET, map[string]any{"taskId": "unknown"})); code != jsonrpc.INVALID_PARAMS {
+		t.Fatalf("unexpected error code: got %v, want %v", code, jsonrpc.INVALID_PARAMS)
+	}
+	if len(notifications) != 1 || notifications[0].(map[string]any)["status"] != "completed" {
+		t.Fatalf("unexpected status notifications: %v", notifications)
+	}
+}
+
+func TestMcpServerMetadata(t *testing.T) {
+	toolsMap, toolsets, promptsMap, promptsets := setUpResources(t, []MockTool{tool1, tool2}, []MockPrompt{prompt1})
+	icons := []tools.Icon{{Src: "https://example.com/icon.png", MimeType: "image/png", Sizes: []string{"48x48"}}}
+	for name, toolset := range toolsets {
+		toolset.ServerMetadata = tools.ServerMetadata{
+			DisplayMetadata: tools.DisplayMetadata{Title: "Toolbox", Icons: icons},
+			Instructions:    "Use the tools.",
+		}
+		if name == "tool1_only" {
+			toolset.ServerMetadata.Instructions = "Use tool1."
+		}
+		for i := range toolset.McpManifest {
+			toolset.McpManifest[i].Title, toolset.McpManifest[i].Icons = "Tool", icons
+		}
+		toolsets[name] = toolset
+	}
+	for i := range promptsets[""].McpManifest {
+		promptsets[""].McpManifest[i].Title, promptsets[""].McpManifest[i].Icons = "Prompt", icons
+	}
+	r, shutdown := setUpServer(t, "mcp", toolsMap, toolsets, promptsMap, promptsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	run := func(path, protocol, method string, params map[string]any) map[string]any {
+		reqMarshal, err := json.Marshal(map[string]any{
+			"jsonrpc": jsonrpcVersion,
+			"id":      method,
+			"method":  method,
+			"params":  params,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error during marshaling of body")
+		}
+		header := map[string]string{"MCP-Protocol-Version": protocol}
+		_, body, err := runRequest(ts, http.MethodPost, path, bytes.NewBuffer(reqMarshal), header)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		var got map[string]any
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Fatalf("unexpected error unmarshalling body: %s", err)
+		}
+		result, ok := got["result"].(map[string]any)
+		if !ok {
+			t.Fatalf("unexpected response: %v", got)
+		}
+		return result
+	}
+
+	wantIcons := []any{map[string]any{"src": "https://example.com/icon.png", "mimeType": "image/png", "sizes": []any{"48x48"}}}
+	tcs := []struct {
+		protocol  string
+		wantTitle bool
+		wantIcons bool
+	}{
+		{protocol: protocolVersion20241105},
+		{protocol: protocolVersion20250326},
+		{protocol: protocolVersion20250618, wantTitle: true},
+		{protocol: protocolVersion20251125, wantTitle: true, wantIcons: true},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.protocol, func(t *testing.T) {
+			check := func(desc string, m map[string]any, wantTitle string) {
+				t.Helper()
+				if got, ok := m["title"]; ok != tc.wantTitle || (ok && got != wantTitle) {
+					t.Errorf("unexpected title of %s: got %v", desc, got)
+				}
+				if got, ok := m["icons"]; ok != tc.wantIcons || (ok && !reflect.DeepEqual(got, wantIcons)) {
+					t.Errorf("unexpected icons of %s: got %v", desc, got)
+				}
+			}
+
+			result := run("/", tc.protocol, "initialize", map[string]any{"protocolVersion": tc.protocol})
+			if got := result["instructions"]; got != "Use the tools." {
+				t.Errorf("unexpected instructions: got %v", got)
+			}
+			check("server", result["serverInfo"].(map[string]any), "Toolbox")
+
+			result = run("/tool1_only", tc.protocol, "initialize", map[string]any{"protocolVersion": tc.protocol})
+			if got := result["instructions"]; got != "Use tool1." {
+				t.Errorf("unexpected instructions of toolset: got %v", got)
+			}
+
+			for _, m := range run("/", tc.protocol, "tools/list", map[string]any{})["tools"].([]any) {
+				check("tool", m.(map[string]any), "Tool")
+			}
+			for _, m := range run("/", tc.protocol, "prompts/list", map[string]any{})["prompts"].([]any) {
+				check("prompt", m.(map[string]any), "Prompt")
+			}
+		})
+	}
+}
+
+func TestMcpAuthorizationPolicy(t *testing.T) {
+	restricted := tool7
+	restricted.policy = &tools.AuthorizationPolicy{
+		Allow: tool7.policy.Allow,
+		Deny:  []tools.AuthorizationRule{{Claims: map[string]tools.ClaimMatcher{"email": {Equals: "intern@example.com"}}}},
+	}
+	toolsMap, toolsets, promptsMap, promptsets := setUpResources(t, []MockTool{tool1, restricted}, []MockPrompt{prompt1})
+	authServices := map[string]auth.AuthService{"my-auth": MockAuthService{Name: "my-auth"}}
+	resourceManager := resources.NewResourceManager(nil, authServices, nil, toolsMap, toolsets, promptsMap, promptsets, nil)
+	r, shutdown := setUpServerWithResourceManager(t, "mcp", resourceManager)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	run := func(t *testing.T, email string, body jsonrpc.JSONRPCRequest) (*http.Response, map[string]any) {
+		header := map[string]string{"MCP-Protocol-Version": protocolVersion20250618}
+		if email != "" {
+			header["my-auth_token"] = email
+		}
+		reqMarshal, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("unexpected error during marshaling of body")
+		}
+		resp, respBody, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal), header)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		var got map[string]any
+		if err := json.Unmarshal(respBody, &got); err != nil {
+			t.Fatalf("unexpected error unmarshalling body: %s", err)
+		}
+		return resp, got
+	}
+
+	listTests := []struct {
+		name      string
+		email     string
+		wantNames []string
+	}{
+		{name: "anonymous", wantNames: []string{tool1.Name}},
+		{name: "allowed", email: "alice@example.com", wantNames: []string{tool1.Name, restricted.Name}},
+		{name: "denied", email: "intern@example.com", wantNames: []string{tool1.Name}},
+		{name: "other domain", email: "mallory@other.com", wantNames: []string{tool1.Name}},
+	}
+	for _, tc := range listTests {
+		t.Run("tools/list "+tc.name, func(t *testing.T) {
+			_, got := run(t, tc.email, jsonrpc.JSONRPCRequest{
+				Jsonrpc: jsonrpcVersion,
+				Id:      "tools-list",
+				Request: jsonrpc.Request{Method: "tools/list"},
+			})
+			result, ok := got["result"].(map[string]any)
+			if !ok {
+				t.Fatalf("unexpected response: %v", got)
+			}
+			var gotNames []string
+			for _, m := range result["tools"].([]any) {
+				gotNames = append(gotNames, m.(map[string]any)["name"].(string))
+			}
+			if !reflect.DeepEqual(gotNames, tc.wantNames) {
+				t.Fatalf("unexpected tools: got %v, want %v", gotNames, tc.wantNames)
+			}
+		})
+	}
+
+	callTests := []struct {
+		name       string
+		email      string
+		wantStatus int
+	}{
+		{name: "allowed", email: "alice@example.com", wantStatus: http.StatusOK},
+		{name: "anonymous", wantStatus: http.StatusForbidden},
+		{name: "denied", email: "intern@example.com", wantStatus: http.StatusForbidden},
+	}
+	for _, tc := range callTests {
+		t.Run("tools/call "+tc.name, func(t *testing.T) {
+			resp, got := run(t, tc.email, jsonrpc.JSONRPCRequest{
+				Jsonrpc: jsonrpcVersion,
+				Id:      "tools-call",
+				Request: jsonrpc.Request{Method: "tools/call"},
+				Params:  map[string]any{"name": restricted.Name},
+			})
+			if resp.StatusCode != tc.wantStatus {
+				t.Fatalf("unexpected status code: got %d, want %d: %v", resp.StatusCode, tc.wantStatus, got)
+			}
+			if tc.wantStatus == http.StatusOK {
+				if _, ok := got["result"]; !ok {
+					t.Fatalf("unexpected response: %v", got)
+				}
+				return
+			}
+			errObj, ok := got["error"].(map[string]any)
+			if !ok {
+				t.Fatalf("expected error response, got %v", got)
+			}
+			if code := errObj["code"].(float64); code != jsonrpc.INVALID_REQUEST {
+				t.Fatalf("unexpected error code: got %v, want %d", code, jsonrpc.INVALID_REQUEST)
+			}
+		})
+	}
+}
+
+func TestMcpInvocationPolicy(t *testing.T) {
+	toolsMap, toolsets, promptsMap, promptsets := setUpResources(t, []MockTool{tool1, tool2}, []MockPrompt{prompt1})
+	policy, err := tools.InvocationPolicyConfig{
+		Name:      "param1-limit",
+		Tools:     []string{tool2.Name},
+		Condition: `params.param1 <= 10 || claims.exists(s, claims[s].email.endsWith("@example.com"))`,
+		Message:   "param1 must be at most 10",
+	}.Initialize(toolsMap)
+	if err != nil {
+		t.Fatalf("unable to initialize policy: %s", err)
+	}
+	for name, ts := range toolsets {
+		ts.Policies = []*tools.InvocationPolicy{policy}
+		toolsets[name] = ts
+	}
+	authServices := map[string]auth.AuthService{"my-auth": MockAuthService{Name: "my-auth"}}
+	resourceManager := resources.NewResourceManager(nil, authServices, nil, toolsMap, toolsets, promptsMap, promptsets, nil)
+	r, shutdown := setUpServerWithResourceManager(t, "mcp", resourceManager)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	tcs := []struct {
+		name      string
+		email     string
+		param1    int
+		wantError string
+	}{
+		{name: "allowed by parameter", param1: 5},
+		{name: "allowed by claim", email: "alice@example.com", param1: 50},
+		{name: "denied", email: "mallory@other.com", param1: 50, wantError: `tool invocation denied by policy "param1-limit": param1 must be at most 10`},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			header := map[string]string{"MCP-Protocol-Version": protocolVersion20250618}
+			if tc.email != "" {
+				header["my-auth_token"] = tc.email
+			}
+			reqMarshal, err := json.Marshal(jsonrpc.JSONRPCRequest{
+				Jsonrpc: jsonrpcVersion,
+				Id:      "tools-call",
+				Request: jsonrpc.Request{Method: "tools/call"},
+				Params:  map[string]any{"name": tool2.Name, "arguments": map[string]any{"param1": tc.param1, "param2": 1}},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error during marshaling of body")
+			}
+			resp, respBody, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal), header)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("unexpected status code: got %d: %s", resp.StatusCode, respBody)
+			}
+			var got struct {
+				Result struct {
+					Content []struct {
+						Text string `json:"text"`
+					} `json:"content"`
+					IsError bool `json:"isError"`
+				} `json:"result"`
+			}
+			if err := json.Unmarshal(respBody, &got); err != nil {
+				t.Fatalf("unexpected error unmarshalling body: %s", err)
+			}
+			if got.Result.IsError != (tc.wantError != "") {
+				t.Fatalf("unexpected result: %s", respBody)
+			}
+			if tc.wantError != "" && got.Result.Content[0].Text != tc.wantError {
+				t.Fatalf("unexpected error: got %q, want %q", got.Result.Content[0].Text, tc.wantError)
+			}
+		})
+	}
+}
@@ -17,14 +17,43 @@ package server
 import (
 	"context"
 	"fmt"
+	"net/http"
 
+	"github.com/googleapis/genai-toolbox/internal/auth"
 	"github.com/googleapis/genai-toolbox/internal/embeddingmodels"
+	"github.com/googleapis/genai-toolbox/internal/mcpresources"
 	"github.com/googleapis/genai-toolbox/internal/prompts"
+	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/util"
 	"github.com/googleapis/genai-toolbox/internal/util/parameters"
 )
 
+// MockAuthService is used to mock auth services in tests. It trusts the
+// `<name>_token` header as the email of the principal.
+type MockAuthService struct {
+	Name string
+}
+
+func (a MockAuthService) AuthServiceType() string {
+	return "mock"
+}
+
+func (a MockAuthService) GetName() string {
+	return a.Name
+}
+
+func (a MockAuthService) GetClaimsFromHeader(_ context.Context, h http.Header) (map[string]any, error) {
+	if email := h.Get(a.Name + "_token"); email != "" {
+		return map[string]any{"email": email}, nil
+	}
+	return nil, nil
+}
+
+func (a MockAuthService) ToConfig() auth.AuthServiceConfig {
+	return nil
+}
+
 // MockTool is used to mock tools in tests
 type MockTool struct {
 	Name                         string
@@ -33,15 +62,57 @@ type MockTool struct {
 	manifest                     tools.Manifest
 	unauthorized                 bool
 	requiresClientAuthrorization bool
+	// notifications are sent to the client before the tool returns
+	notifications []any
+	// progress is reported to the client before the tool returns
+	progress []float64
+	// started, if set, is signalled when the invocation starts. The
+	// invocation then blocks until it is cancelled.
+	started chan struct{}
+	// outputSchema is the output schema of the tool, if any
+	outputSchema *parameters.McpToolsSchema
+	// results, if set, returns the result of the invocation
+	results func(parameters.ParamValues) any
+	// requireConfirmation is set if invocations must be confirmed by the user
+	requireConfirmation bool
+	// policy is the authorization policy of the tool, if any
+	policy *tools.AuthorizationPolicy
 }
 
-func (t MockTool) Invoke(context.Context, tools.SourceProvider, parameters.ParamValues, tools.AccessToken) (any, util.ToolboxError) {
+func (t MockTool) Invoke(ctx context.Context, _ tools.SourceProvider, params parameters.ParamValues, _ tools.AccessToken) (any, util.ToolboxError) {
+	if len(t.notifications) > 0 {
+		send, err := util.MessageSenderFromContext(ctx)
+		if err != nil {
+			return nil, util.NewClientServerError(err.Error(), http.StatusInternalServerError, err)
+		}
+		for _, n := range t.notifications {
+			if err := send(ctx, n); err != nil {
+				return nil, util.NewClientServerError(err.Error(), http.StatusInternalServerError, err)
+			}
+		}
+	}
+	for _, p := range t.progress {
+		if err := util.ReportProgress(ctx, p, t.progress[len(t.progress)-1], fmt.Sprintf("step %v", p)); err != nil {
+			return nil, util.NewClientServerError(err.Error(), http.StatusInternalServerError, err)
+		}
+	}
+	if t.started != nil {
+		t.started <- struct{}{}
+		<-ctx.Done()
+		return nil, util.NewClientServerError("invocation cancelled", http.StatusInternalServerError, ctx.Err())
+	}
+	if t.results != nil {
+		return t.results(params), nil
+	}
 	mock := []any{t.Name}
 	return mock, nil
 }
 
 func (t MockTool) ToConfig() tools.ToolConfig {
-	return nil
+	return MockToolConfig{
+		Tool:         t,
+		CommonConfig: tools.CommonConfig{RequireConfirmation: t.requireConfirmation, Authorization: t.policy},
+	}
 }
 
 // claims is a map of user info decoded from an auth token
@@ -98,9 +169,10 @@ func (t MockTool) McpManifest() tools.McpManifest {
 	}
 
 	mcpManifest := tools.McpManifest{
-		Name:        t.Name,
-		Description: t.Description,
-		InputSchema: toolsSchema,
+		Name:         t.Name,
+		Description:  t.Description,
+		InputSchema:  toolsSchema,
+		OutputSchema: t.outputSchema,
 	}
 
 	if len(authParams) > 0 {
@@ -112,10 +184,31 @@ func (t MockTool) McpManifest() tools.McpManifest {
 	return mcpManifest
 }
 
+// StructuredContent returns the rows of the result, for mock tools with an
+// output schema from tools.NewRowsOutputSchema.
+func (t MockTool) StructuredContent(results any) map[string]any {
+	return tools.NewRowsStructuredContent(results)
+}
+
 func (t MockTool) GetAuthTokenHeaderName(tools.SourceProvider) (string, error) {
 	return "Authorization", nil
 }
 
+// MockToolConfig is used to mock tool configs in tests. It initializes Tool.
+type MockToolConfig struct {
+	Tool MockTool
+
+	tools.CommonConfig
+}
+
+func (c MockToolConfig) ToolConfigType() string {
+	return "mock"
+}
+
+func (c MockToolConfig) Initialize(map[string]sources.Source) (tools.Tool, error) {
+	return c.Tool, nil
+}
+
 // MockPrompt is used to mock prompts in tests
 type MockPrompt struct {
 	Name        string
@@ -140,6 +233,10 @@ func (p MockPrompt) ParseArgs(data map[string]any, claimsMap map[string]map[stri
 	return parameters.ParseParams(params, data, claimsMap)
 }
 
+func (p MockPrompt) GetArguments() prompts.Arguments {
+	return p.Args
+}
+
 func (p MockPrompt) Manifest() prompts.Manifest {
 	var argManifests []parameters.ParameterManifest
 	for _, arg := range p.Args {
@@ -158,3 +255,39 @@ func (p MockPrompt) McpManifest() prompts.McpManifest {
 func (p MockPrompt) ToConfig() prompts.PromptConfig {
 	return nil
 }
+
+// MockResource is used to mock resources in tests
+type MockResource struct {
+	Name        string
+	URI         string
+	URITemplate string
+	// completions are the completions of the template variables
+	completions map[string]*parameters.Completion
+}
+
+func (r MockResource) McpManifest() mcpresources.McpManifest {
+	return mcpresources.McpManifest{
+		URI:         r.URI,
+		URITemplate: r.URITemplate,
+		Name:        r.Name,
+		MimeType:    "text/plain",
+	}
+}
+
+func (r MockResource) Read(ctx context.Context, resourceMgr tools.SourceProvider, uri string) ([]mcpresources.Contents, error) {
+	return []mcpresources.Contents{
+		{
+			URI:      uri,
+			MimeType: "text/plain",
+			Text:     fmt.Sprintf("contents of %s", r.Name),
+		},
+	}, nil
+}
+
+func (r MockResource) GetCompletion(variable string) *parameters.Completion {
+	return r.completions[variable]
+}
+
+func (r MockResource) ToConfig() mcpresources.ResourceConfig {
+	return nil
+}
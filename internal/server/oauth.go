@@ -0,0 +1,205 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/googleapis/genai-toolbox/internal/auth/jwks"
+)
+
+// protectedResourceMetadataPath is the well-known path of the OAuth 2.0
+// protected resource metadata (RFC 9728).
+const protectedResourceMetadataPath = "/.well-known/oauth-protected-resource"
+
+// allToolsets grants access to every toolset when it is mapped to a scope.
+const allToolsets = "*"
+
+// oauthResource protects the MCP endpoint as an OAuth 2.1 resource server:
+// requests must carry a bearer token issued by the configured authorization
+// server for this resource.
+type oauthResource struct {
+	// resource is the canonical URI of the MCP endpoint.
+	resource  string
+	issuer    string
+	audiences []string
+	// scopeToolsets maps each scope to the toolsets it grants access to. If
+	// empty, any valid token grants access to every toolset.
+	scopeToolsets map[string][]string
+	verifier      *jwks.Verifier
+}
+
+// newOAuthResource returns the resource server configured by cfg, or nil if
+// OAuth is not enabled.
+func newOAuthResource(cfg ServerConfig) (*oauthResource, error) {
+	if cfg.OAuthIssuer == "" {
+		return nil, nil
+	}
+	if cfg.OAuthResource == "" {
+		return nil, fmt.Errorf("`--oauth-resource` is required when `--oauth-issuer` is set")
+	}
+	u, err := url.Parse(cfg.OAuthResource)
+	if err != nil || u.Scheme == "" || u.Host == "" || u.Fragment != "" {
+		return nil, fmt.Errorf("`--oauth-resource` must be an absolute URI without fragment: %q", cfg.OAuthResource)
+	}
+	o := &oauthResource{
+		resource:      cfg.OAuthResource,
+		issuer:        cfg.OAuthIssuer,
+		audiences:     cfg.OAuthAudiences,
+		scopeToolsets: make(map[string][]string),
+		verifier:      jwks.NewVerifier(cfg.OAuthIssuer, ""),
+	}
+	if len(o.audiences) == 0 {
+		o.audiences = []string{cfg.OAuthResource}
+	}
+	for _, mapping := range cfg.OAuthScopeToolsets {
+		scope, toolset, ok := strings.Cut(mapping, "=")
+		if !ok || scope == "" {
+			return nil, fmt.Errorf("invalid scope mapping %q: must be formatted as 'scope=toolset'", mapping)
+		}
+		o.scopeToolsets[scope] = append(o.scopeToolsets[scope], toolset)
+	}
+	return o, nil
+}
+
+// metadataPaths returns the paths the protected resource metadata is served
+// at: the well-known path, and the well-known path suffixed with the path of
+// the resource.
+func (o *oauthResource) metadataPaths() []string {
+	paths := []string{protectedResourceMetadataPath}
+	u, _ := url.Parse(o.resource)
+	if p := strings.TrimSuffix(u.Path, "/"); p != "" {
+		paths = append(paths, protectedResourceMetadataPath+p)
+	}
+	return paths
+}
+
+// metadataURL returns the URL of the protected resource metadata, which is
+// advertised to clients in the WWW-Authenticate header.
+func (o *oauthResource) metadataURL() string {
+	u, _ := url.Parse(o.resource)
+	paths := o.metadataPaths()
+	return (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: paths[len(paths)-1]}).String()
+}
+
+// scopes returns the scopes that are mapped to a toolset, in order.
+func (o *oauthResource) scopes() []string {
+	scopes := make([]string, 0, len(o.scopeToolsets))
+	for scope := range o.scopeToolsets {
+		scopes = append(scopes, scope)
+	}
+	slices.Sort(scopes)
+	return scopes
+}
+
+// metadataHandler serves the protected resource metadata.
+func (o *oauthResource) metadataHandler(w http.ResponseWriter, r *http.Request) {
+	metadata := map[string]any{
+		"resource":                 o.resource,
+		"authorization_servers":    []string{o.issuer},
+		"bearer_methods_supported": []string{"header"},
+	}
+	if scopes := o.scopes(); len(scopes) > 0 {
+		metadata["scopes_supported"] = scopes
+	}
+	render.JSON(w, r, metadata)
+}
+
+// challenge rejects a request with a WWW-Authenticate header that points the
+// client to the protected resource metadata.
+func (o *oauthResource) challenge(w http.ResponseWriter, r *http.Request, status int, errorCode string, scopes []string, err error) {
+	params := []string{fmt.Sprintf("resource_metadata=%q", o.metadataURL())}
+	if errorCode != "" {
+		params = append(params, fmt.Sprintf("error=%q", errorCode))
+	}
+	if len(scopes) > 0 {
+		params = append(params, fmt.Sprintf("scope=%q", strings.Join(scopes, " ")))
+	}
+	w.Header().Set("WWW-Authenticate", "Bearer "+strings.Join(params, ", "))
+	_ = render.Render(w, r, newErrResponse(err, status))
+}
+
+type tokenScopesKey struct{}
+
+// authenticate rejects requests without a valid bearer token, and adds the
+// scopes of the token to the context.
+func (o *oauthResource) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scheme, token, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+		if !strings.EqualFold(scheme, "Bearer") || token == "" {
+			o.challenge(w, r, http.StatusUnauthorized, "", nil, fmt.Errorf("missing bearer token"))
+			return
+		}
+		claims, err := o.verifier.Verify(r.Context(), strings.TrimSpace(token), o.audiences)
+		if err != nil {
+			o.challenge(w, r, http.StatusUnauthorized, "invalid_token", nil, err)
+			return
+		}
+		ctx := context.WithValue(r.Context(), tokenScopesKey{}, tokenScopes(claims))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// authorizeToolset rejects requests whose token has no scope granting access
+// to the toolset of the request.
+func (o *oauthResource) authorizeToolset(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(o.scopeToolsets) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		toolsetName := chi.URLParam(r, "toolsetName")
+		granted, _ := r.Context().Value(tokenScopesKey{}).([]string)
+		var required []string
+		for _, scope := range o.scopes() {
+			toolsets := o.scopeToolsets[scope]
+			if !slices.Contains(toolsets, toolsetName) && !slices.Contains(toolsets, allToolsets) {
+				continue
+			}
+			if slices.Contains(granted, scope) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			required = append(required, scope)
+		}
+		o.challenge(w, r, http.StatusForbidden, "insufficient_scope", required, fmt.Errorf("token has no scope granting access to toolset %q", toolsetName))
+	})
+}
+
+// tokenScopes returns the scopes of a token from its `scope` claim, a
+// space-separated string, or its `scp` claim, a string or a list of strings.
+func tokenScopes(claims map[string]any) []string {
+	var scopes []string
+	for _, claim := range []string{"scope", "scp"} {
+		switch v := claims[claim].(type) {
+		case string:
+			scopes = append(scopes, strings.Fields(v)...)
+		case []any:
+			for _, s := range v {
+				if s, ok := s.(string); ok {
+					scopes = append(scopes, s)
+				}
+			}
+		}
+	}
+	return scopes
+}
@@ -0,0 +1,205 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/server/resources"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+)
+
+const oauthTestResource = "https://toolbox.example.com/mcp"
+
+func TestOAuthResourceMetadata(t *testing.T) {
+	issuer := testutils.NewIssuer(t)
+	o, err := newOAuthResource(ServerConfig{
+		OAuthIssuer:        issuer.URL,
+		OAuthResource:      oauthTestResource,
+		OAuthScopeToolsets: []string{"tools.read=", "tools.admin=*"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	wantPaths := []string{"/.well-known/oauth-protected-resource", "/.well-known/oauth-protected-resource/mcp"}
+	if got := o.metadataPaths(); !reflect.DeepEqual(got, wantPaths) {
+		t.Fatalf("unexpected metadata paths: got %v, want %v", got, wantPaths)
+	}
+	if got, want := o.metadataURL(), "https://toolbox.example.com/.well-known/oauth-protected-resource/mcp"; got != want {
+		t.Fatalf("unexpected metadata URL: got %q, want %q", got, want)
+	}
+
+	rec := httptest.NewRecorder()
+	o.metadataHandler(rec, httptest.NewRequest(http.MethodGet, wantPaths[1], nil))
+	var got map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("unable to decode metadata: %s", err)
+	}
+	want := map[string]any{
+		"resource":                 oauthTestResource,
+		"authorization_servers":    []any{issuer.URL},
+		"bearer_methods_supported": []any{"header"},
+		"scopes_supported":         []any{"tools.admin", "tools.read"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected metadata: got %v, want %v", got, want)
+	}
+}
+
+func TestOAuthResourceConfig(t *testing.T) {
+	tcs := []struct {
+		desc string
+		cfg  ServerConfig
+		err  string
+	}{
+		{
+			desc: "disabled",
+			cfg:  ServerConfig{OAuthResource: oauthTestResource},
+		},
+		{
+			desc: "missing resource",
+			cfg:  ServerConfig{OAuthIssuer: "https://issuer.example.com"},
+			err:  "`--oauth-resource` is required when `--oauth-issuer` is set",
+		},
+		{
+			desc: "relative resource",
+			cfg:  ServerConfig{OAuthIssuer: "https://issuer.example.com", OAuthResource: "/mcp"},
+			err:  "`--oauth-resource` must be an absolute URI without fragment: \"/mcp\"",
+		},
+		{
+			desc: "invalid scope mapping",
+			cfg:  ServerConfig{OAuthIssuer: "https://issuer.example.com", OAuthResource: oauthTestResource, OAuthScopeToolsets: []string{"tools.read"}},
+			err:  "invalid scope mapping \"tools.read\": must be formatted as 'scope=toolset'",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			o, err := newOAuthResource(tc.cfg)
+			if tc.err == "" {
+				if err != nil || o != nil {
+					t.Fatalf("unexpected result: %v, %v", o, err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.err {
+				t.Fatalf("unexpected error: got %v, want %q", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestOAuthMcpEndpoint(t *testing.T) {
+	issuer := testutils.NewIssuer(t)
+	o, err := newOAuthResource(ServerConfig{
+		OAuthIssuer:        issuer.URL,
+		OAuthResource:      oauthTestResource,
+		OAuthScopeToolsets: []string{"tools.read=", "tools.read=tool1_only", "tools.admin=*"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	toolsMap, toolsets, promptsMap, promptsets := setUpResources(t, []MockTool{tool1, tool2}, []MockPrompt{prompt1})
+	resourceManager := resources.NewResourceManager(nil, nil, nil, toolsMap, toolsets, promptsMap, promptsets, nil)
+	r, shutdown := setUpServerWithOptions(t, "mcp", resourceManager, func(s *Server) { s.oauth = o })
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	body, err := json.Marshal(map[string]any{
+		"jsonrpc": jsonrpcVersion,
+		"id":      "mcp-initialize",
+		"method":  "initialize",
+		"params":  map[string]any{"protocolVersion": protocolVersion20250618},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error during marshaling of body")
+	}
+	resourceMetadata := `resource_metadata="https://toolbox.example.com/.well-known/oauth-protected-resource/mcp"`
+
+	tcs := []struct {
+		desc          string
+		path          string
+		token         string
+		wantStatus    int
+		wantChallenge string
+	}{
+		{
+			desc:          "missing token",
+			path:          "/",
+			wantStatus:    http.StatusUnauthorized,
+			wantChallenge: "Bearer " + resourceMetadata,
+		},
+		{
+			desc:          "wrong audience",
+			path:          "/",
+			token:         issuer.Token(t, map[string]any{"aud": "https://other.example.com", "scope": "tools.read"}),
+			wantStatus:    http.StatusUnauthorized,
+			wantChallenge: "Bearer " + resourceMetadata + `, error="invalid_token"`,
+		},
+		{
+			desc:       "scope of default toolset",
+			path:       "/",
+			token:      issuer.Token(t, map[string]any{"aud": oauthTestResource, "scope": "openid tools.read"}),
+			wantStatus: http.StatusOK,
+		},
+		{
+			desc:       "scope of toolset",
+			path:       "/tool1_only",
+			token:      issuer.Token(t, map[string]any{"aud": oauthTestResource, "scope": "tools.read"}),
+			wantStatus: http.StatusOK,
+		},
+		{
+			desc:          "insufficient scope",
+			path:          "/tool2_only",
+			token:         issuer.Token(t, map[string]any{"aud": oauthTestResource, "scope": "tools.read"}),
+			wantStatus:    http.StatusForbidden,
+			wantChallenge: "Bearer " + resourceMetadata + `, error="insufficient_scope", scope="tools.admin"`,
+		},
+		{
+			desc:       "scope of every toolset",
+			path:       "/tool2_only",
+			token:      issuer.Token(t, map[string]any{"aud": oauthTestResource, "scp": []string{"tools.admin"}}),
+			wantStatus: http.StatusOK,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			header := map[string]string{}
+			if tc.token != "" {
+				header["Authorization"] = "Bearer " + tc.token
+			}
+			resp, respBody, err := runRequest(ts, http.MethodPost, tc.path, bytes.NewBuffer(body), header)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != tc.wantStatus {
+				t.Fatalf("unexpected status: got %d, want %d: %s", resp.StatusCode, tc.wantStatus, respBody)
+			}
+			if got := resp.Header.Get("WWW-Authenticate"); got != tc.wantChallenge {
+				t.Fatalf("unexpected WWW-Authenticate header: got %q, want %q", got, tc.wantChallenge)
+			}
+			if tc.wantStatus == http.StatusOK && !strings.Contains(string(respBody), `"result"`) {
+				t.Fatalf("unexpected response: %s", respBody)
+			}
+		})
+	}
+}
@@ -15,10 +15,12 @@
 package resources
 
 import (
+	"context"
 	"sync"
 
 	"github.com/googleapis/genai-toolbox/internal/auth"
 	"github.com/googleapis/genai-toolbox/internal/embeddingmodels"
+	"github.com/googleapis/genai-toolbox/internal/mcpresources"
 	"github.com/googleapis/genai-toolbox/internal/prompts"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/tools"
@@ -34,14 +36,26 @@ type ResourceManager struct {
 	toolsets        map[string]tools.Toolset
 	prompts         map[string]prompts.Prompt
 	promptsets      map[string]prompts.Promptset
+	mcpResources    map[string]mcpresources.Resource
+	// generation tracks the uses of the current resources
+	generation *generation
 }
 
+// generation tracks the uses of a set of resources, so that the sources
+// replaced on reload are only closed once no invocation uses them.
+type generation struct {
+	inUse sync.WaitGroup
+}
+
+type generationKey struct{}
+
 func NewResourceManager(
 	sourcesMap map[string]sources.Source,
 	authServicesMap map[string]auth.AuthService,
 	embeddingModelsMap map[string]embeddingmodels.EmbeddingModel,
 	toolsMap map[string]tools.Tool, toolsetsMap map[string]tools.Toolset,
 	promptsMap map[string]prompts.Prompt, promptsetsMap map[string]prompts.Promptset,
+	mcpResourcesMap map[string]mcpresources.Resource,
 
 ) *ResourceManager {
 	resourceMgr := &ResourceManager{
@@ -53,11 +67,30 @@ func NewResourceManager(
 		toolsets:        toolsetsMap,
 		prompts:         promptsMap,
 		promptsets:      promptsetsMap,
+		mcpResources:    mcpResourcesMap,
+		generation:      &generation{},
 	}
 
 	return resourceMgr
 }
 
+// Acquire marks the current resources as in use until release is called, and
+// must be called before any resource is looked up. The returned context holds
+// the resources, so that acquiring it again, e.g. for a task that outlives its
+// request, holds the same resources rather than the current ones.
+func (r *ResourceManager) Acquire(ctx context.Context) (_ context.Context, release func()) {
+	g, ok := ctx.Value(generationKey{}).(*generation)
+	if ok {
+		g.inUse.Add(1)
+		return ctx, sync.OnceFunc(g.inUse.Done)
+	}
+	r.mu.RLock()
+	g = r.generation
+	g.inUse.Add(1)
+	r.mu.RUnlock()
+	return context.WithValue(ctx, generationKey{}, g), sync.OnceFunc(g.inUse.Done)
+}
+
 func (r *ResourceManager) GetSource(sourceName string) (sources.Source, bool) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -107,9 +140,25 @@ func (r *ResourceManager) GetPromptset(promptsetName string) (prompts.Promptset,
 	return promptset, ok
 }
 
-func (r *ResourceManager) SetResources(sourcesMap map[string]sources.Source, authServicesMap map[string]auth.AuthService, embeddingModelsMap map[string]embeddingmodels.EmbeddingModel, toolsMap map[string]tools.Tool, toolsetsMap map[string]tools.Toolset, promptsMap map[string]prompts.Prompt, promptsetsMap map[string]prompts.Promptset) {
+func (r *ResourceManager) GetMcpResource(resourceName string) (mcpresources.Resource, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	resource, ok := r.mcpResources[resourceName]
+	return resource, ok
+}
+
+// SetResources replaces the resources. The returned channel is closed once
+// every use of the replaced resources acquired with Acquire is released.
+func (r *ResourceManager) SetResources(sourcesMap map[string]sources.Source, authServicesMap map[string]auth.AuthService, embeddingModelsMap map[string]embeddingmodels.EmbeddingModel, toolsMap map[string]tools.Tool, toolsetsMap map[string]tools.Toolset, promptsMap map[string]prompts.Prompt, promptsetsMap map[string]prompts.Promptset, mcpResourcesMap map[string]mcpresources.Resource) <-chan struct{} {
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	replaced := r.generation
+	r.generation = &generation{}
+	drained := make(chan struct{})
+	go func() {
+		replaced.inUse.Wait()
+		close(drained)
+	}()
 	r.sources = sourcesMap
 	r.authServices = authServicesMap
 	r.embeddingModels = embeddingModelsMap
@@ -117,6 +166,18 @@ func (r *ResourceManager) SetResources(sourcesMap map[string]sources.Source, aut
 	r.toolsets = toolsetsMap
 	r.prompts = promptsMap
 	r.promptsets = promptsetsMap
+	r.mcpResources = mcpResourcesMap
+	return drained
+}
+
+func (r *ResourceManager) GetSourcesMap() map[string]sources.Source {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	copiedMap := make(map[string]sources.Source, len(r.sources))
+	for k, v := range r.sources {
+		copiedMap[k] = v
+	}
+	return copiedMap
 }
 
 func (r *ResourceManager) GetAuthServiceMap() map[string]auth.AuthService {
@@ -158,3 +219,33 @@ func (r *ResourceManager) GetPromptsMap() map[string]prompts.Prompt {
 	}
 	return copiedMap
 }
+
+func (r *ResourceManager) GetToolsetsMap() map[string]tools.Toolset {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	copiedMap := make(map[string]tools.Toolset, len(r.toolsets))
+	for k, v := range r.toolsets {
+		copiedMap[k] = v
+	}
+	return copiedMap
+}
+
+func (r *ResourceManager) GetPromptsetsMap() map[string]prompts.Promptset {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	copiedMap := make(map[string]prompts.Promptset, len(r.promptsets))
+	for k, v := range r.promptsets {
+		copiedMap[k] = v
+	}
+	return copiedMap
+}
+
+func (r *ResourceManager) GetMcpResourcesMap() map[string]mcpresources.Resource {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	copiedMap := make(map[string]mcpresources.Resource, len(r.mcpResources))
+	for k, v := range r.mcpResources {
+		copiedMap[k] = v
+	}
+	return copiedMap
+}
@@ -15,11 +15,14 @@
 package resources_test
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/auth"
 	"github.com/googleapis/genai-toolbox/internal/embeddingmodels"
+	"github.com/googleapis/genai-toolbox/internal/mcpresources"
 	"github.com/googleapis/genai-toolbox/internal/prompts"
 	"github.com/googleapis/genai-toolbox/internal/server/resources"
 	"github.com/googleapis/genai-toolbox/internal/sources"
@@ -56,7 +59,8 @@ func TestUpdateServer(t *testing.T) {
 			Prompts: []*prompts.Prompt{},
 		},
 	}
-	resMgr := resources.NewResourceManager(newSources, newAuth, newEmbeddingModels, newTools, newToolsets, newPrompts, newPromptsets)
+	newMcpResources := map[string]mcpresources.Resource{"example-resource": nil}
+	resMgr := resources.NewResourceManager(newSources, newAuth, newEmbeddingModels, newTools, newToolsets, newPrompts, newPromptsets, newMcpResources)
 
 	gotSource, _ := resMgr.GetSource("example-source")
 	if diff := cmp.Diff(gotSource, newSources["example-source"]); diff != "" {
@@ -88,6 +92,10 @@ func TestUpdateServer(t *testing.T) {
 		t.Errorf("error updating server, promptset (-want +got):\n%s", diff)
 	}
 
+	if _, ok := resMgr.GetMcpResource("example-resource"); !ok {
+		t.Errorf("error updating server, resource %q not found", "example-resource")
+	}
+
 	updateSource := map[string]sources.Source{
 		"example-source2": &alloydbpg.Source{
 			Config: alloydbpg.Config{
@@ -97,9 +105,36 @@ func TestUpdateServer(t *testing.T) {
 		},
 	}
 
-	resMgr.SetResources(updateSource, newAuth, newEmbeddingModels, newTools, newToolsets, newPrompts, newPromptsets)
+	resMgr.SetResources(updateSource, newAuth, newEmbeddingModels, newTools, newToolsets, newPrompts, newPromptsets, newMcpResources)
 	gotSource, _ = resMgr.GetSource("example-source2")
 	if diff := cmp.Diff(gotSource, updateSource["example-source2"]); diff != "" {
 		t.Errorf("error updating server, sources (-want +got):\n%s", diff)
 	}
 }
+
+func TestSetResourcesDrained(t *testing.T) {
+	resMgr := resources.NewResourceManager(nil, nil, nil, nil, nil, nil, nil, nil)
+	ctx, release := resMgr.Acquire(context.Background())
+	// a task acquires the resources of its request
+	_, releaseTask := resMgr.Acquire(ctx)
+	release()
+
+	drained := resMgr.SetResources(nil, nil, nil, nil, nil, nil, nil, nil)
+	// uses that start after the resources are replaced do not delay the drain
+	_, releaseNext := resMgr.Acquire(context.Background())
+	defer releaseNext()
+	select {
+	case <-drained:
+		t.Fatalf("replaced resources drained while a task uses them")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	releaseTask()
+	// releasing twice has no effect
+	releaseTask()
+	select {
+	case <-drained:
+	case <-time.After(time.Second):
+		t.Fatalf("replaced resources not drained once released")
+	}
+}
@@ -18,11 +18,14 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"maps"
 	"net"
 	"net/http"
+	"reflect"
 	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -32,7 +35,9 @@ import (
 	"github.com/googleapis/genai-toolbox/internal/auth"
 	"github.com/googleapis/genai-toolbox/internal/embeddingmodels"
 	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/mcpresources"
 	"github.com/googleapis/genai-toolbox/internal/prompts"
+	mcputil "github.com/googleapis/genai-toolbox/internal/server/mcp/util"
 	"github.com/googleapis/genai-toolbox/internal/server/resources"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/telemetry"
@@ -51,19 +56,45 @@ type Server struct {
 	logger          log.Logger
 	instrumentation *telemetry.Instrumentation
 	sseManager      *sseManager
-	ResourceMgr     *resources.ResourceManager
+	// stdioSessions are the live stdio sessions, which are notified when
+	// resources are reloaded.
+	stdioMu       sync.Mutex
+	stdioSessions map[*stdioSession]struct{}
+	pageSize      int
+	// disableDefaultToolsetAPI removes the /api routes that use the default
+	// toolset.
+	disableDefaultToolsetAPI bool
+	// taskPool runs the tasks of every MCP session.
+	taskPool *mcputil.TaskPool
+	// oauth protects the MCP endpoint if OAuth is enabled.
+	oauth *oauthResource
+	// stdioAuthFile holds the headers of stdio requests, if set.
+	stdioAuthFile string
+	ResourceMgr   *resources.ResourceManager
 }
 
-func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
-	map[string]sources.Source,
-	map[string]auth.AuthService,
-	map[string]embeddingmodels.EmbeddingModel,
-	map[string]tools.Tool,
-	map[string]tools.Toolset,
-	map[string]prompts.Prompt,
-	map[string]prompts.Promptset,
-	error,
-) {
+// maxQueuedTasks is the maximum number of MCP tasks that wait for a worker.
+const maxQueuedTasks = 1000
+
+// InitializedConfigs are the resources initialized from a ServerConfig, by
+// name.
+type InitializedConfigs struct {
+	Sources         map[string]sources.Source
+	AuthServices    map[string]auth.AuthService
+	EmbeddingModels map[string]embeddingmodels.EmbeddingModel
+	Tools           map[string]tools.Tool
+	Toolsets        map[string]tools.Toolset
+	Prompts         map[string]prompts.Prompt
+	Promptsets      map[string]prompts.Promptset
+	Resources       map[string]mcpresources.Resource
+}
+
+// NewResourceManager returns a ResourceManager of the resources.
+func (c InitializedConfigs) NewResourceManager() *resources.ResourceManager {
+	return resources.NewResourceManager(c.Sources, c.AuthServices, c.EmbeddingModels, c.Tools, c.Toolsets, c.Prompts, c.Promptsets, c.Resources)
+}
+
+func InitializeConfigs(ctx context.Context, cfg ServerConfig) (_ InitializedConfigs, err error) {
 	metadataStr := cfg.Version
 	if len(cfg.UserAgentMetadata) > 0 {
 		metadataStr += "+" + strings.Join(cfg.UserAgentMetadata, "+")
@@ -81,7 +112,18 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 
 	// initialize and validate the sources from configs
 	sourcesMap := make(map[string]sources.Source)
+	initializedSources := make(map[string]sources.Source)
+	defer func() {
+		// the sources are never used if any other config fails to initialize
+		if err != nil {
+			_ = sources.CloseAll(initializedSources)
+		}
+	}()
 	for name, sc := range cfg.SourceConfigs {
+		if previous, ok := cfg.PreviousSources[name]; ok && reflect.DeepEqual(previous.ToConfig(), sc) {
+			sourcesMap[name] = previous
+			continue
+		}
 		s, err := func() (sources.Source, error) {
 			childCtx, span := instrumentation.Tracer.Start(
 				ctx,
@@ -97,9 +139,10 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 			return s, nil
 		}()
 		if err != nil {
-			return nil, nil, nil, nil, nil, nil, nil, err
+			return InitializedConfigs{}, err
 		}
 		sourcesMap[name] = s
+		initializedSources[name] = s
 	}
 	sourceNames := make([]string, 0, len(sourcesMap))
 	for name := range sourcesMap {
@@ -125,7 +168,7 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 			return a, nil
 		}()
 		if err != nil {
-			return nil, nil, nil, nil, nil, nil, nil, err
+			return InitializedConfigs{}, err
 		}
 		authServicesMap[name] = a
 	}
@@ -153,7 +196,7 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 			return em, nil
 		}()
 		if err != nil {
-			return nil, nil, nil, nil, nil, nil, nil, err
+			return InitializedConfigs{}, err
 		}
 		embeddingModelsMap[name] = em
 	}
@@ -181,7 +224,7 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 			return t, nil
 		}()
 		if err != nil {
-			return nil, nil, nil, nil, nil, nil, nil, err
+			return InitializedConfigs{}, err
 		}
 		toolsMap[name] = t
 	}
@@ -191,15 +234,48 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 	}
 	l.InfoContext(ctx, fmt.Sprintf("Initialized %d tools: %s", len(toolsMap), strings.Join(toolNames, ", ")))
 
-	// create a default toolset that contains all tools
+	// create a default toolset that contains all tools and resources
 	allToolNames := make([]string, 0, len(toolsMap))
 	for name := range toolsMap {
 		allToolNames = append(allToolNames, name)
 	}
+	// the tools are sorted, so that the default toolset is the same across
+	// reloads of the same tools
+	slices.Sort(allToolNames)
+	allResourceNames := slices.Sorted(maps.Keys(cfg.ResourceConfigs))
 	if cfg.ToolsetConfigs == nil {
 		cfg.ToolsetConfigs = make(ToolsetConfigs)
 	}
-	cfg.ToolsetConfigs[""] = tools.ToolsetConfig{Name: "", ToolNames: allToolNames}
+	cfg.ToolsetConfigs[""] = tools.ToolsetConfig{Name: "", ToolNames: allToolNames, ResourceNames: allResourceNames}
+
+	// the server metadata applies to every toolset, unless overridden
+	var serverMetadata tools.ServerMetadata
+	var toolsDisplay, promptsDisplay map[string]tools.DisplayMetadata
+	if c := cfg.ServerMetadataConfig; c != nil {
+		serverMetadata, toolsDisplay, promptsDisplay = c.ServerMetadata, c.Tools, c.Prompts
+	}
+	for name := range toolsDisplay {
+		if _, ok := toolsMap[name]; !ok {
+			return InitializedConfigs{}, fmt.Errorf("server metadata references unknown tool %q", name)
+		}
+	}
+
+	// initialize the policies, which apply to the invocations of every
+	// toolset, in order of their names
+	policyNames := make([]string, 0, len(cfg.PolicyConfigs))
+	for name := range cfg.PolicyConfigs {
+		policyNames = append(policyNames, name)
+	}
+	slices.Sort(policyNames)
+	policies := make([]*tools.InvocationPolicy, 0, len(policyNames))
+	for _, name := range policyNames {
+		p, err := cfg.PolicyConfigs[name].Initialize(toolsMap)
+		if err != nil {
+			return InitializedConfigs{}, fmt.Errorf("unable to initialize policy %q: %w", name, err)
+		}
+		policies = append(policies, p)
+	}
+	l.InfoContext(ctx, fmt.Sprintf("Initialized %d policies: %s", len(policies), strings.Join(policyNames, ", ")))
 
 	// initialize and validate the toolsets from configs
 	toolsetsMap := make(map[string]tools.Toolset)
@@ -215,13 +291,33 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 			if err != nil {
 				return tools.Toolset{}, fmt.Errorf("unable to initialize toolset %q: %w", name, err)
 			}
+			t.ServerMetadata = serverMetadata.Override(tc.ServerMetadata)
+			t.Policies = policies
+			for i, m := range t.McpManifest {
+				d := toolsDisplay[m.Name]
+				t.McpManifest[i].Title, t.McpManifest[i].Icons = d.Title, d.Icons
+			}
 			return t, err
 		}()
 		if err != nil {
-			return nil, nil, nil, nil, nil, nil, nil, err
+			return InitializedConfigs{}, err
 		}
 		toolsetsMap[name] = t
 	}
+	// the tools of a toolset with an authorization policy are restricted by
+	// it in the default toolset too, which would otherwise bypass it
+	defaultToolset := toolsetsMap[""]
+	defaultToolset.ToolAuthorizations = make(map[string][]*tools.AuthorizationPolicy)
+	for name, t := range toolsetsMap {
+		if name == "" || t.Authorization == nil {
+			continue
+		}
+		for toolName := range t.Manifest.ToolsManifest {
+			defaultToolset.ToolAuthorizations[toolName] = append(defaultToolset.ToolAuthorizations[toolName], t.Authorization)
+		}
+	}
+	toolsetsMap[""] = defaultToolset
+
 	toolsetNames := make([]string, 0, len(toolsetsMap))
 	for name := range toolsetsMap {
 		if name == "" {
@@ -250,7 +346,7 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 			return p, nil
 		}()
 		if err != nil {
-			return nil, nil, nil, nil, nil, nil, nil, err
+			return InitializedConfigs{}, err
 		}
 		promptsMap[name] = p
 	}
@@ -265,10 +361,16 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 	for name := range promptsMap {
 		allPromptNames = append(allPromptNames, name)
 	}
+	slices.Sort(allPromptNames)
 	if cfg.PromptsetConfigs == nil {
 		cfg.PromptsetConfigs = make(PromptsetConfigs)
 	}
 	cfg.PromptsetConfigs[""] = prompts.PromptsetConfig{Name: "", PromptNames: allPromptNames}
+	for name := range promptsDisplay {
+		if _, ok := promptsMap[name]; !ok {
+			return InitializedConfigs{}, fmt.Errorf("server metadata references unknown prompt %q", name)
+		}
+	}
 
 	// initialize and validate the promptsets from configs
 	promptsetsMap := make(map[string]prompts.Promptset)
@@ -284,10 +386,14 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 			if err != nil {
 				return prompts.Promptset{}, fmt.Errorf("unable to initialize promptset %q: %w", name, err)
 			}
+			for i, m := range p.McpManifest {
+				d := promptsDisplay[m.Name]
+				p.McpManifest[i].Title, p.McpManifest[i].Icons = d.Title, d.Icons
+			}
 			return p, err
 		}()
 		if err != nil {
-			return nil, nil, nil, nil, nil, nil, nil, err
+			return InitializedConfigs{}, err
 		}
 		promptsetsMap[name] = p
 	}
@@ -301,7 +407,53 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 	}
 	l.InfoContext(ctx, fmt.Sprintf("Initialized %d promptsets: %s", len(promptsetsMap), strings.Join(promptsetNames, ", ")))
 
-	return sourcesMap, authServicesMap, embeddingModelsMap, toolsMap, toolsetsMap, promptsMap, promptsetsMap, nil
+	// initialize and validate the resources from configs
+	mcpResourcesMap := make(map[string]mcpresources.Resource)
+	for name, rc := range cfg.ResourceConfigs {
+		r, err := func() (mcpresources.Resource, error) {
+			_, span := instrumentation.Tracer.Start(
+				ctx,
+				"toolbox/server/resource/init",
+				trace.WithAttributes(attribute.String("resource_type", rc.ResourceConfigType())),
+				trace.WithAttributes(attribute.String("resource_name", name)),
+			)
+			defer span.End()
+			r, err := rc.Initialize(sourcesMap)
+			if err != nil {
+				return nil, fmt.Errorf("unable to initialize resource %q: %w", name, err)
+			}
+			return r, nil
+		}()
+		if err != nil {
+			return InitializedConfigs{}, err
+		}
+		mcpResourcesMap[name] = r
+	}
+	resourceNames := make([]string, 0, len(mcpResourcesMap))
+	for name := range mcpResourcesMap {
+		resourceNames = append(resourceNames, name)
+	}
+	l.InfoContext(ctx, fmt.Sprintf("Initialized %d resources: %s", len(mcpResourcesMap), strings.Join(resourceNames, ", ")))
+
+	// check that the resources of each toolset exist
+	for name, t := range toolsetsMap {
+		for _, resourceName := range t.ResourceNames {
+			if _, ok := mcpResourcesMap[resourceName]; !ok {
+				return InitializedConfigs{}, fmt.Errorf("unable to initialize toolset %q: resource does not exist: %s", name, resourceName)
+			}
+		}
+	}
+
+	return InitializedConfigs{
+		Sources:         sourcesMap,
+		AuthServices:    authServicesMap,
+		EmbeddingModels: embeddingModelsMap,
+		Tools:           toolsMap,
+		Toolsets:        toolsetsMap,
+		Prompts:         promptsMap,
+		Promptsets:      promptsetsMap,
+		Resources:       mcpResourcesMap,
+	}, nil
 }
 
 func hostCheck(allowedHosts map[string]struct{}) func(http.Handler) http.Handler {
@@ -358,26 +510,47 @@ func NewServer(ctx context.Context, cfg ServerConfig) (*Server, error) {
 	logger := l.SlogLogger()
 	r.Use(httplog.RequestLogger(logger, httpOpts))
 
-	sourcesMap, authServicesMap, embeddingModelsMap, toolsMap, toolsetsMap, promptsMap, promptsetsMap, err := InitializeConfigs(ctx, cfg)
+	initialized, err := InitializeConfigs(ctx, cfg)
 	if err != nil {
 		return nil, fmt.Errorf("unable to initialize configs: %w", err)
 	}
 
+	oauth, err := newOAuthResource(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize OAuth: %w", err)
+	}
+
+	for _, name := range cfg.RequireAuthServices {
+		if _, ok := initialized.AuthServices[name]; !ok {
+			return nil, fmt.Errorf("`--require-auth-service` names unknown auth service %q", name)
+		}
+	}
+
+	tlsConfig, err := newTLSConfig(cfg, l)
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize TLS: %w", err)
+	}
+
 	addr := net.JoinHostPort(cfg.Address, strconv.Itoa(cfg.Port))
-	srv := &http.Server{Addr: addr, Handler: r}
+	srv := &http.Server{Addr: addr, Handler: r, TLSConfig: tlsConfig}
 
 	sseManager := newSseManager(ctx)
 
-	resourceManager := resources.NewResourceManager(sourcesMap, authServicesMap, embeddingModelsMap, toolsMap, toolsetsMap, promptsMap, promptsetsMap)
+	resourceManager := initialized.NewResourceManager()
 
 	s := &Server{
-		version:         cfg.Version,
-		srv:             srv,
-		root:            r,
-		logger:          l,
-		instrumentation: instrumentation,
-		sseManager:      sseManager,
-		ResourceMgr:     resourceManager,
+		version:                  cfg.Version,
+		srv:                      srv,
+		root:                     r,
+		logger:                   l,
+		instrumentation:          instrumentation,
+		sseManager:               sseManager,
+		pageSize:                 cfg.PageSize,
+		disableDefaultToolsetAPI: cfg.DisableDefaultToolsetAPI,
+		taskPool:                 mcputil.NewTaskPool(max(cfg.TaskWorkers, 1), maxQueuedTasks),
+		oauth:                    oauth,
+		stdioAuthFile:            cfg.StdioAuthFile,
+		ResourceMgr:              resourceManager,
 	}
 
 	// cors
@@ -389,8 +562,8 @@ func NewServer(ctx context.Context, cfg ServerConfig) (*Server, error) {
 		AllowedMethods:   []string{"GET", "POST", "DELETE", "OPTIONS"},
 		AllowCredentials: true, // required since Toolbox uses auth headers
 		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "Mcp-Session-Id", "MCP-Protocol-Version"},
-		ExposedHeaders:   []string{"Mcp-Session-Id"}, // headers that are sent to clients
-		MaxAge:           300,                        // cache preflight results for 5 minutes
+		ExposedHeaders:   []string{"Mcp-Session-Id", "WWW-Authenticate"}, // headers that are sent to clients
+		MaxAge:           300,                                            // cache preflight results for 5 minutes
 	}
 	r.Use(cors.Handler(corsOpts))
 	// validate hosts for DNS rebinding attacks
@@ -406,24 +579,38 @@ func NewServer(ctx context.Context, cfg ServerConfig) (*Server, error) {
 		allowedHostsMap[hostname] = struct{}{}
 	}
 	r.Use(hostCheck(allowedHostsMap))
+	r.Use(tlsConnectionState)
 
 	// control plane
 	apiR, err := apiRouter(s)
 	if err != nil {
 		return nil, err
 	}
-	r.Mount("/api", apiR)
 	mcpR, err := mcpRouter(s)
 	if err != nil {
 		return nil, err
 	}
-	r.Mount("/mcp", mcpR)
+	var webR chi.Router
 	if cfg.UI {
-		webR, err := webRouter()
+		webR, err = webRouter()
 		if err != nil {
 			return nil, err
 		}
-		r.Mount("/ui", webR)
+	}
+	r.Group(func(r chi.Router) {
+		if len(cfg.RequireAuthServices) > 0 {
+			r.Use(s.requireAuth(cfg.RequireAuthServices))
+		}
+		r.Mount("/api", apiR)
+		r.Mount("/mcp", mcpR)
+		if webR != nil {
+			r.Mount("/ui", webR)
+		}
+	})
+	if s.oauth != nil {
+		for _, path := range s.oauth.metadataPaths() {
+			r.Get(path, s.oauth.metadataHandler)
+		}
 	}
 	// default endpoint for validating server is running
 	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
@@ -452,6 +639,11 @@ func (s *Server) Listen(ctx context.Context) error {
 
 // Serve starts an HTTP server for the given Server instance.
 func (s *Server) Serve(ctx context.Context) error {
+	if s.srv.TLSConfig != nil {
+		s.logger.DebugContext(ctx, "Starting a HTTPS server.")
+		// the certificate is served by TLSConfig.GetCertificate
+		return s.srv.ServeTLS(s.listener, "", "")
+	}
 	s.logger.DebugContext(ctx, "Starting a HTTP server.")
 	return s.srv.Serve(s.listener)
 }
@@ -20,6 +20,7 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -28,9 +29,11 @@ import (
 	"github.com/googleapis/genai-toolbox/internal/embeddingmodels"
 	"github.com/googleapis/genai-toolbox/internal/log"
 	"github.com/googleapis/genai-toolbox/internal/prompts"
+	"github.com/googleapis/genai-toolbox/internal/prompts/custom"
 	"github.com/googleapis/genai-toolbox/internal/server"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/sources/alloydbpg"
+	"github.com/googleapis/genai-toolbox/internal/sources/sqlite"
 	"github.com/googleapis/genai-toolbox/internal/telemetry"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
 	"github.com/googleapis/genai-toolbox/internal/tools"
@@ -165,7 +168,7 @@ func TestUpdateServer(t *testing.T) {
 			Prompts: []*prompts.Prompt{},
 		},
 	}
-	s.ResourceMgr.SetResources(newSources, newAuth, newEmbeddingModels, newTools, newToolsets, newPrompts, newPromptsets)
+	s.ResourceMgr.SetResources(newSources, newAuth, newEmbeddingModels, newTools, newToolsets, newPrompts, newPromptsets, nil)
 	if err != nil {
 		t.Errorf("error updating server: %s", err)
 	}
@@ -259,3 +262,204 @@ func TestNameValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestInitializeConfigsServerMetadata(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("error setting up logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation("0.0.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ctx = util.WithInstrumentation(ctx, instrumentation)
+
+	icons := []tools.Icon{{Src: "https://example.com/icon.png"}}
+	cfg := server.ServerConfig{
+		Version: "0.0.0",
+		ToolsetConfigs: server.ToolsetConfigs{
+			"docs": tools.ToolsetConfig{Name: "docs", ServerMetadata: tools.ServerMetadata{Instructions: "Search the docs."}},
+		},
+		PromptConfigs: server.PromptConfigs{
+			"review": &custom.Config{Name: "review", Messages: []prompts.Message{{Role: "user", Content: "Review."}}},
+		},
+		ServerMetadataConfig: &server.ServerMetadataConfig{
+			ServerMetadata: tools.ServerMetadata{
+				DisplayMetadata: tools.DisplayMetadata{Title: "Toolbox", Icons: icons},
+				Instructions:    "Use the tools.",
+			},
+			Prompts: map[string]tools.DisplayMetadata{"review": {Title: "Code review"}},
+		},
+	}
+	initialized, err := server.InitializeConfigs(ctx, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	want := tools.ServerMetadata{DisplayMetadata: tools.DisplayMetadata{Title: "Toolbox", Icons: icons}, Instructions: "Use the tools."}
+	if diff := cmp.Diff(want, initialized.Toolsets[""].ServerMetadata); diff != "" {
+		t.Errorf("unexpected server metadata of default toolset (-want +got):\n%s", diff)
+	}
+	want.Instructions = "Search the docs."
+	if diff := cmp.Diff(want, initialized.Toolsets["docs"].ServerMetadata); diff != "" {
+		t.Errorf("unexpected server metadata of toolset (-want +got):\n%s", diff)
+	}
+	if got := initialized.Promptsets[""].McpManifest[0].Title; got != "Code review" {
+		t.Errorf("unexpected prompt title: got %q", got)
+	}
+
+	cfg.ServerMetadataConfig.Tools = map[string]tools.DisplayMetadata{"unknown": {Title: "Unknown"}}
+	_, err = server.InitializeConfigs(ctx, cfg)
+	if want := `server metadata references unknown tool "unknown"`; err == nil || err.Error() != want {
+		t.Errorf("unexpected error: got %v, want %q", err, want)
+	}
+}
+
+func TestInitializeConfigsToolsetResources(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("error setting up logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation("0.0.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ctx = util.WithInstrumentation(ctx, instrumentation)
+
+	cfg := server.ServerConfig{
+		Version: "0.0.0",
+		ToolsetConfigs: server.ToolsetConfigs{
+			"docs": tools.ToolsetConfig{Name: "docs", ResourceNames: []string{"unknown"}},
+		},
+	}
+	_, err = server.InitializeConfigs(ctx, cfg)
+	if want := `unable to initialize toolset "docs": resource does not exist: unknown`; err == nil || err.Error() != want {
+		t.Errorf("unexpected error: got %v, want %q", err, want)
+	}
+}
+
+func TestInitializeConfigsPreviousSources(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("error setting up logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation("0.0.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ctx = util.WithInstrumentation(ctx, instrumentation)
+
+	dir := t.TempDir()
+	cfg := server.ServerConfig{
+		Version: "0.0.0",
+		SourceConfigs: server.SourceConfigs{
+			"unchanged": sqlite.Config{Name: "unchanged", Type: "sqlite", Database: filepath.Join(dir, "unchanged.db")},
+			"changed":   sqlite.Config{Name: "changed", Type: "sqlite", Database: filepath.Join(dir, "old.db")},
+		},
+	}
+	previous, err := server.InitializeConfigs(ctx, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	cfg.SourceConfigs["changed"] = sqlite.Config{Name: "changed", Type: "sqlite", Database: filepath.Join(dir, "new.db")}
+	cfg.PreviousSources = previous.Sources
+	reloaded, err := server.InitializeConfigs(ctx, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if reloaded.Sources["unchanged"] != previous.Sources["unchanged"] {
+		t.Errorf("source with an unchanged config was initialized again")
+	}
+	if reloaded.Sources["changed"] == previous.Sources["changed"] {
+		t.Errorf("source with a changed config was reused")
+	}
+}
+
+func TestInitializeConfigsDefaultToolsetAuthorization(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("error setting up logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation("0.0.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ctx = util.WithInstrumentation(ctx, instrumentation)
+
+	admins := &tools.AuthorizationPolicy{Allow: []tools.AuthorizationRule{{
+		Claims: map[string]tools.ClaimMatcher{"groups": {Contains: "admins"}},
+	}}}
+	cfg := server.ServerConfig{
+		Version: "0.0.0",
+		ToolConfigs: server.ToolConfigs{
+			"public_tool": server.MockToolConfig{Tool: server.MockTool{Name: "public_tool"}},
+			"admin_tool":  server.MockToolConfig{Tool: server.MockTool{Name: "admin_tool"}},
+		},
+		ToolsetConfigs: server.ToolsetConfigs{
+			"admin": tools.ToolsetConfig{Name: "admin", ToolNames: []string{"admin_tool"}, Authorization: admins},
+		},
+	}
+	initialized, err := server.InitializeConfigs(ctx, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// the tools of the admin toolset are restricted in the default toolset
+	defaultToolset := initialized.Toolsets[""]
+	adminClaims := map[string]map[string]any{"my-auth": {"groups": []any{"admins"}}}
+	for _, tc := range []struct {
+		tool   string
+		claims map[string]map[string]any
+		want   bool
+	}{
+		{tool: "public_tool", want: true},
+		{tool: "admin_tool", want: false},
+		{tool: "admin_tool", claims: adminClaims, want: true},
+	} {
+		if got := defaultToolset.Allows(tc.tool, initialized.Tools[tc.tool], tc.claims); got != tc.want {
+			t.Errorf("unexpected authorization of %q with claims %v: got %t, want %t", tc.tool, tc.claims, got, tc.want)
+		}
+	}
+	got := make([]string, 0)
+	for _, m := range defaultToolset.AllowedMcpManifest(nil) {
+		got = append(got, m.Name)
+	}
+	if diff := cmp.Diff([]string{"public_tool"}, got); diff != "" {
+		t.Fatalf("unexpected tools of default toolset (-want +got):\n%s", diff)
+	}
+}
+
+func TestInitializeConfigsDefaultToolsetOrder(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("error setting up logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation("0.0.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ctx = util.WithInstrumentation(ctx, instrumentation)
+
+	cfg := server.ServerConfig{Version: "0.0.0", ToolConfigs: server.ToolConfigs{}}
+	want := make([]string, 0)
+	for i := range 20 {
+		name := fmt.Sprintf("tool_%02d", i)
+		cfg.ToolConfigs[name] = server.MockToolConfig{Tool: server.MockTool{Name: name}}
+		want = append(want, name)
+	}
+	// maps are iterated in random order, so initialize the tools repeatedly
+	for range 5 {
+		initialized, err := server.InitializeConfigs(ctx, cfg)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		got := make([]string, 0)
+		for _, m := range initialized.Toolsets[""].McpManifest {
+			got = append(got, m.Name)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Fatalf("unexpected tools of default toolset (-want +got):\n%s", diff)
+		}
+	}
+}
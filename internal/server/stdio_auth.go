@@ -0,0 +1,117 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	yaml "github.com/goccy/go-yaml"
+)
+
+const (
+	// stdioHeadersMetaKey is the key of the `_meta` entry of a stdio request
+	// that holds the headers of the request, e.g.
+	// {"my-google-auth_token": "<ID token>"}.
+	stdioHeadersMetaKey = "toolbox/headers"
+	// authorizationEnvVar holds the `Authorization` header of stdio requests,
+	// used by tools that forward the client's access token.
+	authorizationEnvVar = "TOOLBOX_AUTHORIZATION"
+)
+
+// authTokenEnvVar returns the environment variable that holds the token of
+// the auth service name for stdio requests, e.g.
+// TOOLBOX_AUTH_MY_GOOGLE_AUTH_TOKEN for "my-google-auth".
+func authTokenEnvVar(name string) string {
+	normalized := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+	return "TOOLBOX_AUTH_" + normalized + "_TOKEN"
+}
+
+// stdioHeader returns the headers of a stdio request, which auth services
+// verify as they would the headers of a HTTP request. They are taken, from
+// lowest to highest precedence, from the environment, the stdio auth file and
+// the `_meta` of the request. Returns nil if no header is found.
+func (s *Server) stdioHeader(ctx context.Context, body []byte) http.Header {
+	header := make(http.Header)
+
+	if v := os.Getenv(authorizationEnvVar); v != "" {
+		header.Set("Authorization", v)
+	}
+	for name := range s.ResourceMgr.GetAuthServiceMap() {
+		if v := os.Getenv(authTokenEnvVar(name)); v != "" {
+			header.Set(name+"_token", v)
+		}
+	}
+
+	// the file is read for every request so that rotated tokens are used
+	if s.stdioAuthFile != "" {
+		fileHeaders, err := readStdioAuthFile(s.stdioAuthFile)
+		if err != nil {
+			s.logger.WarnContext(ctx, err.Error())
+		}
+		for k, v := range fileHeaders {
+			header.Set(k, v)
+		}
+	}
+
+	var req struct {
+		Params struct {
+			Meta map[string]json.RawMessage `json:"_meta"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal(body, &req); err == nil {
+		if raw, ok := req.Params.Meta[stdioHeadersMetaKey]; ok {
+			var metaHeaders map[string]string
+			if err := json.Unmarshal(raw, &metaHeaders); err != nil {
+				s.logger.WarnContext(ctx, fmt.Sprintf("invalid %q in _meta: %s", stdioHeadersMetaKey, err))
+			}
+			for k, v := range metaHeaders {
+				header.Set(k, v)
+			}
+		}
+	}
+
+	if len(header) == 0 {
+		return nil
+	}
+	return header
+}
+
+// readStdioAuthFile reads a YAML or JSON file that maps header names to
+// values.
+func readStdioAuthFile(path string) (map[string]string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read stdio auth file: %w", err)
+	}
+	var headers map[string]string
+	if err := yaml.Unmarshal(b, &headers); err != nil {
+		return nil, fmt.Errorf("unable to parse stdio auth file %q: %w", path, err)
+	}
+	return headers, nil
+}
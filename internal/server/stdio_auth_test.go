@@ -0,0 +1,124 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/auth/google"
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/server/resources"
+)
+
+func TestAuthTokenEnvVar(t *testing.T) {
+	tcs := map[string]string{
+		"my-google-auth": "TOOLBOX_AUTH_MY_GOOGLE_AUTH_TOKEN",
+		"auth2":          "TOOLBOX_AUTH_AUTH2_TOKEN",
+		"My.Auth":        "TOOLBOX_AUTH_MY_AUTH_TOKEN",
+	}
+	for name, want := range tcs {
+		if got := authTokenEnvVar(name); got != want {
+			t.Errorf("authTokenEnvVar(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestStdioHeader(t *testing.T) {
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "warn")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	authServices := map[string]auth.AuthService{
+		"my-google-auth": google.AuthService{Config: google.Config{Name: "my-google-auth"}},
+		"other-auth":     google.AuthService{Config: google.Config{Name: "other-auth"}},
+	}
+	authFile := filepath.Join(t.TempDir(), "auth.yaml")
+	if err := os.WriteFile(authFile, []byte("other-auth_token: from-file\nAuthorization: Bearer from-file\n"), 0o600); err != nil {
+		t.Fatalf("unable to write auth file: %s", err)
+	}
+
+	tcs := []struct {
+		desc     string
+		env      map[string]string
+		authFile string
+		body     string
+		want     http.Header
+	}{
+		{
+			desc: "no identity material",
+			body: `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`,
+			want: nil,
+		},
+		{
+			desc: "environment",
+			env: map[string]string{
+				"TOOLBOX_AUTH_MY_GOOGLE_AUTH_TOKEN": "from-env",
+				"TOOLBOX_AUTHORIZATION":             "Bearer from-env",
+			},
+			body: `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`,
+			want: http.Header{
+				"My-Google-Auth_token": {"from-env"},
+				"Authorization":        {"Bearer from-env"},
+			},
+		},
+		{
+			desc:     "file overrides environment",
+			env:      map[string]string{"TOOLBOX_AUTH_OTHER_AUTH_TOKEN": "from-env"},
+			authFile: authFile,
+			body:     `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`,
+			want: http.Header{
+				"Other-Auth_token": {"from-file"},
+				"Authorization":    {"Bearer from-file"},
+			},
+		},
+		{
+			desc:     "meta overrides file",
+			authFile: authFile,
+			body:     `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"t","_meta":{"toolbox/headers":{"other-auth_token":"from-meta"}}}}`,
+			want: http.Header{
+				"Other-Auth_token": {"from-meta"},
+				"Authorization":    {"Bearer from-file"},
+			},
+		},
+		{
+			desc:     "missing file",
+			authFile: filepath.Join(t.TempDir(), "missing.yaml"),
+			body:     `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`,
+			want:     nil,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+			s := &Server{
+				logger:        testLogger,
+				stdioAuthFile: tc.authFile,
+				ResourceMgr:   resources.NewResourceManager(nil, authServices, nil, nil, nil, nil, nil, nil),
+			}
+			got := s.stdioHeader(context.Background(), []byte(tc.body))
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("unexpected header (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+// newTLSConfig returns the TLS configuration of the server set by cfg, or nil
+// if TLS is not enabled.
+func newTLSConfig(cfg ServerConfig, logger log.Logger) (*tls.Config, error) {
+	if cfg.TLSCertFile == "" && cfg.TLSKeyFile == "" {
+		if cfg.TLSClientCAFile != "" || cfg.TLSRequireClientCert {
+			return nil, fmt.Errorf("client certificates require `--tls-cert-file` and `--tls-key-file`")
+		}
+		return nil, nil
+	}
+	if cfg.TLSCertFile == "" || cfg.TLSKeyFile == "" {
+		return nil, fmt.Errorf("`--tls-cert-file` and `--tls-key-file` must be set together")
+	}
+	certs := &certReloader{certFile: cfg.TLSCertFile, keyFile: cfg.TLSKeyFile, logger: logger}
+	if err := certs.reload(); err != nil {
+		return nil, err
+	}
+	tlsConfig := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: certs.getCertificate,
+	}
+	if cfg.TLSClientCAFile == "" {
+		if cfg.TLSRequireClientCert {
+			return nil, fmt.Errorf("`--tls-require-client-cert` requires `--tls-client-ca-file`")
+		}
+		return tlsConfig, nil
+	}
+	b, err := os.ReadFile(cfg.TLSClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read client CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(b) {
+		return nil, fmt.Errorf("no PEM encoded certificates found in client CA file %q", cfg.TLSClientCAFile)
+	}
+	tlsConfig.ClientCAs = pool
+	tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	if cfg.TLSRequireClientCert {
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsConfig, nil
+}
+
+// certReloader serves the certificate of the server, reloaded when the
+// modification time of the certificate or key file changes.
+type certReloader struct {
+	certFile string
+	keyFile  string
+	logger   log.Logger
+
+	mu          sync.Mutex
+	cert        *tls.Certificate
+	certModTime time.Time
+	keyModTime  time.Time
+}
+
+func (c *certReloader) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	certInfo, certErr := os.Stat(c.certFile)
+	keyInfo, keyErr := os.Stat(c.keyFile)
+	if certErr != nil || keyErr != nil {
+		// the files may be replaced at the moment, keep the previous certificate
+		return c.cert, nil
+	}
+	if certInfo.ModTime().Equal(c.certModTime) && keyInfo.ModTime().Equal(c.keyModTime) {
+		return c.cert, nil
+	}
+	if err := c.reloadLocked(certInfo, keyInfo); err != nil {
+		// do not retry until the files change again
+		c.certModTime, c.keyModTime = certInfo.ModTime(), keyInfo.ModTime()
+		c.logger.WarnContext(context.Background(), fmt.Sprintf("unable to reload TLS certificate, keeping the previous certificate: %s", err))
+		return c.cert, nil
+	}
+	c.logger.InfoContext(context.Background(), "reloaded TLS certificate")
+	return c.cert, nil
+}
+
+func (c *certReloader) reload() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	certInfo, err := os.Stat(c.certFile)
+	if err != nil {
+		return fmt.Errorf("unable to read TLS certificate file: %w", err)
+	}
+	keyInfo, err := os.Stat(c.keyFile)
+	if err != nil {
+		return fmt.Errorf("unable to read TLS key file: %w", err)
+	}
+	return c.reloadLocked(certInfo, keyInfo)
+}
+
+func (c *certReloader) reloadLocked(certInfo, keyInfo os.FileInfo) error {
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return fmt.Errorf("unable to load TLS certificate: %w", err)
+	}
+	c.cert, c.certModTime, c.keyModTime = &cert, certInfo.ModTime(), keyInfo.ModTime()
+	return nil
+}
+
+// tlsConnectionState makes the TLS connection state of requests, including
+// the verified client certificates, available to auth services.
+func tlsConnectionState(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS != nil {
+			r = r.WithContext(util.WithTLSConnectionState(r.Context(), r.TLS))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
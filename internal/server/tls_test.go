@@ -0,0 +1,240 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/auth/mtls"
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+)
+
+// writeFile writes content to a file of dir and sets its modification time.
+func writeFile(t *testing.T, dir, name string, content []byte, modTime time.Time) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatalf("unable to write %s: %s", name, err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("unable to set modification time of %s: %s", name, err)
+	}
+	return path
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "warn")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	ca := testutils.NewCertificateAuthority(t)
+	serverCert := ca.Issue(t, &x509.Certificate{Subject: pkix.Name{CommonName: "toolbox"}, DNSNames: []string{"localhost"}})
+	dir := t.TempDir()
+	certFile := writeFile(t, dir, "tls.crt", serverCert.CertPEM, time.Now())
+	keyFile := writeFile(t, dir, "tls.key", serverCert.KeyPEM, time.Now())
+	caFile := writeFile(t, dir, "ca.crt", ca.CertPEM, time.Now())
+
+	tcs := []struct {
+		desc           string
+		cfg            ServerConfig
+		wantTLS        bool
+		wantClientAuth tls.ClientAuthType
+		err            string
+	}{
+		{
+			desc: "disabled",
+		},
+		{
+			desc:    "server certificate",
+			cfg:     ServerConfig{TLSCertFile: certFile, TLSKeyFile: keyFile},
+			wantTLS: true,
+		},
+		{
+			desc:           "optional client certificates",
+			cfg:            ServerConfig{TLSCertFile: certFile, TLSKeyFile: keyFile, TLSClientCAFile: caFile},
+			wantTLS:        true,
+			wantClientAuth: tls.VerifyClientCertIfGiven,
+		},
+		{
+			desc:           "required client certificates",
+			cfg:            ServerConfig{TLSCertFile: certFile, TLSKeyFile: keyFile, TLSClientCAFile: caFile, TLSRequireClientCert: true},
+			wantTLS:        true,
+			wantClientAuth: tls.RequireAndVerifyClientCert,
+		},
+		{
+			desc: "missing key file",
+			cfg:  ServerConfig{TLSCertFile: certFile},
+			err:  "`--tls-cert-file` and `--tls-key-file` must be set together",
+		},
+		{
+			desc: "client CA without server certificate",
+			cfg:  ServerConfig{TLSClientCAFile: caFile},
+			err:  "client certificates require `--tls-cert-file` and `--tls-key-file`",
+		},
+		{
+			desc: "required client certificates without CA",
+			cfg:  ServerConfig{TLSCertFile: certFile, TLSKeyFile: keyFile, TLSRequireClientCert: true},
+			err:  "`--tls-require-client-cert` requires `--tls-client-ca-file`",
+		},
+		{
+			desc: "invalid client CA file",
+			cfg:  ServerConfig{TLSCertFile: certFile, TLSKeyFile: keyFile, TLSClientCAFile: keyFile},
+			err:  "no PEM encoded certificates found in client CA file",
+		},
+		{
+			desc: "mismatched key",
+			cfg:  ServerConfig{TLSCertFile: certFile, TLSKeyFile: writeFile(t, dir, "other.key", ca.KeyPEM, time.Now())},
+			err:  "unable to load TLS certificate",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := newTLSConfig(tc.cfg, testLogger)
+			if tc.err != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tc.err) {
+					t.Fatalf("unexpected error: got %v, want %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if (got != nil) != tc.wantTLS {
+				t.Fatalf("unexpected TLS config: got %v, want TLS %t", got, tc.wantTLS)
+			}
+			if got != nil && got.ClientAuth != tc.wantClientAuth {
+				t.Fatalf("unexpected client auth: got %v, want %v", got.ClientAuth, tc.wantClientAuth)
+			}
+		})
+	}
+}
+
+func TestTLSServing(t *testing.T) {
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "warn")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	ca := testutils.NewCertificateAuthority(t)
+	serverCert := ca.Issue(t, &x509.Certificate{Subject: pkix.Name{CommonName: "toolbox"}, IPAddresses: []net.IP{net.ParseIP("127.0.0.1")}})
+	spiffeID, _ := url.Parse("spiffe://example.org/ns/data/sa/reporting")
+	clientCert := ca.Issue(t, &x509.Certificate{Subject: pkix.Name{CommonName: "reporting"}, URIs: []*url.URL{spiffeID}})
+
+	dir := t.TempDir()
+	start := time.Now().Add(-time.Minute)
+	certFile := writeFile(t, dir, "tls.crt", serverCert.CertPEM, start)
+	keyFile := writeFile(t, dir, "tls.key", serverCert.KeyPEM, start)
+	caFile := writeFile(t, dir, "ca.crt", ca.CertPEM, start)
+	tlsConfig, err := newTLSConfig(ServerConfig{TLSCertFile: certFile, TLSKeyFile: keyFile, TLSClientCAFile: caFile}, testLogger)
+	if err != nil {
+		t.Fatalf("unable to initialize TLS config: %s", err)
+	}
+
+	authService, err := mtls.Config{Name: "my-mtls", Type: mtls.AuthServiceType}.Initialize()
+	if err != nil {
+		t.Fatalf("unable to initialize auth service: %s", err)
+	}
+	handler := tlsConnectionState(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, err := authService.GetClaimsFromHeader(r.Context(), r.Header)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if claims == nil {
+			_, _ = w.Write([]byte("anonymous"))
+			return
+		}
+		_, _ = w.Write([]byte(claims["spiffeId"].(string)))
+	}))
+	// serve like Server.Serve, since httptest.Server sets its own certificate
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to open listener: %s", err)
+	}
+	srv := &http.Server{Handler: handler, TLSConfig: tlsConfig}
+	go func() { _ = srv.ServeTLS(ln, "", "") }()
+	defer srv.Close()
+	serverURL := "https://" + ln.Addr().String()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Cert)
+	get := func(t *testing.T, certs []tls.Certificate) (string, *x509.Certificate) {
+		t.Helper()
+		client := &http.Client{Transport: &http.Transport{
+			TLSClientConfig:   &tls.Config{RootCAs: roots, Certificates: certs},
+			DisableKeepAlives: true,
+		}}
+		resp, err := client.Get(serverURL)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("unable to read body: %s", err)
+		}
+		return string(body), resp.TLS.PeerCertificates[0]
+	}
+	keyPair := func(t *testing.T, c testutils.Certificate) tls.Certificate {
+		t.Helper()
+		kp, err := tls.X509KeyPair(c.CertPEM, c.KeyPEM)
+		if err != nil {
+			t.Fatalf("unable to load key pair: %s", err)
+		}
+		return kp
+	}
+
+	t.Run("without client certificate", func(t *testing.T) {
+		if got, _ := get(t, nil); got != "anonymous" {
+			t.Fatalf("unexpected response: %q", got)
+		}
+	})
+
+	t.Run("with client certificate", func(t *testing.T) {
+		if got, _ := get(t, []tls.Certificate{keyPair(t, clientCert)}); got != spiffeID.String() {
+			t.Fatalf("unexpected response: %q", got)
+		}
+	})
+
+	t.Run("reloads server certificate", func(t *testing.T) {
+		rotated := ca.Issue(t, &x509.Certificate{Subject: pkix.Name{CommonName: "toolbox"}, IPAddresses: []net.IP{net.ParseIP("127.0.0.1")}})
+		writeFile(t, dir, "tls.crt", rotated.CertPEM, time.Now())
+		writeFile(t, dir, "tls.key", rotated.KeyPEM, time.Now())
+		_, got := get(t, nil)
+		if got.SerialNumber.Cmp(rotated.Cert.SerialNumber) != 0 {
+			t.Fatalf("unexpected server certificate: got serial %s, want %s", got.SerialNumber, rotated.Cert.SerialNumber)
+		}
+	})
+
+	t.Run("keeps server certificate if reload fails", func(t *testing.T) {
+		_, before := get(t, nil)
+		writeFile(t, dir, "tls.key", []byte("not a key"), time.Now().Add(time.Minute))
+		_, got := get(t, nil)
+		if got.SerialNumber.Cmp(before.SerialNumber) != 0 {
+			t.Fatalf("unexpected server certificate: got serial %s, want %s", got.SerialNumber, before.SerialNumber)
+		}
+	})
+}
@@ -23,6 +23,7 @@ import (
 	"cloud.google.com/go/alloydbconn"
 	"github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/postgres"
 	"github.com/googleapis/genai-toolbox/internal/util"
 	"github.com/googleapis/genai-toolbox/internal/util/orderedmap"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -59,6 +60,8 @@ type Config struct {
 	User     string         `yaml:"user"`
 	Password string         `yaml:"password"`
 	Database string         `yaml:"database" validate:"required"`
+	// SessionIdentity runs each invocation as the caller, if set.
+	SessionIdentity *sources.SessionIdentity `yaml:"sessionIdentity"`
 }
 
 func (r Config) SourceConfigType() string {
@@ -98,12 +101,22 @@ func (s *Source) ToConfig() sources.SourceConfig {
 	return s.Config
 }
 
+func (s *Source) GetSessionIdentity() *sources.SessionIdentity {
+	return s.SessionIdentity
+}
+
 func (s *Source) PostgresPool() *pgxpool.Pool {
 	return s.Pool
 }
 
 func (s *Source) RunSQL(ctx context.Context, statement string, params []any) (any, error) {
-	results, err := s.Pool.Query(ctx, statement, params...)
+	return postgres.RunInSession(ctx, s.PostgresPool(), s.SessionIdentity, func(q postgres.Querier) (any, error) {
+		return runSQL(ctx, q, statement, params)
+	})
+}
+
+func runSQL(ctx context.Context, q postgres.Querier, statement string, params []any) (any, error) {
+	results, err := q.Query(ctx, statement, params...)
 	if err != nil {
 		return nil, fmt.Errorf("unable to execute query: %w", err)
 	}
@@ -73,7 +73,12 @@ func (c *Cache) delete(key string, item Item) {
 // Set adds an item to the cache
 func (c *Cache) Set(key string, value any) {
 	const ttl = 55 * time.Minute
-	expires := time.Now().Add(ttl).UnixNano()
+	c.SetWithExpiry(key, value, time.Now().Add(ttl))
+}
+
+// SetWithExpiry adds an item to the cache that expires at expiresAt
+func (c *Cache) SetWithExpiry(key string, value any, expiresAt time.Time) {
+	expires := expiresAt.UnixNano()
 
 	c.mu.Lock()
 	defer c.mu.Unlock()
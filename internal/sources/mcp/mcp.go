@@ -0,0 +1,339 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const SourceType string = "mcp"
+
+// protocolVersion is the MCP protocol version requested from upstream
+// servers.
+const protocolVersion = "2025-06-18"
+
+// validate interface
+var _ sources.SourceConfig = Config{}
+
+func init() {
+	if !sources.Register(SourceType, newConfig) {
+		panic(fmt.Sprintf("source type %q already registered", SourceType))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources.SourceConfig, error) {
+	actual := Config{Name: name, Timeout: "30s"} // Default timeout
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type Config struct {
+	Name string `yaml:"name" validate:"required"`
+	Type string `yaml:"type" validate:"required"`
+	// Command starts an upstream server that communicates over stdio.
+	Command string            `yaml:"command"`
+	Args    []string          `yaml:"args"`
+	Env     map[string]string `yaml:"env"`
+	// URL is the endpoint of an upstream server that communicates over
+	// streamable HTTP.
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers"`
+	Timeout string            `yaml:"timeout"`
+}
+
+func (r Config) SourceConfigType() string {
+	return SourceType
+}
+
+// Initialize connects to the upstream MCP server and retrieves its tools.
+func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.Source, error) {
+	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceType, r.Name)
+	defer span.End()
+
+	timeout, err := time.ParseDuration(r.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse Timeout string as time.Duration: %s", err)
+	}
+	if (r.Command == "") == (r.URL == "") {
+		return nil, fmt.Errorf("exactly one of `command` or `url` must be specified for source %q", r.Name)
+	}
+	if r.Command == "" && (len(r.Args) > 0 || len(r.Env) > 0) {
+		return nil, fmt.Errorf("`args` and `env` can only be specified with `command`")
+	}
+	if r.URL == "" && len(r.Headers) > 0 {
+		return nil, fmt.Errorf("`headers` can only be specified with `url`")
+	}
+
+	// the user agent, e.g. "genai-toolbox/1.0.0", identifies Toolbox to the
+	// upstream server
+	ua, err := util.UserAgentFromContext(ctx)
+	if err != nil {
+		ua = "genai-toolbox"
+	}
+	clientName, clientVersion, _ := strings.Cut(ua, "/")
+
+	s := &Source{
+		Config:     r,
+		timeout:    timeout,
+		clientInfo: map[string]any{"name": clientName, "version": clientVersion},
+	}
+	if r.Command != "" {
+		s.newTransport = func() (transport, error) {
+			return newStdioTransport(r.Command, r.Args, r.Env)
+		}
+	} else {
+		if _, err := url.ParseRequestURI(r.URL); err != nil {
+			return nil, fmt.Errorf("failed to parse url %v", err)
+		}
+		s.newTransport = func() (transport, error) {
+			return newHTTPTransport(r.URL, r.Headers, ua), nil
+		}
+	}
+	s.transport, err = s.newTransport()
+	if err != nil {
+		return nil, fmt.Errorf("unable to start upstream MCP server: %w", err)
+	}
+
+	if err := s.connect(ctx, s.transport); err != nil {
+		s.transport.close()
+		return nil, err
+	}
+	if err := s.listTools(ctx); err != nil {
+		s.transport.close()
+		return nil, err
+	}
+	return s, nil
+}
+
+var _ sources.Source = &Source{}
+var _ sources.Closer = &Source{}
+
+type Source struct {
+	Config
+	timeout    time.Duration
+	clientInfo map[string]any
+	// newTransport starts the upstream server, or connects to it
+	newTransport func() (transport, error)
+	// connectMu serializes reconnections to the upstream server and guards
+	// the transport
+	connectMu sync.Mutex
+	transport transport
+	closed    bool
+	tools     map[string]Tool
+}
+
+func (s *Source) SourceType() string {
+	return SourceType
+}
+
+func (s *Source) ToConfig() sources.SourceConfig {
+	return s.Config
+}
+
+// Close stops the upstream server started by the source.
+func (s *Source) Close() error {
+	s.connectMu.Lock()
+	defer s.connectMu.Unlock()
+	s.closed = true
+	s.transport.close()
+	return nil
+}
+
+// Schema is the subset of JSON Schema used by the input schemas of upstream
+// tools.
+type Schema struct {
+	// Type is either a type name or a list of type names.
+	Type        any                `json:"type,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+}
+
+// TypeName returns the type of the schema. For a list of types, the first
+// type other than "null" is returned.
+func (s Schema) TypeName() string {
+	switch t := s.Type.(type) {
+	case string:
+		return t
+	case []any:
+		for _, v := range t {
+			if name, ok := v.(string); ok && name != "null" {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// Tool is a tool of the upstream MCP server.
+type Tool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	InputSchema Schema                 `json:"inputSchema"`
+	Annotations *tools.ToolAnnotations `json:"annotations,omitempty"`
+}
+
+// CallToolResult is the result of a tool of the upstream MCP server.
+type CallToolResult struct {
+	Content           []map[string]any `json:"content"`
+	StructuredContent map[string]any   `json:"structuredContent,omitempty"`
+	IsError           bool             `json:"isError,omitempty"`
+}
+
+// McpTool returns the upstream tool with the given name.
+func (s *Source) McpTool(name string) (Tool, bool) {
+	tool, ok := s.tools[name]
+	return tool, ok
+}
+
+// CallTool invokes a tool of the upstream MCP server.
+func (s *Source) CallTool(ctx context.Context, name string, arguments map[string]any) (CallToolResult, error) {
+	t, err := s.currentTransport(ctx)
+	if err != nil {
+		return CallToolResult{}, err
+	}
+	params := map[string]any{"name": name, "arguments": arguments}
+	raw, err := s.request(ctx, t, "tools/call", params)
+	if errors.Is(err, errSessionExpired) {
+		// the upstream server ended the session, start a new one
+		if err := s.connect(ctx, t); err != nil {
+			return CallToolResult{}, err
+		}
+		raw, err = s.request(ctx, t, "tools/call", params)
+	}
+	if err != nil {
+		return CallToolResult{}, err
+	}
+	var result CallToolResult
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return CallToolResult{}, fmt.Errorf("invalid tools/call result from upstream MCP server: %w", err)
+	}
+	return result, nil
+}
+
+// currentTransport returns the transport to the upstream server. An upstream
+// server that exited is started again, a call that was in flight when it
+// exited is not retried since its effects are unknown.
+func (s *Source) currentTransport(ctx context.Context) (transport, error) {
+	s.connectMu.Lock()
+	defer s.connectMu.Unlock()
+	if s.closed {
+		return nil, fmt.Errorf("source %q is closed", s.Name)
+	}
+	if !s.transport.exited() {
+		return s.transport, nil
+	}
+
+	t, err := s.newTransport()
+	if err != nil {
+		return nil, fmt.Errorf("unable to restart upstream MCP server: %w", err)
+	}
+	if err := s.initialize(ctx, t); err != nil {
+		t.close()
+		return nil, err
+	}
+	s.transport.close()
+	s.transport = t
+	return t, nil
+}
+
+// request sends a request to the upstream server within the timeout of the
+// source.
+func (s *Source) request(ctx context.Context, t transport, method string, params any) (json.RawMessage, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return t.request(ctx, method, params)
+}
+
+// connect runs the initialization phase of the MCP lifecycle with the
+// upstream server.
+func (s *Source) connect(ctx context.Context, t transport) error {
+	s.connectMu.Lock()
+	defer s.connectMu.Unlock()
+	return s.initialize(ctx, t)
+}
+
+// initialize runs the initialization phase of the MCP lifecycle over the
+// transport, connectMu must be held.
+func (s *Source) initialize(ctx context.Context, t transport) error {
+	raw, err := s.request(ctx, t, "initialize", map[string]any{
+		"protocolVersion": protocolVersion,
+		"capabilities":    map[string]any{},
+		"clientInfo":      s.clientInfo,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to initialize upstream MCP server: %w", err)
+	}
+	var result struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return fmt.Errorf("invalid initialize result from upstream MCP server: %w", err)
+	}
+	t.setProtocolVersion(result.ProtocolVersion)
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	if err := t.notify(ctx, "notifications/initialized", nil); err != nil {
+		return fmt.Errorf("unable to initialize upstream MCP server: %w", err)
+	}
+	return nil
+}
+
+// listTools retrieves every page of the tools of the upstream server.
+func (s *Source) listTools(ctx context.Context) error {
+	s.tools = make(map[string]Tool)
+	cursor := ""
+	for {
+		params := map[string]any{}
+		if cursor != "" {
+			params["cursor"] = cursor
+		}
+		raw, err := s.request(ctx, s.transport, "tools/list", params)
+		if err != nil {
+			return fmt.Errorf("unable to list tools of upstream MCP server: %w", err)
+		}
+		var result struct {
+			Tools      []Tool `json:"tools"`
+			NextCursor string `json:"nextCursor"`
+		}
+		if err := json.Unmarshal(raw, &result); err != nil {
+			return fmt.Errorf("invalid tools/list result from upstream MCP server: %w", err)
+		}
+		for _, tool := range result.Tools {
+			s.tools[tool.Name] = tool
+		}
+		if result.NextCursor == "" {
+			return nil
+		}
+		cursor = result.NextCursor
+	}
+}
@@ -0,0 +1,429 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/mcp"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestParseFromYamlMcp(t *testing.T) {
+	tcs := []struct {
+		desc string
+		in   string
+		want server.SourceConfigs
+	}{
+		{
+			desc: "stdio example",
+			in: `
+			kind: sources
+			name: my-mcp-server
+			type: mcp
+			command: npx
+			args:
+				- -y
+				- "@modelcontextprotocol/server-everything"
+			env:
+				DEBUG: "true"
+			`,
+			want: map[string]sources.SourceConfig{
+				"my-mcp-server": mcp.Config{
+					Name:    "my-mcp-server",
+					Type:    mcp.SourceType,
+					Command: "npx",
+					Args:    []string{"-y", "@modelcontextprotocol/server-everything"},
+					Env:     map[string]string{"DEBUG": "true"},
+					Timeout: "30s",
+				},
+			},
+		},
+		{
+			desc: "streamable http example",
+			in: `
+			kind: sources
+			name: my-mcp-server
+			type: mcp
+			url: http://test_server/mcp
+			headers:
+				Authorization: Bearer token
+			timeout: 10s
+			`,
+			want: map[string]sources.SourceConfig{
+				"my-mcp-server": mcp.Config{
+					Name:    "my-mcp-server",
+					Type:    mcp.SourceType,
+					URL:     "http://test_server/mcp",
+					Headers: map[string]string{"Authorization": "Bearer token"},
+					Timeout: "10s",
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, _, _, _, _, _, err := server.UnmarshalResourceConfig(context.Background(), testutils.FormatYaml(tc.in))
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if !cmp.Equal(tc.want, got) {
+				t.Fatalf("incorrect parse: want %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestFailParseFromYaml(t *testing.T) {
+	tcs := []struct {
+		desc string
+		in   string
+		err  string
+	}{
+		{
+			desc: "extra field",
+			in: `
+			kind: sources
+			name: my-mcp-server
+			type: mcp
+			url: http://test_server/mcp
+			project: test-project
+			`,
+			err: "error unmarshaling sources: unable to parse source \"my-mcp-server\" as \"mcp\": [2:1] unknown field \"project\"\n   1 | name: my-mcp-server\n>  2 | project: test-project\n       ^\n   3 | type: mcp\n   4 | url: http://test_server/mcp",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, _, _, _, _, _, err := server.UnmarshalResourceConfig(context.Background(), testutils.FormatYaml(tc.in))
+			if err == nil {
+				t.Fatalf("expect parsing to fail")
+			}
+			errStr := err.Error()
+			if errStr != tc.err {
+				t.Fatalf("unexpected error: got %q, want %q", errStr, tc.err)
+			}
+		})
+	}
+}
+
+func TestFailInitialize(t *testing.T) {
+	tcs := []struct {
+		desc string
+		cfg  mcp.Config
+		err  string
+	}{
+		{
+			desc: "no transport",
+			cfg:  mcp.Config{Name: "my-mcp-server", Type: mcp.SourceType, Timeout: "30s"},
+			err:  "exactly one of `command` or `url` must be specified for source \"my-mcp-server\"",
+		},
+		{
+			desc: "both transports",
+			cfg:  mcp.Config{Name: "my-mcp-server", Type: mcp.SourceType, Command: "server", URL: "http://test_server/mcp", Timeout: "30s"},
+			err:  "exactly one of `command` or `url` must be specified for source \"my-mcp-server\"",
+		},
+		{
+			desc: "args with url",
+			cfg:  mcp.Config{Name: "my-mcp-server", Type: mcp.SourceType, URL: "http://test_server/mcp", Args: []string{"-v"}, Timeout: "30s"},
+			err:  "`args` and `env` can only be specified with `command`",
+		},
+		{
+			desc: "headers with command",
+			cfg:  mcp.Config{Name: "my-mcp-server", Type: mcp.SourceType, Command: "server", Headers: map[string]string{"a": "b"}, Timeout: "30s"},
+			err:  "`headers` can only be specified with `url`",
+		},
+		{
+			desc: "invalid timeout",
+			cfg:  mcp.Config{Name: "my-mcp-server", Type: mcp.SourceType, URL: "http://test_server/mcp", Timeout: "soon"},
+			err:  "unable to parse Timeout string as time.Duration: time: invalid duration \"soon\"",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := tc.cfg.Initialize(context.Background(), noop.NewTracerProvider().Tracer(""))
+			if err == nil {
+				t.Fatalf("expect initialization to fail")
+			}
+			if err.Error() != tc.err {
+				t.Fatalf("unexpected error: got %q, want %q", err.Error(), tc.err)
+			}
+		})
+	}
+}
+
+// fakeUpstream answers the requests of the source the way an upstream MCP
+// server would. Its tools are listed over two pages.
+func fakeUpstream(method string, params json.RawMessage) (any, map[string]any) {
+	switch method {
+	case "initialize":
+		return map[string]any{
+			"protocolVersion": "2025-06-18",
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]any{"name": "fake", "version": "1.0.0"},
+		}, nil
+	case "tools/list":
+		var p struct {
+			Cursor string `json:"cursor"`
+		}
+		_ = json.Unmarshal(params, &p)
+		if p.Cursor == "" {
+			return map[string]any{
+				"tools": []any{map[string]any{
+					"name":        "echo",
+					"description": "Echoes the message.",
+					"inputSchema": map[string]any{
+						"type":       "object",
+						"properties": map[string]any{"message": map[string]any{"type": "string"}},
+						"required":   []any{"message"},
+					},
+				}},
+				"nextCursor": "page2",
+			}, nil
+		}
+		return map[string]any{
+			"tools": []any{map[string]any{
+				"name":        "fail",
+				"inputSchema": map[string]any{"type": "object"},
+				"annotations": map[string]any{"readOnlyHint": true},
+			}},
+		}, nil
+	case "tools/call":
+		var p struct {
+			Name      string         `json:"name"`
+			Arguments map[string]any `json:"arguments"`
+		}
+		_ = json.Unmarshal(params, &p)
+		if p.Name == "fail" {
+			return map[string]any{"content": []any{map[string]any{"type": "text", "text": "it failed"}}, "isError": true}, nil
+		}
+		if p.Name == "echo" {
+			return map[string]any{"content": []any{map[string]any{"type": "text", "text": fmt.Sprint(p.Arguments["message"])}}}, nil
+		}
+		return nil, map[string]any{"code": -32602, "message": "unknown tool"}
+	}
+	return nil, map[string]any{"code": -32601, "message": "method not found"}
+}
+
+// respond builds the response to a request of the source.
+func respond(body []byte) (map[string]any, bool) {
+	var req struct {
+		Id     any             `json:"id"`
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil || req.Id == nil {
+		return nil, false
+	}
+	result, rpcErr := fakeUpstream(req.Method, req.Params)
+	resp := map[string]any{"jsonrpc": "2.0", "id": req.Id}
+	if rpcErr != nil {
+		resp["error"] = rpcErr
+	} else {
+		resp["result"] = result
+	}
+	return resp, true
+}
+
+func checkSource(t *testing.T, s sources.Source) {
+	t.Helper()
+	src, ok := s.(*mcp.Source)
+	if !ok {
+		t.Fatalf("unexpected source: %T", s)
+	}
+	echo, ok := src.McpTool("echo")
+	if !ok {
+		t.Fatalf("tool echo not found")
+	}
+	if echo.Description != "Echoes the message." || echo.InputSchema.Properties["message"].TypeName() != "string" {
+		t.Fatalf("unexpected tool: %+v", echo)
+	}
+	fail, ok := src.McpTool("fail")
+	if !ok {
+		t.Fatalf("tool fail from the second page not found")
+	}
+	if fail.Annotations == nil || fail.Annotations.ReadOnlyHint == nil || !*fail.Annotations.ReadOnlyHint {
+		t.Fatalf("unexpected annotations: %+v", fail.Annotations)
+	}
+
+	result, err := src.CallTool(context.Background(), "echo", map[string]any{"message": "hello"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := mcp.CallToolResult{Content: []map[string]any{{"type": "text", "text": "hello"}}}
+	if diff := cmp.Diff(want, result); diff != "" {
+		t.Fatalf("unexpected result (-want +got):\n%s", diff)
+	}
+
+	result, err = src.CallTool(context.Background(), "fail", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !result.IsError {
+		t.Fatalf("expected an error result, got %+v", result)
+	}
+
+	_, err = src.CallTool(context.Background(), "unknown", nil)
+	if err == nil || !strings.Contains(err.Error(), "unknown tool") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestStreamableHTTP(t *testing.T) {
+	for _, stream := range []bool{false, true} {
+		t.Run(fmt.Sprintf("stream=%t", stream), func(t *testing.T) {
+			var mu sync.Mutex
+			sessions := 0
+			session := ""
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") != "Bearer token" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				var body json.RawMessage
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				if strings.Contains(string(body), `"initialize"`) {
+					sessions++
+					session = fmt.Sprintf("session-%d", sessions)
+					w.Header().Set("Mcp-Session-Id", session)
+				} else if r.Header.Get("Mcp-Session-Id") != session {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				} else if r.Header.Get("MCP-Protocol-Version") != "2025-06-18" {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+
+				resp, ok := respond(body)
+				if !ok {
+					w.WriteHeader(http.StatusAccepted)
+					return
+				}
+				b, _ := json.Marshal(resp)
+				if !stream {
+					w.Header().Set("Content-Type", "application/json")
+					_, _ = w.Write(b)
+					return
+				}
+				w.Header().Set("Content-Type", "text/event-stream")
+				// a notification precedes the response
+				fmt.Fprintf(w, "event: message\ndata: %s\n\n", `{"jsonrpc":"2.0","method":"notifications/message","params":{}}`)
+				fmt.Fprintf(w, "event: message\ndata: %s\n\n", b)
+			}))
+			defer server.Close()
+
+			cfg := mcp.Config{
+				Name:    "my-mcp-server",
+				Type:    mcp.SourceType,
+				URL:     server.URL,
+				Headers: map[string]string{"Authorization": "Bearer token"},
+				Timeout: "30s",
+			}
+			s, err := cfg.Initialize(context.Background(), noop.NewTracerProvider().Tracer(""))
+			if err != nil {
+				t.Fatalf("unable to initialize source: %s", err)
+			}
+			checkSource(t, s)
+
+			// the upstream server forgets the session, which is started again
+			mu.Lock()
+			session = "expired"
+			mu.Unlock()
+			src := s.(*mcp.Source)
+			if _, err := src.CallTool(context.Background(), "echo", map[string]any{"message": "again"}); err != nil {
+				t.Fatalf("unexpected error after session expiry: %s", err)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if sessions != 2 {
+				t.Fatalf("expected 2 sessions, got %d", sessions)
+			}
+		})
+	}
+}
+
+func TestStdio(t *testing.T) {
+	cfg := mcp.Config{
+		Name:    "my-mcp-server",
+		Type:    mcp.SourceType,
+		Command: os.Args[0],
+		Args:    []string{"-test.run=TestHelperProcess"},
+		Env:     map[string]string{"GO_WANT_HELPER_PROCESS": "1"},
+		Timeout: "30s",
+	}
+	s, err := cfg.Initialize(context.Background(), noop.NewTracerProvider().Tracer(""))
+	if err != nil {
+		t.Fatalf("unable to initialize source: %s", err)
+	}
+	checkSource(t, s)
+
+	// the upstream server exits during a call, and is started again for the
+	// next one
+	src := s.(*mcp.Source)
+	_, err = src.CallTool(context.Background(), "exit", nil)
+	if err == nil || !strings.Contains(err.Error(), "upstream MCP server exited") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := src.CallTool(context.Background(), "echo", map[string]any{"message": "again"}); err != nil {
+		t.Fatalf("unexpected error after the upstream server exited: %s", err)
+	}
+
+	if err := src.Close(); err != nil {
+		t.Fatalf("unable to close source: %s", err)
+	}
+	_, err = src.CallTool(context.Background(), "echo", map[string]any{"message": "closed"})
+	if err == nil || !strings.Contains(err.Error(), "is closed") {
+		t.Fatalf("unexpected error after close: %v", err)
+	}
+}
+
+// TestHelperProcess is not a real test. It runs as the upstream MCP server of
+// TestStdio.
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		// the exit tool stops the server without a response
+		if strings.Contains(scanner.Text(), `"name":"exit"`) {
+			os.Exit(0)
+		}
+		resp, ok := respond(scanner.Bytes())
+		if !ok {
+			continue
+		}
+		b, _ := json.Marshal(resp)
+		fmt.Printf("%s\n", b)
+	}
+	os.Exit(0)
+}
@@ -0,0 +1,381 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/googleapis/genai-toolbox/internal/server/mcp/jsonrpc"
+)
+
+// errSessionExpired is returned when the upstream server no longer knows the
+// session, which must then be initialized again.
+var errSessionExpired = errors.New("upstream MCP session expired")
+
+// errServerExited is returned when the subprocess of the upstream server has
+// exited, which must then be started again.
+var errServerExited = errors.New("upstream MCP server exited")
+
+// transport exchanges JSON-RPC messages with an upstream MCP server.
+type transport interface {
+	// request sends a request and returns the result of its response.
+	request(ctx context.Context, method string, params any) (json.RawMessage, error)
+	// notify sends a notification.
+	notify(ctx context.Context, method string, params any) error
+	// setProtocolVersion sets the protocol version negotiated on
+	// initialization.
+	setProtocolVersion(version string)
+	// exited reports whether the upstream server stopped and must be started
+	// again.
+	exited() bool
+	close()
+}
+
+// message is a JSON-RPC message sent to the upstream server.
+type message struct {
+	Jsonrpc string `json:"jsonrpc"`
+	Id      *int64 `json:"id,omitempty"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+// incomingMessage is a JSON-RPC message received from the upstream server:
+// a response, a notification or a request.
+type incomingMessage struct {
+	Id     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *jsonrpc.Error  `json:"error,omitempty"`
+}
+
+// key returns the key used to match a response with its request.
+func (m incomingMessage) key() string {
+	return string(bytes.TrimSpace(m.Id))
+}
+
+// value returns the result of a response, or its error.
+func (m incomingMessage) value() (json.RawMessage, error) {
+	if m.Error != nil {
+		return nil, fmt.Errorf("upstream MCP server returned error %d: %s", m.Error.Code, m.Error.Message)
+	}
+	return m.Result, nil
+}
+
+/* stdio */
+
+// stdioTransport exchanges newline-delimited messages with an upstream server
+// started as a subprocess.
+type stdioTransport struct {
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	nextId  int64
+	pending map[string]chan incomingMessage
+	// done is closed once the subprocess stops writing to stdout
+	done chan struct{}
+}
+
+func newStdioTransport(command string, args []string, env map[string]string) (*stdioTransport, error) {
+	cmd := exec.Command(command, args...)
+	cmd.Env = os.Environ()
+	for k, v := range env {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
+	}
+	// logs of the upstream server are passed through
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	t := &stdioTransport{
+		cmd:     cmd,
+		stdin:   stdin,
+		pending: make(map[string]chan incomingMessage),
+		done:    make(chan struct{}),
+	}
+	go t.readMessages(stdout)
+	return t, nil
+}
+
+// readMessages passes the responses of the upstream server to the requests
+// waiting for them until stdout is closed.
+func (t *stdioTransport) readMessages(stdout io.Reader) {
+	defer close(t.done)
+	reader := bufio.NewReader(stdout)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			t.dispatch(line)
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (t *stdioTransport) dispatch(line []byte) {
+	var msg incomingMessage
+	if err := json.Unmarshal(line, &msg); err != nil {
+		return
+	}
+	if msg.Method != "" {
+		// requests of the upstream server are not supported, notifications
+		// are ignored
+		if msg.Id != nil {
+			_ = t.write(map[string]any{
+				"jsonrpc": jsonrpc.JSONRPC_VERSION,
+				"id":      msg.Id,
+				"error":   jsonrpc.Error{Code: jsonrpc.METHOD_NOT_FOUND, Message: "method not found"},
+			})
+		}
+		return
+	}
+	t.mu.Lock()
+	responses, ok := t.pending[msg.key()]
+	delete(t.pending, msg.key())
+	t.mu.Unlock()
+	if ok {
+		responses <- msg
+	}
+}
+
+func (t *stdioTransport) write(v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	_, err = fmt.Fprintf(t.stdin, "%s\n", b)
+	return err
+}
+
+func (t *stdioTransport) request(ctx context.Context, method string, params any) (json.RawMessage, error) {
+	responses := make(chan incomingMessage, 1)
+	t.mu.Lock()
+	t.nextId++
+	id := t.nextId
+	key := strconv.FormatInt(id, 10)
+	t.pending[key] = responses
+	t.mu.Unlock()
+	defer func() {
+		t.mu.Lock()
+		delete(t.pending, key)
+		t.mu.Unlock()
+	}()
+
+	if err := t.write(message{Jsonrpc: jsonrpc.JSONRPC_VERSION, Id: &id, Method: method, Params: params}); err != nil {
+		if t.exited() {
+			return nil, errServerExited
+		}
+		return nil, fmt.Errorf("unable to send %s request: %w", method, err)
+	}
+	select {
+	case msg := <-responses:
+		return msg.value()
+	case <-t.done:
+		return nil, errServerExited
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (t *stdioTransport) notify(_ context.Context, method string, params any) error {
+	return t.write(message{Jsonrpc: jsonrpc.JSONRPC_VERSION, Method: method, Params: params})
+}
+
+func (t *stdioTransport) setProtocolVersion(string) {}
+
+func (t *stdioTransport) exited() bool {
+	select {
+	case <-t.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (t *stdioTransport) close() {
+	_ = t.stdin.Close()
+	_ = t.cmd.Process.Kill()
+	_ = t.cmd.Wait()
+}
+
+/* streamable HTTP */
+
+// httpTransport posts messages to an upstream server using the streamable
+// HTTP transport.
+type httpTransport struct {
+	url       string
+	headers   map[string]string
+	userAgent string
+	client    *http.Client
+
+	mu              sync.Mutex
+	nextId          int64
+	sessionId       string
+	protocolVersion string
+}
+
+func newHTTPTransport(url string, headers map[string]string, userAgent string) *httpTransport {
+	return &httpTransport{
+		url:       url,
+		headers:   headers,
+		userAgent: userAgent,
+		client:    &http.Client{},
+	}
+}
+
+// post sends a message and returns the HTTP response.
+func (t *httpTransport) post(ctx context.Context, msg message) (*http.Response, error) {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	if t.userAgent != "" {
+		req.Header.Set("User-Agent", t.userAgent)
+	}
+
+	t.mu.Lock()
+	sessionId, protocolVersion := t.sessionId, t.protocolVersion
+	t.mu.Unlock()
+	if sessionId != "" {
+		req.Header.Set("Mcp-Session-Id", sessionId)
+	}
+	if protocolVersion != "" {
+		req.Header.Set("MCP-Protocol-Version", protocolVersion)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making HTTP request: %w", err)
+	}
+	if resp.StatusCode == http.StatusNotFound && sessionId != "" {
+		resp.Body.Close()
+		return nil, errSessionExpired
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("unexpected status code: %d, response body: %s", resp.StatusCode, string(body))
+	}
+	return resp, nil
+}
+
+func (t *httpTransport) request(ctx context.Context, method string, params any) (json.RawMessage, error) {
+	t.mu.Lock()
+	if method == "initialize" {
+		// a new session is started on initialization
+		t.sessionId = ""
+		t.protocolVersion = ""
+	}
+	t.nextId++
+	id := t.nextId
+	t.mu.Unlock()
+
+	resp, err := t.post(ctx, message{Jsonrpc: jsonrpc.JSONRPC_VERSION, Id: &id, Method: method, Params: params})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if sessionId := resp.Header.Get("Mcp-Session-Id"); sessionId != "" && method == "initialize" {
+		t.mu.Lock()
+		t.sessionId = sessionId
+		t.mu.Unlock()
+	}
+
+	key := strconv.FormatInt(id, 10)
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/event-stream" {
+		var msg incomingMessage
+		if err := json.NewDecoder(resp.Body).Decode(&msg); err != nil {
+			return nil, fmt.Errorf("invalid response to %s request: %w", method, err)
+		}
+		return msg.value()
+	}
+
+	// the response is the event of the stream that matches the request,
+	// other messages are ignored
+	reader := bufio.NewReader(resp.Body)
+	var data strings.Builder
+	for {
+		line, err := reader.ReadString('\n')
+		line = strings.TrimRight(line, "\r\n")
+		if d, ok := strings.CutPrefix(line, "data:"); ok {
+			data.WriteString(strings.TrimPrefix(d, " "))
+		} else if line == "" && data.Len() > 0 {
+			var msg incomingMessage
+			if jsonErr := json.Unmarshal([]byte(data.String()), &msg); jsonErr == nil && msg.Method == "" && msg.key() == key {
+				return msg.value()
+			}
+			data.Reset()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("stream ended without a response to %s request: %w", method, err)
+		}
+	}
+}
+
+func (t *httpTransport) notify(ctx context.Context, method string, params any) error {
+	resp, err := t.post(ctx, message{Jsonrpc: jsonrpc.JSONRPC_VERSION, Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (t *httpTransport) setProtocolVersion(version string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.protocolVersion = version
+}
+
+func (t *httpTransport) exited() bool {
+	return false
+}
+
+func (t *httpTransport) close() {}
@@ -79,6 +79,34 @@ func TestParseFromYamlCloudSQLMySQL(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "with session identity",
+			in: `
+			kind: sources
+			name: my-mysql-instance
+			type: mysql
+			host: 0.0.0.0
+			port: my-port
+			sessionIdentity:
+				authService: my-google-auth
+				roleClaim: db_role
+				variables:
+					user_email: email
+			`,
+			want: map[string]sources.SourceConfig{
+				"my-mysql-instance": mysql.Config{
+					Name: "my-mysql-instance",
+					Type: mysql.SourceType,
+					Host: "0.0.0.0",
+					Port: "my-port",
+					SessionIdentity: &sources.SessionIdentity{
+						AuthService: "my-google-auth",
+						RoleClaim:   "db_role",
+						Variables:   map[string]string{"user_email": "email"},
+					},
+				},
+			},
+		},
 		{
 			desc: "with query params",
 			in: `
@@ -0,0 +1,102 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+)
+
+// Querier runs the queries of an invocation, on a pool or in a transaction.
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// RunInSession calls fn with the pool, or, if identity is set, with a
+// transaction that first sets the role and user variables of the caller. The
+// transaction is committed if fn succeeds.
+//
+// Unlike in PostgreSQL, the role and user variables of MySQL outlive the
+// transaction, so they are reset before the connection is returned to the
+// pool. A connection that can not be reset is discarded.
+func RunInSession(ctx context.Context, pool *sql.DB, identity *sources.SessionIdentity, fn func(Querier) (any, error)) (any, error) {
+	session, err := identity.Session(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return fn(pool)
+	}
+
+	conn, err := pool.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get connection: %w", err)
+	}
+	defer conn.Close()
+	defer resetSession(context.WithoutCancel(ctx), conn, session)
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	if session.Role != "" {
+		if _, err := tx.ExecContext(ctx, "SET ROLE "+quoteIdentifier(session.Role)); err != nil {
+			return nil, fmt.Errorf("unable to set role: %w", err)
+		}
+	}
+	for _, v := range session.Variables {
+		if _, err := tx.ExecContext(ctx, "SET @"+quoteIdentifier(v.Name)+" = ?", v.Value); err != nil {
+			return nil, fmt.Errorf("unable to set user variable %q: %w", v.Name, err)
+		}
+	}
+	out, err := fn(tx)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("unable to commit transaction: %w", err)
+	}
+	return out, nil
+}
+
+// resetSession resets the role and user variables of the connection, or
+// discards the connection if they can not be reset.
+func resetSession(ctx context.Context, conn *sql.Conn, session *sources.Session) {
+	statements := make([]string, 0, len(session.Variables)+1)
+	if session.Role != "" {
+		statements = append(statements, "SET ROLE DEFAULT")
+	}
+	for _, v := range session.Variables {
+		statements = append(statements, "SET @"+quoteIdentifier(v.Name)+" = NULL")
+	}
+	for _, stmt := range statements {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			// the pool closes connections that report driver.ErrBadConn
+			_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+			return
+		}
+	}
+}
+
+// quoteIdentifier quotes a role or variable name for MySQL.
+func quoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
@@ -58,6 +58,8 @@ type Config struct {
 	Database      string            `yaml:"database" validate:"required"`
 	QueryParams   map[string]string `yaml:"queryParams"`
 	QueryExecMode string            `yaml:"queryExecMode" validate:"omitempty,oneof=cache_statement cache_describe describe_exec exec simple_protocol"`
+	// SessionIdentity runs each invocation as the caller, if set.
+	SessionIdentity *sources.SessionIdentity `yaml:"sessionIdentity"`
 }
 
 func (r Config) SourceConfigType() string {
@@ -97,12 +99,22 @@ func (s *Source) ToConfig() sources.SourceConfig {
 	return s.Config
 }
 
+func (s *Source) GetSessionIdentity() *sources.SessionIdentity {
+	return s.SessionIdentity
+}
+
 func (s *Source) PostgresPool() *pgxpool.Pool {
 	return s.Pool
 }
 
 func (s *Source) RunSQL(ctx context.Context, statement string, params []any) (any, error) {
-	results, err := s.PostgresPool().Query(ctx, statement, params...)
+	return RunInSession(ctx, s.PostgresPool(), s.SessionIdentity, func(q Querier) (any, error) {
+		return runSQL(ctx, q, statement, params)
+	})
+}
+
+func runSQL(ctx context.Context, q Querier, statement string, params []any) (any, error) {
+	results, err := q.Query(ctx, statement, params...)
 	if err != nil {
 		return nil, fmt.Errorf("unable to execute query: %w", err)
 	}
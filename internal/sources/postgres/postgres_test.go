@@ -115,6 +115,40 @@ func TestParseFromYamlPostgres(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "example with session identity",
+			in: `
+			kind: sources
+			name: my-pg-instance
+			type: postgres
+			host: my-host
+			port: my-port
+			database: my_db
+			user: my_user
+			password: my_pass
+			sessionIdentity:
+				authService: my-google-auth
+				roleClaim: db_role
+				variables:
+					app.user_email: email
+			`,
+			want: map[string]sources.SourceConfig{
+				"my-pg-instance": postgres.Config{
+					Name:     "my-pg-instance",
+					Type:     postgres.SourceType,
+					Host:     "my-host",
+					Port:     "my-port",
+					Database: "my_db",
+					User:     "my_user",
+					Password: "my_pass",
+					SessionIdentity: &sources.SessionIdentity{
+						AuthService: "my-google-auth",
+						RoleClaim:   "db_role",
+						Variables:   map[string]string{"app.user_email": "email"},
+					},
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
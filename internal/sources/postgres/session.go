@@ -0,0 +1,62 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Querier runs the queries of an invocation, on a pool or in a transaction.
+type Querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// RunInSession calls fn with the pool, or, if identity is set, with a
+// transaction that first sets the role and session variables of the caller.
+// Both are scoped to the transaction, which is committed if fn succeeds.
+func RunInSession(ctx context.Context, pool *pgxpool.Pool, identity *sources.SessionIdentity, fn func(Querier) (any, error)) (any, error) {
+	session, err := identity.Session(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return fn(pool)
+	}
+	var out any
+	err = pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+		if session.Role != "" {
+			if _, err := tx.Exec(ctx, "SET LOCAL ROLE "+pgx.Identifier{session.Role}.Sanitize()); err != nil {
+				return fmt.Errorf("unable to set role: %w", err)
+			}
+		}
+		for _, v := range session.Variables {
+			if _, err := tx.Exec(ctx, "SELECT set_config($1, $2, true)", v.Name, v.Value); err != nil {
+				return fmt.Errorf("unable to set session variable %q: %w", v.Name, err)
+			}
+		}
+		var err error
+		out, err = fn(tx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,121 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+// SessionIdentity configures a SQL source to run each invocation as the
+// caller: in a transaction that sets the role and session variables of the
+// database from the claims verified by an auth service, so that grants and
+// row-level security of the database apply to the caller.
+type SessionIdentity struct {
+	// AuthService is the name of the auth service whose claims are used.
+	AuthService string `yaml:"authService" validate:"required"`
+	// RoleClaim is the claim that holds the database role of the caller. The
+	// role is not changed if empty.
+	RoleClaim string `yaml:"roleClaim"`
+	// Variables maps the names of session variables to the claims they are
+	// set to.
+	Variables map[string]string `yaml:"variables"`
+}
+
+// SessionVariable is a session variable set for an invocation.
+type SessionVariable struct {
+	Name  string
+	Value string
+}
+
+// Session is the role and session variables of an invocation.
+type Session struct {
+	// Role is empty if the role is not changed.
+	Role      string
+	Variables []SessionVariable
+}
+
+// Session returns the session of the invocation of ctx, or nil if s is nil.
+// It fails if the claims of the auth service, or any of the mapped claims,
+// were not verified, so that invocations never fall back to the role of the
+// source.
+func (s *SessionIdentity) Session(ctx context.Context) (*Session, error) {
+	if s == nil {
+		return nil, nil
+	}
+	claims, ok := util.AuthClaimsFromContext(ctx)[s.AuthService]
+	if !ok {
+		return nil, fmt.Errorf("invocation requires claims verified by auth service %q", s.AuthService)
+	}
+	session := &Session{}
+	if s.RoleClaim != "" {
+		role, err := claimValue(claims, s.RoleClaim)
+		if err != nil {
+			return nil, err
+		}
+		if role == "" {
+			return nil, fmt.Errorf("claim %q of the database role is empty", s.RoleClaim)
+		}
+		session.Role = role
+	}
+	names := make([]string, 0, len(s.Variables))
+	for name := range s.Variables {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		v, err := claimValue(claims, s.Variables[name])
+		if err != nil {
+			return nil, err
+		}
+		session.Variables = append(session.Variables, SessionVariable{Name: name, Value: v})
+	}
+	return session, nil
+}
+
+// claimValue returns a claim as the value of a session variable. Claims that
+// are not strings, such as lists, are JSON encoded.
+func claimValue(claims map[string]any, name string) (string, error) {
+	v, ok := claims[name]
+	if !ok {
+		return "", fmt.Errorf("claim %q is missing", name)
+	}
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("unable to encode claim %q: %w", name, err)
+	}
+	return string(b), nil
+}
+
+// SessionIdentitySource is implemented by sources that can run invocations as
+// the caller.
+type SessionIdentitySource interface {
+	GetSessionIdentity() *SessionIdentity
+}
+
+// UsesSessionIdentity reports whether the source runs invocations as the
+// caller. Tools that run arbitrary statements must not use such sources, since
+// the statements can change the role set for the caller.
+func UsesSessionIdentity(src Source) bool {
+	s, ok := src.(SessionIdentitySource)
+	return ok && s.GetSessionIdentity() != nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sources_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+func TestSessionIdentity(t *testing.T) {
+	identity := &sources.SessionIdentity{
+		AuthService: "my-auth",
+		RoleClaim:   "db_role",
+		Variables: map[string]string{
+			"app.user_email": "email",
+			"app.groups":     "groups",
+		},
+	}
+	tcs := []struct {
+		desc     string
+		identity *sources.SessionIdentity
+		claims   map[string]map[string]any
+		want     *sources.Session
+		err      string
+	}{
+		{
+			desc: "no session identity",
+		},
+		{
+			desc:     "role and variables",
+			identity: identity,
+			claims: map[string]map[string]any{
+				"my-auth": {"db_role": "analyst", "email": "alice@example.com", "groups": []any{"data", "eng"}},
+			},
+			want: &sources.Session{
+				Role: "analyst",
+				Variables: []sources.SessionVariable{
+					{Name: "app.groups", Value: `["data","eng"]`},
+					{Name: "app.user_email", Value: "alice@example.com"},
+				},
+			},
+		},
+		{
+			desc:     "variables only",
+			identity: &sources.SessionIdentity{AuthService: "my-auth", Variables: map[string]string{"app.sub": "sub"}},
+			claims:   map[string]map[string]any{"my-auth": {"sub": "1234"}},
+			want:     &sources.Session{Variables: []sources.SessionVariable{{Name: "app.sub", Value: "1234"}}},
+		},
+		{
+			desc:     "no claims",
+			identity: identity,
+			err:      `invocation requires claims verified by auth service "my-auth"`,
+		},
+		{
+			desc:     "claims of another auth service",
+			identity: identity,
+			claims:   map[string]map[string]any{"other-auth": {"db_role": "admin"}},
+			err:      `invocation requires claims verified by auth service "my-auth"`,
+		},
+		{
+			desc:     "missing claim",
+			identity: identity,
+			claims:   map[string]map[string]any{"my-auth": {"db_role": "analyst", "email": "alice@example.com"}},
+			err:      `claim "groups" is missing`,
+		},
+		{
+			desc:     "empty role",
+			identity: identity,
+			claims:   map[string]map[string]any{"my-auth": {"db_role": ""}},
+			err:      `claim "db_role" of the database role is empty`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.claims != nil {
+				ctx = util.WithAuthClaims(ctx, tc.claims)
+			}
+			got, err := tc.identity.Session(ctx)
+			if tc.err != "" {
+				if err == nil || err.Error() != tc.err {
+					t.Fatalf("unexpected error: got %v, want %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("unexpected session (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
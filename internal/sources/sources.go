@@ -16,6 +16,7 @@ package sources
 
 import (
 	"context"
+	"errors"
 
 	"fmt"
 
@@ -65,6 +66,27 @@ type Source interface {
 	ToConfig() SourceConfig
 }
 
+// Closer is implemented by sources that hold resources, such as subprocesses,
+// which must be released once the source is no longer used.
+type Closer interface {
+	Close() error
+}
+
+// CloseAll closes the sources that implement Closer.
+func CloseAll(sourcesMap map[string]Source) error {
+	var errs []error
+	for name, s := range sourcesMap {
+		c, ok := s.(Closer)
+		if !ok {
+			continue
+		}
+		if err := c.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("unable to close source %q: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // InitConnectionSpan adds a span for database pool connection initialization
 func InitConnectionSpan(ctx context.Context, tracer trace.Tracer, sourceType, sourceName string) (context.Context, trace.Span) {
 	ctx, span := tracer.Start(
@@ -0,0 +1,101 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testutils
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Certificate is a certificate issued in tests, with its PEM encoded key.
+type Certificate struct {
+	Cert    *x509.Certificate
+	CertPEM []byte
+	KeyPEM  []byte
+
+	key *ecdsa.PrivateKey
+}
+
+// CertificateAuthority issues certificates in tests.
+type CertificateAuthority struct {
+	Certificate
+	serial atomic.Int64
+}
+
+// NewCertificateAuthority returns a self-signed certificate authority.
+func NewCertificateAuthority(t testing.TB) *CertificateAuthority {
+	t.Helper()
+	ca := &CertificateAuthority{}
+	ca.Certificate = ca.sign(t, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil)
+	return ca
+}
+
+// Issue returns a certificate signed by the authority. The serial number
+// and validity of template are set by Issue.
+func (ca *CertificateAuthority) Issue(t testing.TB, template *x509.Certificate) Certificate {
+	t.Helper()
+	template.KeyUsage |= x509.KeyUsageDigitalSignature
+	if len(template.ExtKeyUsage) == 0 {
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+	}
+	return ca.sign(t, template, &ca.Certificate)
+}
+
+// sign signs template with parent, or self-signs it if parent is nil.
+func (ca *CertificateAuthority) sign(t testing.TB, template *x509.Certificate, parent *Certificate) Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unable to generate key: %s", err)
+	}
+	template.SerialNumber = big.NewInt(ca.serial.Add(1))
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+	parentCert, parentKey := template, key
+	if parent != nil {
+		parentCert, parentKey = parent.Cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parentCert, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatalf("unable to create certificate: %s", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("unable to parse certificate: %s", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("unable to marshal key: %s", err)
+	}
+	return Certificate{
+		Cert:    cert,
+		CertPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		KeyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+		key:     key,
+	}
+}
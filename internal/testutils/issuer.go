@@ -0,0 +1,138 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testutils
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	jose "github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+)
+
+// Issuer stands in for an authorization server that publishes its metadata
+// and its signing keys as a JSON Web Key Set.
+type Issuer struct {
+	*httptest.Server
+
+	mu    sync.Mutex
+	key   *rsa.PrivateKey
+	keyID string
+	// keyCount numbers the keys, so that rotated keys get a new key ID
+	keyCount int
+
+	jwksRequests atomic.Int32
+	// unavailable makes the keys fail to be fetched
+	unavailable atomic.Bool
+}
+
+// NewIssuer starts an Issuer that is closed when the test ends.
+func NewIssuer(t testing.TB) *Issuer {
+	t.Helper()
+	i := &Issuer{}
+	i.RotateKey(t)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"issuer":   i.URL,
+			"jwks_uri": i.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		i.jwksRequests.Add(1)
+		if i.unavailable.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		i.mu.Lock()
+		key := jose.JSONWebKey{Key: &i.key.PublicKey, KeyID: i.keyID, Algorithm: string(jose.RS256), Use: "sig"}
+		i.mu.Unlock()
+		_ = json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{key}})
+	})
+	i.Server = httptest.NewServer(mux)
+	t.Cleanup(i.Close)
+	return i
+}
+
+// RotateKey replaces the signing key of the issuer with a new key.
+func (i *Issuer) RotateKey(t testing.TB) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unable to generate key: %s", err)
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.keyCount++
+	i.key = key
+	i.keyID = fmt.Sprintf("key-%d", i.keyCount)
+}
+
+// SetUnavailable sets whether fetching the keys of the issuer fails.
+func (i *Issuer) SetUnavailable(unavailable bool) {
+	i.unavailable.Store(unavailable)
+}
+
+// JWKSRequests returns the number of times the keys of the issuer were
+// fetched.
+func (i *Issuer) JWKSRequests() int {
+	return int(i.jwksRequests.Load())
+}
+
+// Token returns a token with claims signed by the issuer. The `iss` and `exp`
+// claims default to the URL of the issuer and an hour from now.
+func (i *Issuer) Token(t testing.TB, claims map[string]any) string {
+	t.Helper()
+	i.mu.Lock()
+	key, keyID := i.key, i.keyID
+	i.mu.Unlock()
+	return SignToken(t, key, keyID, i.withDefaults(claims))
+}
+
+func (i *Issuer) withDefaults(claims map[string]any) map[string]any {
+	all := map[string]any{
+		"iss": i.URL,
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	for k, v := range claims {
+		all[k] = v
+	}
+	return all
+}
+
+// SignToken returns a token with claims signed with key.
+func SignToken(t testing.TB, key *rsa.PrivateKey, keyID string, claims map[string]any) string {
+	t.Helper()
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.RS256, Key: jose.JSONWebKey{Key: key, KeyID: keyID}},
+		(&jose.SignerOptions{}).WithType("JWT"),
+	)
+	if err != nil {
+		t.Fatalf("unable to create signer: %s", err)
+	}
+	token, err := jwt.Signed(signer).Claims(claims).Serialize()
+	if err != nil {
+		t.Fatalf("unable to sign token: %s", err)
+	}
+	return token
+}
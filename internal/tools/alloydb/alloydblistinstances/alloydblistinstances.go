@@ -57,6 +57,8 @@ type Config struct {
 	Description  string   `yaml:"description"`
 	AuthRequired []string `yaml:"authRequired"`
 	BaseURL      string   `yaml:"baseURL"`
+
+	tools.CommonConfig `yaml:",inline"`
 }
 
 // validate interface
@@ -106,6 +106,8 @@ type Config struct {
 	MaxDelay   string  `yaml:"maxDelay"`
 	Multiplier float64 `yaml:"multiplier"`
 	MaxRetries int     `yaml:"maxRetries"`
+
+	tools.CommonConfig `yaml:",inline"`
 }
 
 // validate interface
@@ -259,7 +261,14 @@ func (t Tool) Invoke(ctx context.Context, resourceMgr tools.SourceProvider, para
 			return op, nil
 		}
 
-		time.Sleep(delay)
+		// report each poll so that the client knows the operation is still running
+		_ = util.ReportProgress(ctx, float64(retries+1), float64(maxRetries), fmt.Sprintf("operation %q is still running", operation))
+
+		select {
+		case <-ctx.Done():
+			return nil, util.NewAgentError("timed out waiting for operation", ctx.Err())
+		case <-time.After(delay):
+		}
 		delay = time.Duration(float64(delay) * multiplier)
 		if delay > maxDelay {
 			delay = maxDelay
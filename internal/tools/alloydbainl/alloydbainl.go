@@ -58,6 +58,8 @@ type Config struct {
 	NLConfig           string                `yaml:"nlConfig" validate:"required"`
 	AuthRequired       []string              `yaml:"authRequired"`
 	NLConfigParameters parameters.Parameters `yaml:"nlConfigParameters"`
+
+	tools.CommonConfig `yaml:",inline"`
 }
 
 // validate interface
@@ -0,0 +1,173 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ClaimMatcher is a condition on the value of a claim. Exactly one of its
+// fields must be set.
+type ClaimMatcher struct {
+	// The claim is equal to the value.
+	Equals any `yaml:"equals"`
+	// The claim is one of the values.
+	In []any `yaml:"in"`
+	// The claim is a list that contains the value.
+	Contains any `yaml:"contains"`
+	// The claim is a string that ends with the suffix, such as the domain of
+	// an email address.
+	EndsWith string `yaml:"endsWith"`
+}
+
+// Validate checks that exactly one condition is set.
+func (m ClaimMatcher) Validate() error {
+	n := 0
+	for _, set := range []bool{m.Equals != nil, m.In != nil, m.Contains != nil, m.EndsWith != ""} {
+		if set {
+			n++
+		}
+	}
+	if n != 1 {
+		return fmt.Errorf("exactly one of `equals`, `in`, `contains` or `endsWith` must be set")
+	}
+	return nil
+}
+
+// matches reports whether the claim value v, which is absent if ok is false,
+// satisfies the condition.
+func (m ClaimMatcher) matches(v any, ok bool) bool {
+	if !ok {
+		return false
+	}
+	switch {
+	case m.Equals != nil:
+		return claimEqual(v, m.Equals)
+	case m.In != nil:
+		return slices.ContainsFunc(m.In, func(want any) bool { return claimEqual(v, want) })
+	case m.Contains != nil:
+		list, isList := v.([]any)
+		return isList && slices.ContainsFunc(list, func(got any) bool { return claimEqual(got, m.Contains) })
+	case m.EndsWith != "":
+		s, isString := v.(string)
+		return isString && strings.HasSuffix(s, m.EndsWith)
+	}
+	return false
+}
+
+// claimEqual compares claims by their string representation, since numbers
+// are decoded as float64 from tokens but as integers from the tools file.
+func claimEqual(got, want any) bool {
+	return fmt.Sprint(got) == fmt.Sprint(want)
+}
+
+// AuthorizationRule matches the principals whose claims satisfy every
+// condition.
+type AuthorizationRule struct {
+	// The auth services whose claims are considered. Defaults to every auth
+	// service that verified the request.
+	AuthServices []string `yaml:"authServices"`
+	// Conditions on the claims, by claim name.
+	Claims map[string]ClaimMatcher `yaml:"claims"`
+}
+
+// Validate checks the conditions of the rule.
+func (r AuthorizationRule) Validate() error {
+	if len(r.AuthServices) == 0 && len(r.Claims) == 0 {
+		return fmt.Errorf("a rule must set `authServices` or `claims`")
+	}
+	for name, m := range r.Claims {
+		if err := m.Validate(); err != nil {
+			return fmt.Errorf("claim %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// matches reports whether the claims of one of the auth services of the rule
+// satisfy every condition.
+func (r AuthorizationRule) matches(claimsFromAuth map[string]map[string]any) bool {
+	for name, claims := range claimsFromAuth {
+		if len(r.AuthServices) > 0 && !slices.Contains(r.AuthServices, name) {
+			continue
+		}
+		matched := true
+		for claim, m := range r.Claims {
+			v, ok := claims[claim]
+			if !m.matches(v, ok) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// AuthorizationPolicy decides which principals may call a tool, based on the
+// claims verified by the auth services.
+type AuthorizationPolicy struct {
+	// If set, a principal must match one of the rules.
+	Allow []AuthorizationRule `yaml:"allow"`
+	// A principal that matches one of the rules is denied, even if allowed.
+	Deny []AuthorizationRule `yaml:"deny"`
+}
+
+// Validate checks the rules of the policy.
+func (p AuthorizationPolicy) Validate() error {
+	for i, r := range p.Allow {
+		if err := r.Validate(); err != nil {
+			return fmt.Errorf("allow rule %d: %w", i, err)
+		}
+	}
+	for i, r := range p.Deny {
+		if err := r.Validate(); err != nil {
+			return fmt.Errorf("deny rule %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Allows reports whether the principal with the claims, by auth service name,
+// is allowed by the policy. A nil policy allows every principal.
+func (p *AuthorizationPolicy) Allows(claimsFromAuth map[string]map[string]any) bool {
+	if p == nil {
+		return true
+	}
+	for _, r := range p.Deny {
+		if r.matches(claimsFromAuth) {
+			return false
+		}
+	}
+	if len(p.Allow) == 0 {
+		return true
+	}
+	for _, r := range p.Allow {
+		if r.matches(claimsFromAuth) {
+			return true
+		}
+	}
+	return false
+}
+
+// ToolAllows reports whether the authorization policy of the tool, as set by
+// `authorization` in its config, allows the principal with the claims.
+func ToolAllows(tool Tool, claimsFromAuth map[string]map[string]any) bool {
+	return commonConfig(tool).Authorization.Allows(claimsFromAuth)
+}
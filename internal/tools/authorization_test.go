@@ -0,0 +1,148 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestAuthorizationPolicy(t *testing.T) {
+	policy := &tools.AuthorizationPolicy{
+		Allow: []tools.AuthorizationRule{
+			{Claims: map[string]tools.ClaimMatcher{"hd": {Equals: "example.com"}}},
+			{AuthServices: []string{"okta"}, Claims: map[string]tools.ClaimMatcher{"groups": {Contains: "data-eng"}}},
+		},
+		Deny: []tools.AuthorizationRule{
+			{Claims: map[string]tools.ClaimMatcher{"email": {In: []any{"intern@example.com"}}}},
+			{Claims: map[string]tools.ClaimMatcher{"email": {EndsWith: "@contractor.example.com"}}},
+		},
+	}
+	if err := policy.Validate(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	tcs := []struct {
+		desc   string
+		claims map[string]map[string]any
+		want   bool
+	}{
+		{
+			desc:   "no claims",
+			claims: map[string]map[string]any{},
+			want:   false,
+		},
+		{
+			desc:   "allowed by hosted domain",
+			claims: map[string]map[string]any{"google": {"hd": "example.com", "email": "alice@example.com"}},
+			want:   true,
+		},
+		{
+			desc:   "allowed by group",
+			claims: map[string]map[string]any{"okta": {"groups": []any{"analysts", "data-eng"}}},
+			want:   true,
+		},
+		{
+			desc:   "group of another auth service",
+			claims: map[string]map[string]any{"google": {"groups": []any{"data-eng"}}},
+			want:   false,
+		},
+		{
+			desc:   "denied by email",
+			claims: map[string]map[string]any{"google": {"hd": "example.com", "email": "intern@example.com"}},
+			want:   false,
+		},
+		{
+			desc: "denied by another auth service",
+			claims: map[string]map[string]any{
+				"google": {"hd": "example.com"},
+				"okta":   {"email": "bob@contractor.example.com"},
+			},
+			want: false,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := policy.Allows(tc.claims); got != tc.want {
+				t.Fatalf("unexpected result: got %t, want %t", got, tc.want)
+			}
+		})
+	}
+
+	var nilPolicy *tools.AuthorizationPolicy
+	if !nilPolicy.Allows(nil) {
+		t.Fatalf("expected nil policy to allow every principal")
+	}
+}
+
+func TestAuthorizationPolicyValidate(t *testing.T) {
+	tcs := []struct {
+		desc   string
+		policy tools.AuthorizationPolicy
+	}{
+		{
+			desc:   "empty rule",
+			policy: tools.AuthorizationPolicy{Allow: []tools.AuthorizationRule{{}}},
+		},
+		{
+			desc: "no condition",
+			policy: tools.AuthorizationPolicy{Deny: []tools.AuthorizationRule{
+				{Claims: map[string]tools.ClaimMatcher{"email": {}}},
+			}},
+		},
+		{
+			desc: "several conditions",
+			policy: tools.AuthorizationPolicy{Allow: []tools.AuthorizationRule{
+				{Claims: map[string]tools.ClaimMatcher{"email": {Equals: "a@example.com", EndsWith: "@example.com"}}},
+			}},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			if err := tc.policy.Validate(); err == nil {
+				t.Fatalf("expected validation to fail")
+			}
+		})
+	}
+}
+
+func TestToolAllows(t *testing.T) {
+	policy := tools.AuthorizationPolicy{
+		Allow: []tools.AuthorizationRule{{Claims: map[string]tools.ClaimMatcher{"hd": {Equals: "example.com"}}}},
+	}
+	cfg := fakeConfig{name: "guarded", CommonConfig: tools.CommonConfig{RequireConfirmation: true, Authorization: &policy}}
+	tool, err := cfg.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !tools.RequiresConfirmation(tool) {
+		t.Fatalf("expected tool to require confirmation")
+	}
+	if tools.ToolAllows(tool, map[string]map[string]any{"google": {"hd": "other.com"}}) {
+		t.Fatalf("expected policy to deny the principal")
+	}
+	if !tools.ToolAllows(tool, map[string]map[string]any{"google": {"hd": "example.com"}}) {
+		t.Fatalf("expected policy to allow the principal")
+	}
+
+	plain, err := fakeConfig{name: "plain"}.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !tools.ToolAllows(plain, nil) {
+		t.Fatalf("expected tool without policy to allow every principal")
+	}
+}
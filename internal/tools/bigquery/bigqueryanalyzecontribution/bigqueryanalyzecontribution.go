@@ -66,6 +66,8 @@ type Config struct {
 	Source       string   `yaml:"source" validate:"required"`
 	Description  string   `yaml:"description" validate:"required"`
 	AuthRequired []string `yaml:"authRequired"`
+
+	tools.CommonConfig `yaml:",inline"`
 }
 
 // validate interface
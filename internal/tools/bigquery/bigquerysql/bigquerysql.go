@@ -66,6 +66,8 @@ type Config struct {
 	AuthRequired       []string              `yaml:"authRequired"`
 	Parameters         parameters.Parameters `yaml:"parameters"`
 	TemplateParameters parameters.Parameters `yaml:"templateParameters"`
+
+	tools.CommonConfig `yaml:",inline"`
 }
 
 // validate interface
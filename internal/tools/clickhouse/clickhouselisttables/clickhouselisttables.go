@@ -55,6 +55,8 @@ type Config struct {
 	Description  string                `yaml:"description" validate:"required"`
 	AuthRequired []string              `yaml:"authRequired"`
 	Parameters   parameters.Parameters `yaml:"parameters"`
+
+	tools.CommonConfig `yaml:",inline"`
 }
 
 var _ tools.ToolConfig = Config{}
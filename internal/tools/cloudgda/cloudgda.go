@@ -118,6 +118,8 @@ type Config struct {
 	Context           *QueryDataContext  `yaml:"context" validate:"required"`
 	GenerationOptions *GenerationOptions `yaml:"generationOptions,omitempty"`
 	AuthRequired      []string           `yaml:"authRequired"`
+
+	tools.CommonConfig `yaml:",inline"`
 }
 
 // validate interface
@@ -56,6 +56,8 @@ type Config struct {
 	Description  string   `yaml:"description"`
 	Source       string   `yaml:"source" validate:"required"`
 	AuthRequired []string `yaml:"authRequired"`
+
+	tools.CommonConfig `yaml:",inline"`
 }
 
 // validate interface
@@ -107,6 +107,8 @@ type Config struct {
 	MaxDelay   string  `yaml:"maxDelay"`
 	Multiplier float64 `yaml:"multiplier"`
 	MaxRetries int     `yaml:"maxRetries"`
+
+	tools.CommonConfig `yaml:",inline"`
 }
 
 // validate interface
@@ -257,7 +259,14 @@ func (t Tool) Invoke(ctx context.Context, resourceMgr tools.SourceProvider, para
 			return op, nil
 		}
 
-		time.Sleep(delay)
+		// report each poll so that the client knows the operation is still running
+		_ = util.ReportProgress(ctx, float64(retries+1), float64(maxRetries), fmt.Sprintf("operation %q is still running", operationID))
+
+		select {
+		case <-ctx.Done():
+			return nil, util.NewClientServerError("timed out waiting for operation", http.StatusRequestTimeout, ctx.Err())
+		case <-time.After(delay):
+		}
 		delay = time.Duration(float64(delay) * multiplier)
 		if delay > maxDelay {
 			delay = maxDelay
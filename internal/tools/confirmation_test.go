@@ -0,0 +1,68 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+type fakeTool struct {
+	tools.Tool
+	cfg fakeConfig
+}
+
+func (t fakeTool) ToConfig() tools.ToolConfig {
+	return t.cfg
+}
+
+type fakeConfig struct {
+	name string
+
+	tools.CommonConfig
+}
+
+func (c fakeConfig) ToolConfigType() string {
+	return "fake"
+}
+
+func (c fakeConfig) Initialize(map[string]sources.Source) (tools.Tool, error) {
+	return fakeTool{cfg: c}, nil
+}
+
+func TestRequiresConfirmation(t *testing.T) {
+	tool, err := fakeConfig{name: "plain"}.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if tools.RequiresConfirmation(tool) {
+		t.Fatalf("expected tool not to require confirmation")
+	}
+
+	cfg := fakeConfig{name: "confirmed", CommonConfig: tools.CommonConfig{RequireConfirmation: true}}
+	tool, err = cfg.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !tools.RequiresConfirmation(tool) {
+		t.Fatalf("expected tool to require confirmation")
+	}
+	// the tool is not wrapped, so that its optional interfaces are kept
+	if _, ok := tool.(fakeTool); !ok {
+		t.Fatalf("unexpected tool type: %T", tool)
+	}
+}
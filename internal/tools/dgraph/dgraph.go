@@ -59,6 +59,8 @@ type Config struct {
 	IsQuery      bool                  `yaml:"isQuery"`
 	Timeout      string                `yaml:"timeout"`
 	Parameters   parameters.Parameters `yaml:"parameters"`
+
+	tools.CommonConfig `yaml:",inline"`
 }
 
 // validate interface
@@ -53,6 +53,8 @@ type Config struct {
 	Format       string                `yaml:"format"`
 	Timeout      int                   `yaml:"timeout"`
 	Parameters   parameters.Parameters `yaml:"parameters"`
+
+	tools.CommonConfig `yaml:",inline"`
 }
 
 var _ tools.ToolConfig = Config{}
@@ -77,6 +77,8 @@ type Config struct {
 
 	// Parameters for template substitution
 	Parameters parameters.Parameters `yaml:"parameters"`
+
+	tools.CommonConfig `yaml:",inline"`
 }
 
 // validate interface
@@ -69,6 +69,8 @@ type Config struct {
 	QueryParams  parameters.Parameters `yaml:"queryParams"`
 	BodyParams   parameters.Parameters `yaml:"bodyParams"`
 	HeaderParams parameters.Parameters `yaml:"headerParams"`
+
+	tools.CommonConfig `yaml:",inline"`
 }
 
 // validate interface
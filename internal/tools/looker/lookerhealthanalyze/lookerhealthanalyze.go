@@ -66,6 +66,8 @@ type Config struct {
 	AuthRequired []string               `yaml:"authRequired"`
 	Parameters   map[string]any         `yaml:"parameters"`
 	Annotations  *tools.ToolAnnotations `yaml:"annotations,omitempty"`
+
+	tools.CommonConfig `yaml:",inline"`
 }
 
 var _ tools.ToolConfig = Config{}
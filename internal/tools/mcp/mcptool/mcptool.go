@@ -0,0 +1,279 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package mcptool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/embeddingmodels"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	mcpsrc "github.com/googleapis/genai-toolbox/internal/sources/mcp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"github.com/googleapis/genai-toolbox/internal/util/parameters"
+)
+
+const resourceType string = "mcp-tool"
+
+func init() {
+	if !tools.Register(resourceType, newConfig) {
+		panic(fmt.Sprintf("tool type %q already registered", resourceType))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	McpTool(string) (mcpsrc.Tool, bool)
+	CallTool(context.Context, string, map[string]any) (mcpsrc.CallToolResult, error)
+}
+
+type Config struct {
+	Name   string `yaml:"name" validate:"required"`
+	Type   string `yaml:"type" validate:"required"`
+	Source string `yaml:"source" validate:"required"`
+	// Tool is the name of the upstream tool. Defaults to the name of the tool.
+	Tool string `yaml:"tool"`
+	// Description overrides the description of the upstream tool.
+	Description  string   `yaml:"description"`
+	AuthRequired []string `yaml:"authRequired"`
+	// Annotations override the annotations of the upstream tool.
+	Annotations *tools.ToolAnnotations `yaml:"annotations,omitempty"`
+
+	tools.CommonConfig `yaml:",inline"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigType() string {
+	return resourceType
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source type must be `%s`", resourceType, mcpsrc.SourceType)
+	}
+
+	upstreamName := cfg.Tool
+	if upstreamName == "" {
+		upstreamName = cfg.Name
+	}
+	upstream, ok := s.McpTool(upstreamName)
+	if !ok {
+		return nil, fmt.Errorf("upstream MCP server of source %q has no tool named %q", cfg.Source, upstreamName)
+	}
+
+	allParameters, err := SchemaParameters(upstream.InputSchema)
+	if err != nil {
+		return nil, fmt.Errorf("unable to import input schema of upstream tool %q: %w", upstreamName, err)
+	}
+
+	description := cfg.Description
+	if description == "" {
+		description = upstream.Description
+	}
+	annotations := cfg.Annotations
+	if annotations == nil {
+		annotations = upstream.Annotations
+	}
+
+	// Create Toolbox manifest
+	paramManifest := allParameters.Manifest()
+	if paramManifest == nil {
+		paramManifest = make([]parameters.ParameterManifest, 0)
+	}
+
+	// Create MCP manifest
+	mcpManifest := tools.GetMcpManifest(cfg.Name, description, cfg.AuthRequired, allParameters, annotations)
+
+	// finish tool setup
+	return Tool{
+		Config:       cfg,
+		upstreamName: upstreamName,
+		AllParams:    allParameters,
+		manifest:     tools.Manifest{Description: description, Parameters: paramManifest, AuthRequired: cfg.AuthRequired},
+		mcpManifest:  mcpManifest,
+	}, nil
+}
+
+// SchemaParameters converts the input schema of an upstream tool into
+// parameters, ordered by name.
+func SchemaParameters(schema mcpsrc.Schema) (parameters.Parameters, error) {
+	if t := schema.TypeName(); t != "" && t != "object" {
+		return nil, fmt.Errorf("input schema must be of type \"object\", got %q", t)
+	}
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	params := make(parameters.Parameters, 0, len(names))
+	for _, name := range names {
+		p, err := schemaParameter(name, schema.Properties[name], slices.Contains(schema.Required, name))
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, p)
+	}
+	return params, nil
+}
+
+func schemaParameter(name string, schema *mcpsrc.Schema, required bool) (parameters.Parameter, error) {
+	if schema == nil {
+		return nil, fmt.Errorf("parameter %q has no schema", name)
+	}
+	switch t := schema.TypeName(); t {
+	case "string":
+		return parameters.NewStringParameterWithRequired(name, schema.Description, required), nil
+	case "integer":
+		return parameters.NewIntParameterWithRequired(name, schema.Description, required), nil
+	case "number":
+		return parameters.NewFloatParameterWithRequired(name, schema.Description, required), nil
+	case "boolean":
+		return parameters.NewBooleanParameterWithRequired(name, schema.Description, required), nil
+	case "object":
+		return parameters.NewMapParameterWithRequired(name, schema.Description, required, ""), nil
+	case "array":
+		if schema.Items == nil {
+			return nil, fmt.Errorf("array parameter %q must specify `items`", name)
+		}
+		items, err := schemaParameter(name, schema.Items, true)
+		if err != nil {
+			return nil, err
+		}
+		return parameters.NewArrayParameterWithRequired(name, schema.Description, required, items), nil
+	default:
+		return nil, fmt.Errorf("parameter %q has unsupported type %q", name, t)
+	}
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Config
+	upstreamName string
+	AllParams    parameters.Parameters `yaml:"allParams"`
+	manifest     tools.Manifest
+	mcpManifest  tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, resourceMgr tools.SourceProvider, params parameters.ParamValues, accessToken tools.AccessToken) (any, util.ToolboxError) {
+	source, err := tools.GetCompatibleSource[compatibleSource](resourceMgr, t.Source, t.Name, t.Type)
+	if err != nil {
+		return nil, util.NewClientServerError("source used is not compatible with the tool", http.StatusInternalServerError, err)
+	}
+
+	// omitted optional parameters are not forwarded
+	arguments := make(map[string]any)
+	for _, p := range params {
+		if p.Value != nil {
+			arguments[p.Name] = p.Value
+		}
+	}
+
+	result, err := source.CallTool(ctx, t.upstreamName, arguments)
+	if err != nil {
+		return nil, util.ProcessGeneralError(err)
+	}
+	if result.IsError {
+		return nil, util.NewAgentError(fmt.Sprintf("upstream tool %q returned an error: %s", t.upstreamName, contentText(result.Content)), nil)
+	}
+	if result.StructuredContent != nil {
+		return result.StructuredContent, nil
+	}
+
+	// text content holding JSON is decoded, so that it is not encoded twice
+	// in the response of the tool
+	out := make([]any, 0, len(result.Content))
+	for _, c := range result.Content {
+		text, ok := c["text"].(string)
+		if c["type"] != "text" || !ok {
+			out = append(out, c)
+			continue
+		}
+		var v any
+		if err := json.Unmarshal([]byte(text), &v); err == nil {
+			out = append(out, v)
+		} else {
+			out = append(out, text)
+		}
+	}
+	return out, nil
+}
+
+// contentText joins the text items of a tool result.
+func contentText(content []map[string]any) string {
+	var texts []string
+	for _, c := range content {
+		if text, ok := c["text"].(string); ok {
+			texts = append(texts, text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+func (t Tool) EmbedParams(ctx context.Context, paramValues parameters.ParamValues, embeddingModelsMap map[string]embeddingmodels.EmbeddingModel) (parameters.ParamValues, error) {
+	return parameters.EmbedParams(ctx, t.AllParams, paramValues, embeddingModelsMap, nil)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization(resourceMgr tools.SourceProvider) (bool, error) {
+	return false, nil
+}
+
+func (t Tool) ToConfig() tools.ToolConfig {
+	return t.Config
+}
+
+func (t Tool) GetAuthTokenHeaderName(resourceMgr tools.SourceProvider) (string, error) {
+	return "Authorization", nil
+}
+
+func (t Tool) GetParameters() parameters.Parameters {
+	return t.AllParams
+}
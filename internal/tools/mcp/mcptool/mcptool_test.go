@@ -0,0 +1,311 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcptool_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	mcpsrc "github.com/googleapis/genai-toolbox/internal/sources/mcp"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/mcp/mcptool"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"github.com/googleapis/genai-toolbox/internal/util/parameters"
+)
+
+func TestParseFromYamlMcpTool(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	readOnly := true
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			kind: tools
+			name: echo
+			type: mcp-tool
+			source: my-mcp-server
+			`,
+			want: server.ToolConfigs{
+				"echo": mcptool.Config{
+					Name:         "echo",
+					Type:         "mcp-tool",
+					Source:       "my-mcp-server",
+					AuthRequired: []string{},
+				},
+			},
+		},
+		{
+			desc: "advanced example",
+			in: `
+			kind: tools
+			name: upstream_echo
+			type: mcp-tool
+			source: my-mcp-server
+			tool: echo
+			description: some description
+			authRequired:
+				- my-google-auth-service
+			annotations:
+				readOnlyHint: true
+			`,
+			want: server.ToolConfigs{
+				"upstream_echo": mcptool.Config{
+					Name:         "upstream_echo",
+					Type:         "mcp-tool",
+					Source:       "my-mcp-server",
+					Tool:         "echo",
+					Description:  "some description",
+					AuthRequired: []string{"my-google-auth-service"},
+					Annotations:  &tools.ToolAnnotations{ReadOnlyHint: &readOnly},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, _, _, got, _, _, err := server.UnmarshalResourceConfig(ctx, testutils.FormatYaml(tc.in))
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestSchemaParameters(t *testing.T) {
+	tcs := []struct {
+		desc   string
+		schema mcpsrc.Schema
+		want   parameters.Parameters
+		err    string
+	}{
+		{
+			desc: "all types",
+			schema: mcpsrc.Schema{
+				Type: "object",
+				Properties: map[string]*mcpsrc.Schema{
+					"query":   {Type: "string", Description: "the query"},
+					"limit":   {Type: "integer"},
+					"score":   {Type: []any{"number", "null"}},
+					"exact":   {Type: "boolean"},
+					"filters": {Type: "object"},
+					"tags":    {Type: "array", Items: &mcpsrc.Schema{Type: "string"}},
+				},
+				Required: []string{"query", "tags"},
+			},
+			want: parameters.Parameters{
+				parameters.NewBooleanParameterWithRequired("exact", "", false),
+				parameters.NewMapParameterWithRequired("filters", "", false, ""),
+				parameters.NewIntParameterWithRequired("limit", "", false),
+				parameters.NewStringParameterWithRequired("query", "the query", true),
+				parameters.NewFloatParameterWithRequired("score", "", false),
+				parameters.NewArrayParameterWithRequired("tags", "", true, parameters.NewStringParameterWithRequired("tags", "", true)),
+			},
+		},
+		{
+			desc:   "no properties",
+			schema: mcpsrc.Schema{Type: "object"},
+			want:   parameters.Parameters{},
+		},
+		{
+			desc:   "not an object",
+			schema: mcpsrc.Schema{Type: "string"},
+			err:    "input schema must be of type \"object\", got \"string\"",
+		},
+		{
+			desc: "array without items",
+			schema: mcpsrc.Schema{
+				Type:       "object",
+				Properties: map[string]*mcpsrc.Schema{"tags": {Type: "array"}},
+			},
+			err: "array parameter \"tags\" must specify `items`",
+		},
+		{
+			desc: "unsupported type",
+			schema: mcpsrc.Schema{
+				Type:       "object",
+				Properties: map[string]*mcpsrc.Schema{"value": {}},
+			},
+			err: "parameter \"value\" has unsupported type \"\"",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := mcptool.SchemaParameters(tc.schema)
+			if tc.err != "" {
+				if err == nil || err.Error() != tc.err {
+					t.Fatalf("unexpected error: got %v, want %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect parameters: diff %v", diff)
+			}
+		})
+	}
+}
+
+// fakeSource stands in for an mcp source.
+type fakeSource struct {
+	tools  map[string]mcpsrc.Tool
+	result mcpsrc.CallToolResult
+	called map[string]any
+}
+
+func (s *fakeSource) SourceType() string { return mcpsrc.SourceType }
+
+func (s *fakeSource) ToConfig() sources.SourceConfig { return nil }
+
+func (s *fakeSource) McpTool(name string) (mcpsrc.Tool, bool) {
+	tool, ok := s.tools[name]
+	return tool, ok
+}
+
+func (s *fakeSource) CallTool(_ context.Context, name string, arguments map[string]any) (mcpsrc.CallToolResult, error) {
+	if _, ok := s.tools[name]; !ok {
+		return mcpsrc.CallToolResult{}, errors.New("unknown tool")
+	}
+	s.called = arguments
+	return s.result, nil
+}
+
+type provider map[string]sources.Source
+
+func (p provider) GetSource(name string) (sources.Source, bool) {
+	s, ok := p[name]
+	return s, ok
+}
+
+func TestInvoke(t *testing.T) {
+	readOnly := true
+	src := &fakeSource{tools: map[string]mcpsrc.Tool{
+		"search": {
+			Name:        "search",
+			Description: "Searches the catalog.",
+			InputSchema: mcpsrc.Schema{
+				Type: "object",
+				Properties: map[string]*mcpsrc.Schema{
+					"query": {Type: "string"},
+					"limit": {Type: "integer"},
+				},
+				Required: []string{"query"},
+			},
+			Annotations: &tools.ToolAnnotations{ReadOnlyHint: &readOnly},
+		},
+	}}
+	cfg := mcptool.Config{Name: "catalog_search", Type: "mcp-tool", Source: "my-mcp-server", Tool: "search"}
+	tool, err := cfg.Initialize(map[string]sources.Source{"my-mcp-server": src})
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+
+	manifest := tool.McpManifest()
+	if manifest.Name != "catalog_search" || manifest.Description != "Searches the catalog." {
+		t.Fatalf("unexpected manifest: %+v", manifest)
+	}
+	if diff := cmp.Diff(src.tools["search"].Annotations, manifest.Annotations); diff != "" {
+		t.Fatalf("unexpected annotations: diff %v", diff)
+	}
+	if diff := cmp.Diff([]string{"query"}, manifest.InputSchema.Required); diff != "" {
+		t.Fatalf("unexpected required parameters: diff %v", diff)
+	}
+
+	tcs := []struct {
+		desc   string
+		result mcpsrc.CallToolResult
+		want   any
+		err    string
+	}{
+		{
+			desc: "json and plain text content",
+			result: mcpsrc.CallToolResult{Content: []map[string]any{
+				{"type": "text", "text": `{"id": 1}`},
+				{"type": "text", "text": "no more results"},
+				{"type": "image", "data": "aGk=", "mimeType": "image/png"},
+			}},
+			want: []any{
+				map[string]any{"id": float64(1)},
+				"no more results",
+				map[string]any{"type": "image", "data": "aGk=", "mimeType": "image/png"},
+			},
+		},
+		{
+			desc: "structured content",
+			result: mcpsrc.CallToolResult{
+				Content:           []map[string]any{{"type": "text", "text": `{"count": 2}`}},
+				StructuredContent: map[string]any{"count": float64(2)},
+			},
+			want: map[string]any{"count": float64(2)},
+		},
+		{
+			desc: "error result",
+			result: mcpsrc.CallToolResult{
+				Content: []map[string]any{{"type": "text", "text": "catalog unavailable"}},
+				IsError: true,
+			},
+			err: "upstream tool \"search\" returned an error: catalog unavailable",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			src.result = tc.result
+			params := parameters.ParamValues{{Name: "limit", Value: nil}, {Name: "query", Value: "books"}}
+			got, toolErr := tool.Invoke(context.Background(), provider{"my-mcp-server": src}, params, "")
+			if diff := cmp.Diff(map[string]any{"query": "books"}, src.called); diff != "" {
+				t.Fatalf("unexpected arguments: diff %v", diff)
+			}
+			if tc.err != "" {
+				var agentErr *util.AgentError
+				if !errors.As(toolErr, &agentErr) || toolErr.Error() != tc.err {
+					t.Fatalf("unexpected error: got %v, want %q", toolErr, tc.err)
+				}
+				return
+			}
+			if toolErr != nil {
+				t.Fatalf("unexpected error: %s", toolErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("unexpected result: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestFailInitialize(t *testing.T) {
+	src := &fakeSource{tools: map[string]mcpsrc.Tool{}}
+	cfg := mcptool.Config{Name: "missing", Type: "mcp-tool", Source: "my-mcp-server"}
+	_, err := cfg.Initialize(map[string]sources.Source{"my-mcp-server": src})
+	want := "upstream MCP server of source \"my-mcp-server\" has no tool named \"missing\""
+	if err == nil || err.Error() != want {
+		t.Fatalf("unexpected error: got %v, want %q", err, want)
+	}
+}
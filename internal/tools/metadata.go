@@ -0,0 +1,80 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// Icon is an image that clients can display for the server, a tool or a
+// prompt. Only supported for v2025-11-25+.
+type Icon struct {
+	// A HTTP(S) URL or a data URI of the image.
+	Src string `yaml:"src" json:"src"`
+	// The MIME type of the image, if it can't be inferred from src.
+	MimeType string `yaml:"mimeType" json:"mimeType,omitempty"`
+	// The sizes the image can be displayed at, such as "48x48" or "any".
+	Sizes []string `yaml:"sizes" json:"sizes,omitempty"`
+}
+
+// Validate checks that the source of the icon is a HTTP(S) URL or a data URI.
+func (i Icon) Validate() error {
+	u, err := url.Parse(i.Src)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http" && u.Scheme != "data") {
+		return fmt.Errorf("icon src must be a HTTP(S) URL or a data URI: %q", i.Src)
+	}
+	return nil
+}
+
+// DisplayMetadata is how clients display a tool or a prompt to users.
+type DisplayMetadata struct {
+	// A human-readable name. Only supported for v2025-06-18+.
+	Title string `yaml:"title"`
+	Icons []Icon `yaml:"icons"`
+}
+
+// Validate checks the icons of the metadata.
+func (m DisplayMetadata) Validate() error {
+	for _, icon := range m.Icons {
+		if err := icon.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ServerMetadata is what MCP clients are told about the server when they
+// initialize a session.
+type ServerMetadata struct {
+	DisplayMetadata `yaml:",inline"`
+	// Instructions describing how to use the server and its tools, that
+	// clients may add to the system prompt.
+	Instructions string `yaml:"instructions"`
+}
+
+// Override returns m with the fields that are set in o replaced.
+func (m ServerMetadata) Override(o ServerMetadata) ServerMetadata {
+	if o.Title != "" {
+		m.Title = o.Title
+	}
+	if o.Instructions != "" {
+		m.Instructions = o.Instructions
+	}
+	if len(o.Icons) > 0 {
+		m.Icons = o.Icons
+	}
+	return m
+}
@@ -0,0 +1,64 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestIconValidate(t *testing.T) {
+	tcs := []struct {
+		src string
+		err string
+	}{
+		{src: "https://example.com/icon.png"},
+		{src: "data:image/svg+xml;base64,PHN2Zy8+"},
+		{src: "file:///icon.png", err: `icon src must be a HTTP(S) URL or a data URI: "file:///icon.png"`},
+		{src: "icon.png", err: `icon src must be a HTTP(S) URL or a data URI: "icon.png"`},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.src, func(t *testing.T) {
+			err := tools.Icon{Src: tc.src}.Validate()
+			if tc.err == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.err {
+				t.Fatalf("unexpected error: got %v, want %q", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestServerMetadataOverride(t *testing.T) {
+	icons := []tools.Icon{{Src: "https://example.com/icon.png"}}
+	m := tools.ServerMetadata{
+		DisplayMetadata: tools.DisplayMetadata{Title: "Toolbox", Icons: icons},
+		Instructions:    "Use the tools.",
+	}
+	got := m.Override(tools.ServerMetadata{Instructions: "Search the docs."})
+	want := tools.ServerMetadata{
+		DisplayMetadata: tools.DisplayMetadata{Title: "Toolbox", Icons: icons},
+		Instructions:    "Search the docs.",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected metadata (-want +got):\n%s", diff)
+	}
+}
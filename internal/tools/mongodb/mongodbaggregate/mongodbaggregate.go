@@ -63,6 +63,8 @@ type Config struct {
 	Canonical       bool                   `yaml:"canonical"`
 	ReadOnly        bool                   `yaml:"readOnly"`
 	Annotations     *tools.ToolAnnotations `yaml:"annotations,omitempty"`
+
+	tools.CommonConfig `yaml:",inline"`
 }
 
 // validate interface
@@ -76,6 +76,47 @@ func TestParseFromYamlMongoQuery(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "with requireConfirmation",
+			in: `
+            kind: tools
+            name: example_tool
+            type: mongodb-delete-many
+            source: my-instance
+            description: some description
+            database: test_db
+            collection: test_coll
+            filterPayload: |
+                { name: {{json .name}} }
+            filterParams:
+                - name: name 
+                  type: string
+                  description: small description
+            requireConfirmation: true
+			`,
+			want: server.ToolConfigs{
+				"example_tool": mongodbdeletemany.Config{
+					Name:          "example_tool",
+					Type:          "mongodb-delete-many",
+					Source:        "my-instance",
+					AuthRequired:  []string{},
+					Database:      "test_db",
+					Collection:    "test_coll",
+					Description:   "some description",
+					FilterPayload: "{ name: {{json .name}} }\n",
+					FilterParams: parameters.Parameters{
+						&parameters.StringParameter{
+							CommonParameter: parameters.CommonParameter{
+								Name: "name",
+								Type: "string",
+								Desc: "small description",
+							},
+						},
+					},
+					CommonConfig: tools.CommonConfig{RequireConfirmation: true},
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -141,6 +182,22 @@ func TestFailParseFromYamlMongoQuery(t *testing.T) {
 			`,
 			err: `unable to parse tool "example_tool" as type "mongodb-delete-many"`,
 		},
+		{
+			desc: "Invalid requireConfirmation",
+			in: `
+            kind: tools
+            name: example_tool
+            type: mongodb-delete-many
+            source: my-instance
+            description: some description
+            database: test_db
+            collection: test_coll
+            filterPayload: |
+              { name : {{json .name}} }
+            requireConfirmation: "yes"
+			`,
+			err: "`requireConfirmation` must be a boolean",
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
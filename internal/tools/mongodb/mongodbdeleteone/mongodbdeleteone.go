@@ -61,6 +61,8 @@ type Config struct {
 	FilterPayload string                 `yaml:"filterPayload" validate:"required"`
 	FilterParams  parameters.Parameters  `yaml:"filterParams"`
 	Annotations   *tools.ToolAnnotations `yaml:"annotations,omitempty"`
+
+	tools.CommonConfig `yaml:",inline"`
 }
 
 // validate interface
@@ -68,6 +68,8 @@ type Config struct {
 	SortParams     parameters.Parameters  `yaml:"sortParams"`
 	Limit          int64                  `yaml:"limit"`
 	Annotations    *tools.ToolAnnotations `yaml:"annotations,omitempty"`
+
+	tools.CommonConfig `yaml:",inline"`
 }
 
 // validate interface
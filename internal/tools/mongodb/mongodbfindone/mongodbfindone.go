@@ -65,6 +65,8 @@ type Config struct {
 	ProjectPayload string                 `yaml:"projectPayload"`
 	ProjectParams  parameters.Parameters  `yaml:"projectParams"`
 	Annotations    *tools.ToolAnnotations `yaml:"annotations,omitempty"`
+
+	tools.CommonConfig `yaml:",inline"`
 }
 
 // validate interface
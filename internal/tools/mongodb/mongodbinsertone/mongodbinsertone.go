@@ -60,6 +60,8 @@ type Config struct {
 	Collection   string                 `yaml:"collection" validate:"required"`
 	Canonical    bool                   `yaml:"canonical"`
 	Annotations  *tools.ToolAnnotations `yaml:"annotations,omitempty"`
+
+	tools.CommonConfig `yaml:",inline"`
 }
 
 // validate interface
@@ -64,6 +64,8 @@ type Config struct {
 	Canonical     bool                   `yaml:"canonical"`
 	Upsert        bool                   `yaml:"upsert"`
 	Annotations   *tools.ToolAnnotations `yaml:"annotations,omitempty"`
+
+	tools.CommonConfig `yaml:",inline"`
 }
 
 // validate interface
@@ -58,6 +58,8 @@ type Config struct {
 	AuthRequired       []string              `yaml:"authRequired"`
 	Parameters         parameters.Parameters `yaml:"parameters"`
 	TemplateParameters parameters.Parameters `yaml:"templateParameters"`
+
+	tools.CommonConfig `yaml:",inline"`
 }
 
 // validate interface
@@ -74,6 +76,7 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	}
 
 	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, allParameters, nil)
+	mcpManifest.OutputSchema = tools.NewRowsOutputSchema()
 
 	// finish tool setup
 	t := Tool{
@@ -86,7 +89,10 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 }
 
 // validate interface
-var _ tools.Tool = Tool{}
+var (
+	_ tools.Tool                      = Tool{}
+	_ tools.StructuredContentProvider = Tool{}
+)
 
 type Tool struct {
 	Config
@@ -120,6 +126,12 @@ func (t Tool) Invoke(ctx context.Context, resourceMgr tools.SourceProvider, para
 	return resp, nil
 }
 
+// StructuredContent returns the rows of the result, as declared by the output
+// schema of the tool.
+func (t Tool) StructuredContent(results any) map[string]any {
+	return tools.NewRowsStructuredContent(results)
+}
+
 func (t Tool) EmbedParams(ctx context.Context, paramValues parameters.ParamValues, embeddingModelsMap map[string]embeddingmodels.EmbeddingModel) (parameters.ParamValues, error) {
 	return parameters.EmbedParams(ctx, t.AllParams, paramValues, embeddingModelsMap, nil)
 }
@@ -57,6 +57,8 @@ type Config struct {
 	Statement    string                `yaml:"statement" validate:"required"`
 	AuthRequired []string              `yaml:"authRequired"`
 	Parameters   parameters.Parameters `yaml:"parameters"`
+
+	tools.CommonConfig `yaml:",inline"`
 }
 
 // validate interface
@@ -55,6 +55,8 @@ type Config struct {
 	Description  string   `yaml:"description" validate:"required"`
 	ReadOnly     bool     `yaml:"readOnly"`
 	AuthRequired []string `yaml:"authRequired"`
+
+	tools.CommonConfig `yaml:",inline"`
 }
 
 // validate interface
@@ -69,6 +69,8 @@ type Config struct {
 	Description        string   `yaml:"description" validate:"required"`
 	AuthRequired       []string `yaml:"authRequired"`
 	CacheExpireMinutes *int     `yaml:"cacheExpireMinutes,omitempty"` // Cache expiration time in minutes.
+
+	tools.CommonConfig `yaml:",inline"`
 }
 
 // Statically verify that Config implements the tools.ToolConfig interface.
@@ -0,0 +1,157 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"github.com/googleapis/genai-toolbox/internal/util/parameters"
+)
+
+// policyCostLimit bounds the cost of evaluating a condition, so that a
+// condition can not stall invocations.
+const policyCostLimit = 1_000_000
+
+// policyEnv declares the variables of the conditions of invocation policies:
+//   - claims: the claims verified by each auth service, by auth service name
+//   - tool: the `name` and `annotations` of the tool
+//   - params: the parsed parameters of the invocation, by name
+//   - now: the time of the invocation
+var policyEnv = func() *cel.Env {
+	env, err := cel.NewEnv(
+		cel.Variable("claims", cel.MapType(cel.StringType, cel.MapType(cel.StringType, cel.DynType))),
+		cel.Variable("tool", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("params", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("now", cel.TimestampType),
+		ext.Strings(),
+	)
+	if err != nil {
+		panic(fmt.Sprintf("unable to create policy environment: %s", err))
+	}
+	return env
+}()
+
+// InvocationPolicyConfig is the `policies` kind of a tools file. It is a rule
+// over the invocations of tools, evaluated once their parameters are parsed.
+type InvocationPolicyConfig struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	// Tools are the names of the tools the policy applies to. The policy
+	// applies to every tool if empty.
+	Tools []string `yaml:"tools"`
+	// Condition is a CEL expression that allows the invocation if it
+	// evaluates to true or to an empty string. A non-empty string denies the
+	// invocation with the string as the reason.
+	Condition string `yaml:"condition" validate:"required"`
+	// Message is the reason given when the condition evaluates to false.
+	Message string `yaml:"message"`
+}
+
+// Initialize compiles the condition of the policy, and checks that the tools
+// of the policy exist.
+func (c InvocationPolicyConfig) Initialize(toolsMap map[string]Tool) (*InvocationPolicy, error) {
+	for _, name := range c.Tools {
+		if _, ok := toolsMap[name]; !ok {
+			return nil, fmt.Errorf("tool %q does not exist", name)
+		}
+	}
+	ast, iss := policyEnv.Compile(c.Condition)
+	if iss.Err() != nil {
+		return nil, fmt.Errorf("invalid condition: %w", iss.Err())
+	}
+	switch ast.OutputType() {
+	case cel.BoolType, cel.StringType, cel.DynType:
+	default:
+		return nil, fmt.Errorf("condition must evaluate to a bool or a string, not %s", ast.OutputType())
+	}
+	program, err := policyEnv.Program(ast, cel.CostLimit(policyCostLimit))
+	if err != nil {
+		return nil, fmt.Errorf("invalid condition: %w", err)
+	}
+	return &InvocationPolicy{InvocationPolicyConfig: c, program: program}, nil
+}
+
+// InvocationPolicy is an initialized InvocationPolicyConfig.
+type InvocationPolicy struct {
+	InvocationPolicyConfig
+	program cel.Program
+}
+
+// AppliesTo reports whether the policy applies to the tool with the name.
+func (p *InvocationPolicy) AppliesTo(toolName string) bool {
+	return len(p.Tools) == 0 || slices.Contains(p.Tools, toolName)
+}
+
+// Evaluate returns an agent error with the reason if the policy denies the
+// invocation. Conditions that fail to evaluate deny the invocation.
+func (p *InvocationPolicy) Evaluate(toolName string, tool Tool, claimsFromAuth map[string]map[string]any, params parameters.ParamValues) util.ToolboxError {
+	claims := make(map[string]any, len(claimsFromAuth))
+	for name, c := range claimsFromAuth {
+		claims[name] = c
+	}
+	out, _, err := p.program.Eval(map[string]any{
+		"claims": claims,
+		"tool":   map[string]any{"name": toolName, "annotations": annotationsOf(tool)},
+		"params": params.AsMap(),
+		"now":    time.Now(),
+	})
+	if err != nil {
+		return util.NewAgentError(fmt.Sprintf("tool invocation denied: policy %q could not be evaluated", p.Name), err)
+	}
+	var reason string
+	switch v := out.Value().(type) {
+	case bool:
+		if v {
+			return nil
+		}
+		reason = p.Message
+		if reason == "" {
+			reason = "the condition of the policy is not met"
+		}
+	case string:
+		if v == "" {
+			return nil
+		}
+		reason = v
+	default:
+		return util.NewAgentError(fmt.Sprintf("tool invocation denied: policy %q evaluated to %s, not a bool or a string", p.Name, out.Type().TypeName()), nil)
+	}
+	return util.NewAgentError(fmt.Sprintf("tool invocation denied by policy %q: %s", p.Name, reason), nil)
+}
+
+// annotationsOf returns the annotations of the tool that are set, by name.
+func annotationsOf(tool Tool) map[string]any {
+	annotations := map[string]any{}
+	a := tool.McpManifest().Annotations
+	if a == nil {
+		return annotations
+	}
+	for name, hint := range map[string]*bool{
+		"destructiveHint": a.DestructiveHint,
+		"idempotentHint":  a.IdempotentHint,
+		"openWorldHint":   a.OpenWorldHint,
+		"readOnlyHint":    a.ReadOnlyHint,
+	} {
+		if hint != nil {
+			annotations[name] = *hint
+		}
+	}
+	return annotations
+}
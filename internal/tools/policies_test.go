@@ -0,0 +1,161 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util/parameters"
+)
+
+// annotatedTool is a tool with annotations.
+type annotatedTool struct {
+	tools.Tool
+	annotations *tools.ToolAnnotations
+}
+
+func (t annotatedTool) McpManifest() tools.McpManifest {
+	return tools.McpManifest{Annotations: t.annotations}
+}
+
+func TestInvocationPolicy(t *testing.T) {
+	readOnly := true
+	toolsMap := map[string]tools.Tool{
+		"execute-sql": annotatedTool{},
+		"list-tables": annotatedTool{annotations: &tools.ToolAnnotations{ReadOnlyHint: &readOnly}},
+	}
+	initialize := func(t *testing.T, cfg tools.InvocationPolicyConfig) *tools.InvocationPolicy {
+		t.Helper()
+		p, err := cfg.Initialize(toolsMap)
+		if err != nil {
+			t.Fatalf("unable to initialize policy: %s", err)
+		}
+		return p
+	}
+	toolset := tools.Toolset{Policies: []*tools.InvocationPolicy{
+		initialize(t, tools.InvocationPolicyConfig{
+			Name:      "dba-only",
+			Tools:     []string{"execute-sql"},
+			Condition: `claims.exists(s, "dba" in claims[s].groups)`,
+			Message:   "only DBAs may execute SQL",
+		}),
+		initialize(t, tools.InvocationPolicyConfig{
+			Name:      "limit",
+			Condition: `!has(params.limit) || params.limit <= 1000 || claims.exists(s, claims[s].power_user == true) ? "" : "limit must be at most 1000"`,
+		}),
+		initialize(t, tools.InvocationPolicyConfig{
+			Name:      "read-only-tools",
+			Condition: `tool.name == "execute-sql" || tool.annotations.readOnlyHint`,
+		}),
+	}}
+
+	tcs := []struct {
+		desc     string
+		toolName string
+		claims   map[string]map[string]any
+		params   parameters.ParamValues
+		err      string
+	}{
+		{
+			desc:     "allowed",
+			toolName: "execute-sql",
+			claims:   map[string]map[string]any{"my-oidc": {"groups": []any{"dba"}}},
+		},
+		{
+			desc:     "denied with message",
+			toolName: "execute-sql",
+			claims:   map[string]map[string]any{"my-oidc": {"groups": []any{"analysts"}}},
+			err:      `tool invocation denied by policy "dba-only": only DBAs may execute SQL`,
+		},
+		{
+			desc:     "policy of another tool",
+			toolName: "list-tables",
+			claims:   map[string]map[string]any{},
+			params:   parameters.ParamValues{{Name: "limit", Value: 10}},
+		},
+		{
+			desc:     "denied with reason of condition",
+			toolName: "list-tables",
+			claims:   map[string]map[string]any{"my-oidc": {"power_user": false}},
+			params:   parameters.ParamValues{{Name: "limit", Value: 5000}},
+			err:      `tool invocation denied by policy "limit": limit must be at most 1000`,
+		},
+		{
+			desc:     "allowed by claim",
+			toolName: "list-tables",
+			claims:   map[string]map[string]any{"my-oidc": {"power_user": true}},
+			params:   parameters.ParamValues{{Name: "limit", Value: 5000}},
+		},
+		{
+			desc:     "failed evaluation denies",
+			toolName: "execute-sql",
+			claims:   map[string]map[string]any{"my-oidc": {}},
+			err:      `tool invocation denied: policy "dba-only" could not be evaluated`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := toolset.EvaluatePolicies(tc.toolName, toolsMap[tc.toolName], tc.claims, tc.params)
+			if tc.err == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.HasPrefix(err.Error(), tc.err) {
+				t.Fatalf("unexpected error: got %v, want %q", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestInvocationPolicyConfigErrors(t *testing.T) {
+	tcs := []struct {
+		desc string
+		cfg  tools.InvocationPolicyConfig
+		err  string
+	}{
+		{
+			desc: "unknown tool",
+			cfg:  tools.InvocationPolicyConfig{Name: "p", Tools: []string{"missing"}, Condition: "true"},
+			err:  `tool "missing" does not exist`,
+		},
+		{
+			desc: "invalid condition",
+			cfg:  tools.InvocationPolicyConfig{Name: "p", Condition: "params.limit <="},
+			err:  "invalid condition",
+		},
+		{
+			desc: "undeclared variable",
+			cfg:  tools.InvocationPolicyConfig{Name: "p", Condition: "user.admin"},
+			err:  "invalid condition",
+		},
+		{
+			desc: "condition of another type",
+			cfg:  tools.InvocationPolicyConfig{Name: "p", Condition: "1 + 1"},
+			err:  "condition must evaluate to a bool or a string, not int",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := tc.cfg.Initialize(map[string]tools.Tool{})
+			if err == nil || !strings.HasPrefix(err.Error(), tc.err) {
+				t.Fatalf("unexpected error: got %v, want %q", err, tc.err)
+			}
+		})
+	}
+}
@@ -55,6 +55,8 @@ type Config struct {
 	Source       string   `yaml:"source" validate:"required"`
 	Description  string   `yaml:"description" validate:"required"`
 	AuthRequired []string `yaml:"authRequired"`
+
+	tools.CommonConfig `yaml:",inline"`
 }
 
 var _ tools.ToolConfig = Config{}
@@ -64,6 +66,11 @@ func (cfg Config) ToolConfigType() string {
 }
 
 func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// arbitrary statements can change the role set for the caller
+	if src, ok := srcs[cfg.Source]; ok && sources.UsesSessionIdentity(src) {
+		return nil, fmt.Errorf("tool %q can not use source %q, which sets `sessionIdentity`", cfg.Name, cfg.Source)
+	}
+
 	sqlParameter := parameters.NewStringParameter("sql", "The sql to execute.")
 	params := parameters.Parameters{sqlParameter}
 
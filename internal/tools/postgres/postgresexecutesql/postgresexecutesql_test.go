@@ -19,6 +19,8 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/postgres"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
 	"github.com/googleapis/genai-toolbox/internal/tools/postgres/postgresexecutesql"
 )
@@ -70,3 +72,29 @@ func TestParseFromYamlExecuteSql(t *testing.T) {
 	}
 
 }
+
+func TestInitializeSessionIdentity(t *testing.T) {
+	cfg := postgresexecutesql.Config{
+		Name:        "example_tool",
+		Type:        "postgres-execute-sql",
+		Source:      "my-pg-instance",
+		Description: "some description",
+	}
+	srcs := map[string]sources.Source{
+		"my-pg-instance": &postgres.Source{Config: postgres.Config{Name: "my-pg-instance"}},
+	}
+	if _, err := cfg.Initialize(srcs); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// statements could change the role set for the caller
+	srcs["my-pg-instance"] = &postgres.Source{Config: postgres.Config{
+		Name:            "my-pg-instance",
+		SessionIdentity: &sources.SessionIdentity{AuthService: "my-auth", RoleClaim: "role"},
+	}}
+	_, err := cfg.Initialize(srcs)
+	want := "tool \"example_tool\" can not use source \"my-pg-instance\", which sets `sessionIdentity`"
+	if err == nil || err.Error() != want {
+		t.Fatalf("unexpected error: got %v, want %q", err, want)
+	}
+}
@@ -70,6 +70,7 @@ func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.T
 
 type compatibleSource interface {
 	PostgresPool() *pgxpool.Pool
+	RunSQL(context.Context, string, []any) (any, error)
 }
 
 // validate compatible sources are still compatible
@@ -85,6 +86,8 @@ type Config struct {
 	Source       string   `yaml:"source" validate:"required"`
 	Description  string   `yaml:"description"`
 	AuthRequired []string `yaml:"authRequired"`
+
+	tools.CommonConfig `yaml:",inline"`
 }
 
 var _ tools.ToolConfig = Config{}
@@ -101,8 +104,7 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	}
 
 	// verify the source is compatible
-	s, ok := rawS.(compatibleSource)
-	if !ok {
+	if _, ok := rawS.(compatibleSource); !ok {
 		return nil, fmt.Errorf("invalid source for %q tool: source type must be one of %q", resourceType, compatibleSources)
 	}
 
@@ -122,7 +124,6 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	return Tool{
 		Config:    cfg,
 		allParams: allParameters,
-		pool:      s.PostgresPool(),
 		manifest: tools.Manifest{
 			Description:  cfg.Description,
 			Parameters:   paramManifest,
@@ -137,7 +138,6 @@ var _ tools.Tool = Tool{}
 type Tool struct {
 	Config
 	allParams   parameters.Parameters `yaml:"allParams"`
-	pool        *pgxpool.Pool
 	manifest    tools.Manifest
 	mcpManifest tools.McpManifest
 }
@@ -155,28 +155,16 @@ func (t Tool) Invoke(ctx context.Context, resourceMgr tools.SourceProvider, para
 	}
 	sliceParams := newParams.AsSlice()
 
-	results, err := t.pool.Query(ctx, listStoredProcedure, sliceParams...)
+	source, err := tools.GetCompatibleSource[compatibleSource](resourceMgr, t.Source, t.Name, t.Type)
 	if err != nil {
-		return nil, util.ProcessGeneralError(err)
+		return nil, util.NewClientServerError("source used is not compatible with the tool", http.StatusInternalServerError, err)
 	}
-	defer results.Close()
-
-	fields := results.FieldDescriptions()
-	var out []map[string]any
-
-	for results.Next() {
-		values, err := results.Values()
-		if err != nil {
-			return nil, util.NewClientServerError("unable to parse row", http.StatusInternalServerError, err)
-		}
-		rowMap := make(map[string]any)
-		for i, field := range fields {
-			rowMap[string(field.Name)] = values[i]
-		}
-		out = append(out, rowMap)
+	// the source runs the query as the caller if it sets a session identity
+	resp, err := source.RunSQL(ctx, listStoredProcedure, sliceParams)
+	if err != nil {
+		return nil, util.ProcessGeneralError(err)
 	}
-
-	return out, nil
+	return resp, nil
 }
 
 func (t Tool) EmbedParams(ctx context.Context, paramValues parameters.ParamValues, embeddingModelsMap map[string]embeddingmodels.EmbeddingModel) (parameters.ParamValues, error) {
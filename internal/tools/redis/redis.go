@@ -56,6 +56,8 @@ type Config struct {
 	Commands     [][]string            `yaml:"commands" validate:"required"`
 	AuthRequired []string              `yaml:"authRequired"`
 	Parameters   parameters.Parameters `yaml:"parameters"`
+
+	tools.CommonConfig `yaml:",inline"`
 }
 
 // validate interface
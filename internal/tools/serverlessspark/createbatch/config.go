@@ -21,6 +21,7 @@ import (
 
 	dataprocpb "cloud.google.com/go/dataproc/v2/apiv1/dataprocpb"
 	"github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/tools"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
 )
@@ -49,6 +50,8 @@ type Config struct {
 	RuntimeConfig     *dataprocpb.RuntimeConfig     `yaml:"runtimeConfig"`
 	EnvironmentConfig *dataprocpb.EnvironmentConfig `yaml:"environmentConfig"`
 	AuthRequired      []string                      `yaml:"authRequired"`
+
+	tools.CommonConfig `yaml:",inline"`
 }
 
 func NewConfig(ctx context.Context, name string, decoder *yaml.Decoder) (Config, error) {
@@ -62,6 +65,8 @@ func NewConfig(ctx context.Context, name string, decoder *yaml.Decoder) (Config,
 		RuntimeConfig     any      `yaml:"runtimeConfig"`
 		EnvironmentConfig any      `yaml:"environmentConfig"`
 		AuthRequired      []string `yaml:"authRequired"`
+
+		tools.CommonConfig `yaml:",inline"`
 	}
 
 	if err := decoder.DecodeContext(ctx, &ymlCfg); err != nil {
@@ -74,6 +79,7 @@ func NewConfig(ctx context.Context, name string, decoder *yaml.Decoder) (Config,
 		Source:       ymlCfg.Source,
 		Description:  ymlCfg.Description,
 		AuthRequired: ymlCfg.AuthRequired,
+		CommonConfig: ymlCfg.CommonConfig,
 	}
 
 	if ymlCfg.RuntimeConfig != nil {
@@ -57,6 +57,8 @@ type Config struct {
 	Description  string   `yaml:"description" validate:"required"`
 	AuthRequired []string `yaml:"authRequired"`
 	ReadOnly     bool     `yaml:"readOnly"`
+
+	tools.CommonConfig `yaml:",inline"`
 }
 
 // validate interface
@@ -68,6 +68,35 @@ type ToolConfig interface {
 	Initialize(map[string]sources.Source) (Tool, error)
 }
 
+// CommonConfig holds the fields accepted by every tool type. Tool configs
+// embed it inline, so that the fields are read through the config of a tool.
+type CommonConfig struct {
+	// RequireConfirmation requires the user to confirm each invocation of
+	// the tool.
+	RequireConfirmation bool `yaml:"requireConfirmation"`
+	// Authorization restricts the principals that may call the tool.
+	Authorization *AuthorizationPolicy `yaml:"authorization"`
+}
+
+// CommonConfigProvider is implemented by tool configs that embed
+// CommonConfig.
+type CommonConfigProvider interface {
+	GetCommonConfig() CommonConfig
+}
+
+func (c CommonConfig) GetCommonConfig() CommonConfig {
+	return c
+}
+
+// commonConfig returns the fields of the config of the tool that are accepted
+// by every tool type.
+func commonConfig(tool Tool) CommonConfig {
+	if p, ok := tool.ToConfig().(CommonConfigProvider); ok {
+		return p.GetCommonConfig()
+	}
+	return CommonConfig{}
+}
+
 // https://modelcontextprotocol.io/specification/2025-06-18/schema#toolannotations
 type ToolAnnotations struct {
 	DestructiveHint *bool `json:"destructiveHint,omitempty" yaml:"destructiveHint,omitempty"`
@@ -143,12 +172,92 @@ type Manifest struct {
 type McpManifest struct {
 	// The name of the tool.
 	Name string `json:"name"`
+	// A human-readable name of the tool. Only supported for v2025-06-18+.
+	Title string `json:"title,omitempty"`
 	// A human-readable description of the tool.
 	Description string           `json:"description,omitempty"`
 	Annotations *ToolAnnotations `json:"annotations,omitempty"`
 	// A JSON Schema object defining the expected parameters for the tool.
 	InputSchema parameters.McpToolsSchema `json:"inputSchema,omitempty"`
-	Metadata    map[string]any            `json:"_meta,omitempty"`
+	// An optional JSON Schema object defining the structure of the tool's
+	// output returned in the structuredContent field of a tool result.
+	// Only supported for v2025-06-18+.
+	OutputSchema *parameters.McpToolsSchema `json:"outputSchema,omitempty"`
+	// Execution-related properties of the tool.
+	// Only supported for v2025-11-25+.
+	Execution *ToolExecution `json:"execution,omitempty"`
+	// Icons of the tool. Only supported for v2025-11-25+.
+	Icons    []Icon         `json:"icons,omitempty"`
+	Metadata map[string]any `json:"_meta,omitempty"`
+}
+
+// ToolExecution describes how a tool can be executed.
+type ToolExecution struct {
+	// Whether the tool can be called with a task: "forbidden", "optional" or
+	// "required". Defaults to "forbidden".
+	TaskSupport string `json:"taskSupport,omitempty"`
+}
+
+// StructuredContentProvider is implemented by tools with an output schema,
+// which return the structured content of their results.
+type StructuredContentProvider interface {
+	// StructuredContent returns the structured content of the results, which
+	// conforms to the output schema of the tool.
+	StructuredContent(results any) map[string]any
+}
+
+// StructuredContent returns the structured content of the results of the
+// tool, or nil if the tool has no output schema. Results of tools that do not
+// implement StructuredContentProvider are their own structured content if they
+// are objects.
+func StructuredContent(tool Tool, results any) map[string]any {
+	if tool.McpManifest().OutputSchema == nil {
+		return nil
+	}
+	if p, ok := tool.(StructuredContentProvider); ok {
+		return p.StructuredContent(results)
+	}
+	if m, ok := results.(map[string]any); ok {
+		return m
+	}
+	return nil
+}
+
+// structuredResultKey is the key of the structured content that holds the
+// result of tools declaring the output schema from NewRowsOutputSchema.
+const structuredResultKey = "result"
+
+// NewRowsOutputSchema returns the output schema of tools that return a list of
+// rows, such as SQL tools. These tools implement StructuredContentProvider
+// with NewRowsStructuredContent.
+func NewRowsOutputSchema() *parameters.McpToolsSchema {
+	return &parameters.McpToolsSchema{
+		Type: "object",
+		Properties: map[string]parameters.ParameterMcpManifest{
+			structuredResultKey: {
+				Type:        "array",
+				Description: "The rows returned by the tool.",
+				Items: &parameters.ParameterMcpManifest{
+					Type:        "object",
+					Description: "A row, keyed by column name.",
+				},
+			},
+		},
+		Required: []string{structuredResultKey},
+	}
+}
+
+// NewRowsStructuredContent returns the structured content of a tool result
+// that conforms to the schema of NewRowsOutputSchema.
+func NewRowsStructuredContent(results any) map[string]any {
+	rows, ok := results.([]any)
+	if !ok && results != nil {
+		rows = []any{results}
+	}
+	if rows == nil {
+		rows = []any{}
+	}
+	return map[string]any{structuredResultKey: rows}
 }
 
 func GetMcpManifest(name, desc string, authInvoke []string, params parameters.Parameters, annotations *ToolAnnotations) McpManifest {
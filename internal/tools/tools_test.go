@@ -120,3 +120,98 @@ func TestGetMcpManifestMetadata(t *testing.T) {
 		})
 	}
 }
+
+func TestNewRowsStructuredContent(t *testing.T) {
+	tcs := []struct {
+		desc    string
+		results any
+		want    map[string]any
+	}{
+		{
+			desc:    "rows",
+			results: []any{map[string]any{"id": 1}, map[string]any{"id": 2}},
+			want:    map[string]any{"result": []any{map[string]any{"id": 1}, map[string]any{"id": 2}}},
+		},
+		{
+			desc:    "no rows",
+			results: []any(nil),
+			want:    map[string]any{"result": []any{}},
+		},
+		{
+			desc:    "nil result",
+			results: nil,
+			want:    map[string]any{"result": []any{}},
+		},
+		{
+			desc:    "single result",
+			results: map[string]any{"id": 1},
+			want:    map[string]any{"result": []any{map[string]any{"id": 1}}},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := tools.NewRowsStructuredContent(tc.results)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect structured content: diff %v", diff)
+			}
+		})
+	}
+}
+
+// outputSchemaTool is a tool with an output schema.
+type outputSchemaTool struct {
+	tools.Tool
+}
+
+func (outputSchemaTool) McpManifest() tools.McpManifest {
+	return tools.McpManifest{OutputSchema: &parameters.McpToolsSchema{Type: "object"}}
+}
+
+// rowsTool is a tool that returns rows as its structured content.
+type rowsTool struct {
+	outputSchemaTool
+}
+
+func (rowsTool) StructuredContent(results any) map[string]any {
+	return tools.NewRowsStructuredContent(results)
+}
+
+func TestStructuredContent(t *testing.T) {
+	tcs := []struct {
+		desc    string
+		tool    tools.Tool
+		results any
+		want    map[string]any
+	}{
+		{
+			desc:    "no output schema",
+			tool:    annotatedTool{},
+			results: []any{map[string]any{"id": 1}},
+		},
+		{
+			desc:    "structured content of the tool",
+			tool:    rowsTool{},
+			results: []any{map[string]any{"id": 1}},
+			want:    map[string]any{"result": []any{map[string]any{"id": 1}}},
+		},
+		{
+			desc:    "object result",
+			tool:    outputSchemaTool{},
+			results: map[string]any{"id": 1},
+			want:    map[string]any{"id": 1},
+		},
+		{
+			desc:    "result that is not an object",
+			tool:    outputSchemaTool{},
+			results: []any{map[string]any{"id": 1}},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := tools.StructuredContent(tc.tool, tc.results)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect structured content: diff %v", diff)
+			}
+		})
+	}
+}
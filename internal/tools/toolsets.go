@@ -17,11 +17,24 @@ package tools
 import (
 	"fmt"
 	"regexp"
+	"slices"
+
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"github.com/googleapis/genai-toolbox/internal/util/parameters"
 )
 
 type ToolsetConfig struct {
 	Name      string   `yaml:"name"`
 	ToolNames []string `yaml:",inline"`
+	// ResourceNames are the names of the MCP resources that sessions of the
+	// toolset may list and read.
+	ResourceNames []string `yaml:"resources"`
+	// ServerMetadata overrides the server metadata for sessions of the
+	// toolset.
+	ServerMetadata ServerMetadata `yaml:",inline"`
+	// Authorization restricts the principals that may call the tools of the
+	// toolset, in addition to the policies of the tools.
+	Authorization *AuthorizationPolicy `yaml:"authorization"`
 }
 
 type Toolset struct {
@@ -29,15 +42,86 @@ type Toolset struct {
 	Tools       []*Tool         `yaml:",inline"`
 	Manifest    ToolsetManifest `yaml:",inline"`
 	McpManifest []McpManifest   `yaml:",inline"`
+	// Policies are evaluated for every invocation of the tools of the
+	// toolset, once its parameters are parsed.
+	Policies []*InvocationPolicy `yaml:"-"`
+	// ToolAuthorizations are the authorization policies of the other
+	// toolsets that contain a tool, by tool name. They restrict the tools of
+	// the default toolset, which contains every tool.
+	ToolAuthorizations map[string][]*AuthorizationPolicy `yaml:"-"`
 }
 
 func (t Toolset) ToConfig() ToolsetConfig {
 	return t.ToolsetConfig
 }
 
+// HasTool reports whether the tool with the given name is part of the toolset.
+func (t Toolset) HasTool(toolName string) bool {
+	_, ok := t.Manifest.ToolsManifest[toolName]
+	return ok
+}
+
+// HasResource reports whether the MCP resource with the given name is part of
+// the toolset.
+func (t Toolset) HasResource(resourceName string) bool {
+	return slices.Contains(t.ResourceNames, resourceName)
+}
+
+// Allows reports whether the principal with the claims, by auth service name,
+// may call tool as part of the toolset.
+func (t Toolset) Allows(toolName string, tool Tool, claimsFromAuth map[string]map[string]any) bool {
+	return t.Authorization.Allows(claimsFromAuth) && t.allowsTool(toolName, tool, claimsFromAuth)
+}
+
+// allowsTool reports whether the policy of the tool, and those of the other
+// toolsets that contain it, allow the principal with the claims.
+func (t Toolset) allowsTool(toolName string, tool Tool, claimsFromAuth map[string]map[string]any) bool {
+	if !ToolAllows(tool, claimsFromAuth) {
+		return false
+	}
+	for _, p := range t.ToolAuthorizations[toolName] {
+		if !p.Allows(claimsFromAuth) {
+			return false
+		}
+	}
+	return true
+}
+
+// EvaluatePolicies returns an agent error with the reason if one of the
+// policies that apply to the tool denies the invocation.
+func (t Toolset) EvaluatePolicies(toolName string, tool Tool, claimsFromAuth map[string]map[string]any, params parameters.ParamValues) util.ToolboxError {
+	for _, p := range t.Policies {
+		if !p.AppliesTo(toolName) {
+			continue
+		}
+		if err := p.Evaluate(toolName, tool, claimsFromAuth, params); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AllowedMcpManifest returns the MCP manifests of the tools of the toolset
+// that the principal with the claims may call.
+func (t Toolset) AllowedMcpManifest(claimsFromAuth map[string]map[string]any) []McpManifest {
+	if !t.Authorization.Allows(claimsFromAuth) {
+		return []McpManifest{}
+	}
+	manifests := make([]McpManifest, 0, len(t.McpManifest))
+	for i, m := range t.McpManifest {
+		if t.allowsTool(m.Name, *t.Tools[i], claimsFromAuth) {
+			manifests = append(manifests, m)
+		}
+	}
+	return manifests
+}
+
 type ToolsetManifest struct {
 	ServerVersion string              `json:"serverVersion"`
 	ToolsManifest map[string]Manifest `json:"tools"`
+	// NextCursor is the cursor of the next page of tools, if the manifest is
+	// paginated and more tools are available.
+	NextCursor string `json:"nextCursor,omitempty"`
 }
 
 func (t ToolsetConfig) Initialize(serverVersion string, toolsMap map[string]Tool) (Toolset, error) {
@@ -45,6 +129,8 @@ func (t ToolsetConfig) Initialize(serverVersion string, toolsMap map[string]Tool
 	// Check each declared tool name exists
 	var toolset Toolset
 	toolset.Name = t.Name
+	toolset.Authorization = t.Authorization
+	toolset.ResourceNames = t.ResourceNames
 	if !IsValidName(toolset.Name) {
 		return toolset, fmt.Errorf("invalid toolset name: %s", toolset.Name)
 	}
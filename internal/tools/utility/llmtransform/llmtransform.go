@@ -0,0 +1,268 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llmtransform
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"text/template"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/embeddingmodels"
+	mcputil "github.com/googleapis/genai-toolbox/internal/server/mcp/util"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"github.com/googleapis/genai-toolbox/internal/util/parameters"
+)
+
+const resourceType string = "llm-transform"
+
+// resultKey is the name under which the result of the wrapped tool is
+// available in the prompt template.
+const resultKey = "result"
+
+// defaultMaxTokens is the maximum number of tokens sampled when the tool does
+// not set `maxTokens`.
+const defaultMaxTokens = 1024
+
+func init() {
+	if !tools.Register(resourceType, newConfig) {
+		panic(fmt.Sprintf("tool type %q already registered", resourceType))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+// toolProvider is the view of the resource manager used to look up the
+// wrapped tool, which is not available when the tool is initialized.
+type toolProvider interface {
+	GetTool(string) (tools.Tool, bool)
+}
+
+type Config struct {
+	Name        string `yaml:"name" validate:"required"`
+	Type        string `yaml:"type" validate:"required"`
+	Description string `yaml:"description" validate:"required"`
+	// Tool is the name of the tool whose result is transformed.
+	Tool string `yaml:"tool" validate:"required"`
+	// Prompt is a Go template rendered with the parameters of the tool and
+	// the JSON-encoded result of the wrapped tool as `.result`.
+	Prompt       string                `yaml:"prompt" validate:"required"`
+	SystemPrompt string                `yaml:"systemPrompt"`
+	MaxTokens    int                   `yaml:"maxTokens"`
+	Temperature  *float64              `yaml:"temperature"`
+	ModelHints   []string              `yaml:"modelHints"`
+	Parameters   parameters.Parameters `yaml:"parameters"`
+	AuthRequired []string              `yaml:"authRequired"`
+
+	tools.CommonConfig `yaml:",inline"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigType() string {
+	return resourceType
+}
+
+func (cfg Config) Initialize(_ map[string]sources.Source) (tools.Tool, error) {
+	if cfg.Tool == cfg.Name {
+		return nil, fmt.Errorf("tool %q cannot transform its own result", cfg.Name)
+	}
+	if err := parameters.CheckDuplicateParameters(cfg.Parameters); err != nil {
+		return nil, err
+	}
+	for _, p := range cfg.Parameters {
+		if p.GetName() == resultKey {
+			return nil, fmt.Errorf("parameter name %q is reserved for the result of the wrapped tool", resultKey)
+		}
+	}
+	prompt, err := template.New("prompt").Parse(cfg.Prompt)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse prompt: %w", err)
+	}
+
+	// Create Toolbox manifest
+	paramManifest := cfg.Parameters.Manifest()
+	if paramManifest == nil {
+		paramManifest = make([]parameters.ParameterManifest, 0)
+	}
+
+	// Create MCP manifest
+	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, cfg.Parameters, nil)
+
+	// finish tool setup
+	return Tool{
+		Config:      cfg,
+		prompt:      prompt,
+		manifest:    tools.Manifest{Description: cfg.Description, Parameters: paramManifest, AuthRequired: cfg.AuthRequired},
+		mcpManifest: mcpManifest,
+	}, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Config
+	prompt      *template.Template
+	manifest    tools.Manifest
+	mcpManifest tools.McpManifest
+}
+
+// wrappedTool returns the tool whose result is transformed.
+func (t Tool) wrappedTool(resourceMgr tools.SourceProvider) (tools.Tool, error) {
+	provider, ok := resourceMgr.(toolProvider)
+	if !ok {
+		return nil, fmt.Errorf("unable to look up tools")
+	}
+	tool, ok := provider.GetTool(t.Tool)
+	if !ok {
+		return nil, fmt.Errorf("no tool named %q configured", t.Tool)
+	}
+	return tool, nil
+}
+
+func (t Tool) Invoke(ctx context.Context, resourceMgr tools.SourceProvider, params parameters.ParamValues, accessToken tools.AccessToken) (any, util.ToolboxError) {
+	wrapped, err := t.wrappedTool(resourceMgr)
+	if err != nil {
+		return nil, util.NewClientServerError("wrapped tool is not available", http.StatusInternalServerError, err)
+	}
+	// the wrapped tool must accept one of the auth services verified for this
+	// invocation, so that it cannot be used to bypass authorization
+	verifiedAuthServices := slices.Collect(maps.Keys(util.AuthClaimsFromContext(ctx)))
+	if !wrapped.Authorized(verifiedAuthServices) {
+		return nil, util.NewClientServerError(fmt.Sprintf("tool %q is not authorized to invoke tool %q", t.Name, t.Tool), http.StatusUnauthorized, nil)
+	}
+
+	paramsMap := params.AsMap()
+	wrappedParams, err := parameters.ParseParams(wrapped.GetParameters(), paramsMap, nil)
+	if err != nil {
+		return nil, util.NewAgentError(fmt.Sprintf("invalid parameters for tool %q", t.Tool), err)
+	}
+	// the wrapped tool is invoked through the toolset of this invocation, so
+	// that its policies and confirmation apply as if it were called directly
+	toolset, ok := mcputil.ToolsetFromContext(ctx)
+	if !ok {
+		return nil, util.NewClientServerError(fmt.Sprintf("tool %q must be invoked through a toolset", t.Name), http.StatusInternalServerError, nil)
+	}
+	resources, ok := resourceMgr.(mcputil.ToolResources)
+	if !ok {
+		return nil, util.NewClientServerError("embedding models are not available", http.StatusInternalServerError, nil)
+	}
+	result, toolErr := mcputil.InvokeTool(ctx, resources, toolset, t.Tool, wrapped, wrappedParams, accessToken)
+	if toolErr != nil {
+		return nil, toolErr
+	}
+
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return nil, util.NewClientServerError("unable to marshal the result of the wrapped tool", http.StatusInternalServerError, err)
+	}
+	data := map[string]any{resultKey: string(encoded)}
+	for k, v := range paramsMap {
+		data[k] = v
+	}
+	var prompt bytes.Buffer
+	if err := t.prompt.Execute(&prompt, data); err != nil {
+		return nil, util.NewClientServerError("unable to render prompt", http.StatusInternalServerError, err)
+	}
+
+	req := mcputil.CreateMessageRequestParams{
+		Messages: []mcputil.SamplingMessage{{
+			Role:    mcputil.ROLE_USER,
+			Content: mcputil.SamplingContent{Type: "text", Text: prompt.String()},
+		}},
+		SystemPrompt: t.SystemPrompt,
+		MaxTokens:    t.MaxTokens,
+		Temperature:  t.Temperature,
+	}
+	if req.MaxTokens <= 0 {
+		req.MaxTokens = defaultMaxTokens
+	}
+	if len(t.ModelHints) > 0 {
+		req.ModelPreferences = &mcputil.ModelPreferences{}
+		for _, hint := range t.ModelHints {
+			req.ModelPreferences.Hints = append(req.ModelPreferences.Hints, mcputil.ModelHint{Name: hint})
+		}
+	}
+	sampled, err := mcputil.CreateMessage(ctx, req)
+	if err != nil {
+		return nil, util.NewAgentError(fmt.Sprintf("unable to transform the result of tool %q", t.Tool), err)
+	}
+
+	text, ok, err := sampled.Text()
+	if err != nil {
+		return nil, util.NewAgentError(fmt.Sprintf("unable to transform the result of tool %q", t.Tool), err)
+	}
+	if !ok {
+		// content that is not text, such as an image, is returned as is
+		items, _ := sampled.ContentItems()
+		return items, nil
+	}
+	return text, nil
+}
+
+func (t Tool) EmbedParams(ctx context.Context, paramValues parameters.ParamValues, embeddingModelsMap map[string]embeddingmodels.EmbeddingModel) (parameters.ParamValues, error) {
+	return parameters.EmbedParams(ctx, t.Parameters, paramValues, embeddingModelsMap, nil)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization(resourceMgr tools.SourceProvider) (bool, error) {
+	wrapped, err := t.wrappedTool(resourceMgr)
+	if err != nil {
+		return false, err
+	}
+	return wrapped.RequiresClientAuthorization(resourceMgr)
+}
+
+func (t Tool) ToConfig() tools.ToolConfig {
+	return t.Config
+}
+
+func (t Tool) GetAuthTokenHeaderName(resourceMgr tools.SourceProvider) (string, error) {
+	wrapped, err := t.wrappedTool(resourceMgr)
+	if err != nil {
+		return "", err
+	}
+	return wrapped.GetAuthTokenHeaderName(resourceMgr)
+}
+
+func (t Tool) GetParameters() parameters.Parameters {
+	return t.Parameters
+}
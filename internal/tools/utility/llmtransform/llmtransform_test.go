@@ -0,0 +1,285 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llmtransform_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/embeddingmodels"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	mcputil "github.com/googleapis/genai-toolbox/internal/server/mcp/util"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/utility/llmtransform"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"github.com/googleapis/genai-toolbox/internal/util/parameters"
+)
+
+func TestParseFromYamlLlmTransform(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	temperature := 0.2
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			kind: tools
+			name: summarize_orders
+			type: llm-transform
+			description: Summarizes the orders.
+			tool: list_orders
+			prompt: "Summarize these orders: {{.result}}"
+			`,
+			want: server.ToolConfigs{
+				"summarize_orders": llmtransform.Config{
+					Name:         "summarize_orders",
+					Type:         "llm-transform",
+					Description:  "Summarizes the orders.",
+					Tool:         "list_orders",
+					Prompt:       "Summarize these orders: {{.result}}",
+					AuthRequired: []string{},
+				},
+			},
+		},
+		{
+			desc: "advanced example",
+			in: `
+			kind: tools
+			name: classify_ticket
+			type: llm-transform
+			description: Classifies a support ticket.
+			tool: get_ticket
+			prompt: "Classify ticket {{.id}}: {{.result}}"
+			systemPrompt: Answer with a single word.
+			maxTokens: 16
+			temperature: 0.2
+			modelHints:
+				- claude
+			parameters:
+				- name: id
+				  type: integer
+				  description: The ID of the ticket.
+			authRequired:
+				- my-google-auth-service
+			`,
+			want: server.ToolConfigs{
+				"classify_ticket": llmtransform.Config{
+					Name:         "classify_ticket",
+					Type:         "llm-transform",
+					Description:  "Classifies a support ticket.",
+					Tool:         "get_ticket",
+					Prompt:       "Classify ticket {{.id}}: {{.result}}",
+					SystemPrompt: "Answer with a single word.",
+					MaxTokens:    16,
+					Temperature:  &temperature,
+					ModelHints:   []string{"claude"},
+					Parameters: parameters.Parameters{
+						parameters.NewIntParameter("id", "The ID of the ticket."),
+					},
+					AuthRequired: []string{"my-google-auth-service"},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, _, _, got, _, _, err := server.UnmarshalResourceConfig(ctx, testutils.FormatYaml(tc.in))
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestFailInitialize(t *testing.T) {
+	tcs := []struct {
+		desc string
+		cfg  llmtransform.Config
+		err  string
+	}{
+		{
+			desc: "wraps itself",
+			cfg:  llmtransform.Config{Name: "summarize", Tool: "summarize", Prompt: "{{.result}}"},
+			err:  "tool \"summarize\" cannot transform its own result",
+		},
+		{
+			desc: "reserved parameter",
+			cfg: llmtransform.Config{
+				Name:       "summarize",
+				Tool:       "list_orders",
+				Prompt:     "{{.result}}",
+				Parameters: parameters.Parameters{parameters.NewStringParameter("result", "")},
+			},
+			err: "parameter name \"result\" is reserved for the result of the wrapped tool",
+		},
+		{
+			desc: "invalid prompt",
+			cfg:  llmtransform.Config{Name: "summarize", Tool: "list_orders", Prompt: "{{.result"},
+			err:  "unable to parse prompt",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := tc.cfg.Initialize(nil)
+			if err == nil || !strings.HasPrefix(err.Error(), tc.err) {
+				t.Fatalf("unexpected error: got %v, want %q", err, tc.err)
+			}
+		})
+	}
+}
+
+// provider stands in for the resource manager.
+type provider map[string]tools.Tool
+
+func (p provider) GetSource(string) (sources.Source, bool) {
+	return nil, false
+}
+
+func (p provider) GetTool(name string) (tools.Tool, bool) {
+	tool, ok := p[name]
+	return tool, ok
+}
+
+func (p provider) GetEmbeddingModelMap() map[string]embeddingmodels.EmbeddingModel {
+	return nil
+}
+
+// authTool is a tool that requires one of the auth services.
+type authTool struct {
+	server.MockTool
+	authRequired []string
+}
+
+func (t authTool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.authRequired, verifiedAuthServices)
+}
+
+func TestInvoke(t *testing.T) {
+	cfg := llmtransform.Config{
+		Name:         "summarize_orders",
+		Type:         "llm-transform",
+		Description:  "Summarizes the orders.",
+		Tool:         "list_orders",
+		Prompt:       "Summarize the orders of {{.customer}}: {{.result}}",
+		SystemPrompt: "Be brief.",
+		ModelHints:   []string{"claude"},
+		Parameters: parameters.Parameters{
+			parameters.NewStringParameter("customer", "The customer."),
+		},
+	}
+	tool, err := cfg.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	resourceMgr := provider{"list_orders": server.MockTool{
+		Name:   "list_orders",
+		Params: []parameters.Parameter{parameters.NewStringParameter("customer", "The customer.")},
+	}}
+	params := parameters.ParamValues{{Name: "customer", Value: "alice"}}
+
+	var gotMethod string
+	var gotParams mcputil.CreateMessageRequestParams
+	requester := func(_ context.Context, method string, params any) (json.RawMessage, error) {
+		gotMethod = method
+		gotParams = params.(mcputil.CreateMessageRequestParams)
+		return json.RawMessage(`{"role":"assistant","content":{"type":"text","text":"Alice ordered a book."},"model":"claude"}`), nil
+	}
+	ctx := mcputil.WithClientCapabilities(context.Background(), mcputil.ClientCapabilities{Sampling: &struct{}{}})
+	ctx = util.WithClientRequester(ctx, requester)
+	ctx = mcputil.WithToolset(ctx, tools.Toolset{})
+
+	got, toolErr := tool.Invoke(ctx, resourceMgr, params, "")
+	if toolErr != nil {
+		t.Fatalf("unexpected error: %s", toolErr)
+	}
+	if got != "Alice ordered a book." {
+		t.Fatalf("unexpected result: %v", got)
+	}
+	if gotMethod != mcputil.SAMPLING_CREATE_MESSAGE {
+		t.Fatalf("unexpected method: %s", gotMethod)
+	}
+	wantParams := mcputil.CreateMessageRequestParams{
+		Messages: []mcputil.SamplingMessage{{
+			Role:    mcputil.ROLE_USER,
+			Content: mcputil.SamplingContent{Type: "text", Text: `Summarize the orders of alice: ["list_orders"]`},
+		}},
+		ModelPreferences: &mcputil.ModelPreferences{Hints: []mcputil.ModelHint{{Name: "claude"}}},
+		SystemPrompt:     "Be brief.",
+		MaxTokens:        1024,
+	}
+	if diff := cmp.Diff(wantParams, gotParams); diff != "" {
+		t.Fatalf("unexpected sampling request: diff %v", diff)
+	}
+
+	// the client does not support sampling
+	noSamplingCtx := mcputil.WithToolset(util.WithClientRequester(context.Background(), requester), tools.Toolset{})
+	_, toolErr = tool.Invoke(noSamplingCtx, resourceMgr, params, "")
+	want := "unable to transform the result of tool \"list_orders\""
+	if toolErr == nil || !strings.HasPrefix(toolErr.Error(), want) || toolErr.Category() != util.CategoryAgent {
+		t.Fatalf("unexpected error: got %v, want %q", toolErr, want)
+	}
+
+	// the wrapped tool requires an auth service that was not verified for
+	// this invocation
+	protected := provider{"list_orders": authTool{MockTool: resourceMgr["list_orders"].(server.MockTool), authRequired: []string{"my-auth-service"}}}
+	_, toolErr = tool.Invoke(ctx, protected, params, "")
+	if toolErr == nil || toolErr.Category() != util.CategoryServer {
+		t.Fatalf("unexpected error: %v", toolErr)
+	}
+	authCtx := util.WithAuthClaims(ctx, map[string]map[string]any{"my-auth-service": {"sub": "alice"}})
+	if _, toolErr := tool.Invoke(authCtx, protected, params, ""); toolErr != nil {
+		t.Fatalf("unexpected error: %s", toolErr)
+	}
+
+	// the authorization policies of the toolset apply to the wrapped tool
+	restricted := tools.Toolset{ToolAuthorizations: map[string][]*tools.AuthorizationPolicy{
+		"list_orders": {{Allow: []tools.AuthorizationRule{{AuthServices: []string{"my-auth-service"}}}}},
+	}}
+	_, toolErr = tool.Invoke(mcputil.WithToolset(ctx, restricted), resourceMgr, params, "")
+	want = "unauthorized Tool call"
+	if toolErr == nil || !strings.HasPrefix(toolErr.Error(), want) {
+		t.Fatalf("unexpected error: got %v, want %q", toolErr, want)
+	}
+	if _, toolErr := tool.Invoke(mcputil.WithToolset(authCtx, restricted), resourceMgr, params, ""); toolErr != nil {
+		t.Fatalf("unexpected error: %s", toolErr)
+	}
+
+	// the tool is not invoked through a toolset
+	noToolsetCtx := mcputil.WithClientCapabilities(util.WithClientRequester(context.Background(), requester), mcputil.ClientCapabilities{Sampling: &struct{}{}})
+	_, toolErr = tool.Invoke(noToolsetCtx, resourceMgr, params, "")
+	if toolErr == nil || toolErr.Category() != util.CategoryServer {
+		t.Fatalf("unexpected error: %v", toolErr)
+	}
+
+	// the wrapped tool does not exist
+	_, toolErr = tool.Invoke(ctx, provider{}, params, "")
+	if toolErr == nil || toolErr.Category() != util.CategoryServer {
+		t.Fatalf("unexpected error: %v", toolErr)
+	}
+}
@@ -49,6 +49,8 @@ type Config struct {
 	Description  string   `yaml:"description" validate:"required"`
 	Timeout      string   `yaml:"timeout" validate:"required"`
 	AuthRequired []string `yaml:"authRequired"`
+
+	tools.CommonConfig `yaml:",inline"`
 }
 
 var _ tools.ToolConfig = Config{}
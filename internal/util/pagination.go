@@ -0,0 +1,85 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const cursorPrefix = "offset:"
+
+const pageSizeKey contextKey = "pageSize"
+
+// WithPageSize adds the page size used by list operations to the context
+func WithPageSize(ctx context.Context, pageSize int) context.Context {
+	return context.WithValue(ctx, pageSizeKey, pageSize)
+}
+
+// PageSizeFromContext retrieves the page size from the context. It returns 0,
+// meaning that results are not paginated, if no page size was set.
+func PageSizeFromContext(ctx context.Context) int {
+	if pageSize, ok := ctx.Value(pageSizeKey).(int); ok {
+		return pageSize
+	}
+	return 0
+}
+
+// Paginate returns the page of items that starts at cursor, along with the
+// cursor of the next page. The cursor is an opaque token returned by a
+// previous call; an empty cursor starts at the first item. nextCursor is empty
+// once the last page is returned. A pageSize of 0 or less returns every item
+// after the cursor.
+func Paginate[T any](items []T, cursor string, pageSize int) ([]T, string, error) {
+	offset := 0
+	if cursor != "" {
+		var err error
+		offset, err = decodeCursor(cursor)
+		if err != nil || offset > len(items) {
+			return nil, "", fmt.Errorf("invalid cursor: %q", cursor)
+		}
+	}
+	if pageSize <= 0 || offset+pageSize >= len(items) {
+		return items[offset:], "", nil
+	}
+	end := offset + pageSize
+	return items[offset:end], encodeCursor(end), nil
+}
+
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(offset)))
+}
+
+func decodeCursor(cursor string) (int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+	s, ok := strings.CutPrefix(string(b), cursorPrefix)
+	if !ok {
+		return 0, fmt.Errorf("missing cursor prefix")
+	}
+	offset, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err
+	}
+	if offset < 0 {
+		return 0, fmt.Errorf("negative offset")
+	}
+	return offset, nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util_test
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+func TestPaginate(t *testing.T) {
+	items := []string{"a", "b", "c", "d", "e"}
+
+	tcs := []struct {
+		name     string
+		pageSize int
+		want     [][]string
+	}{
+		{name: "no pagination", pageSize: 0, want: [][]string{{"a", "b", "c", "d", "e"}}},
+		{name: "uneven pages", pageSize: 2, want: [][]string{{"a", "b"}, {"c", "d"}, {"e"}}},
+		{name: "even pages", pageSize: 5, want: [][]string{{"a", "b", "c", "d", "e"}}},
+		{name: "page larger than items", pageSize: 10, want: [][]string{{"a", "b", "c", "d", "e"}}},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			var got [][]string
+			cursor := ""
+			for {
+				page, next, err := util.Paginate(items, cursor, tc.pageSize)
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				got = append(got, page)
+				if next == "" {
+					break
+				}
+				cursor = next
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect pages (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPaginateInvalidCursor(t *testing.T) {
+	items := []string{"a", "b"}
+	for _, cursor := range []string{
+		"not a cursor",
+		base64.RawURLEncoding.EncodeToString([]byte("1")),
+		base64.RawURLEncoding.EncodeToString([]byte("offset:-1")),
+		base64.RawURLEncoding.EncodeToString([]byte("offset:3")),
+	} {
+		if _, _, err := util.Paginate(items, cursor, 1); err == nil {
+			t.Errorf("expected error for cursor %q", cursor)
+		}
+	}
+}
+
+func TestPageSizeFromContext(t *testing.T) {
+	ctx := context.Background()
+	if got := util.PageSizeFromContext(ctx); got != 0 {
+		t.Fatalf("unexpected default page size: got %d, want 0", got)
+	}
+	if got := util.PageSizeFromContext(util.WithPageSize(ctx, 3)); got != 3 {
+		t.Fatalf("unexpected page size: got %d, want 3", got)
+	}
+}
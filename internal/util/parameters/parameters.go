@@ -325,6 +325,7 @@ type Parameter interface {
 	GetAuthServices() []ParamAuthService
 	GetEmbeddedBy() string
 	GetValueFromParam() string
+	GetCompletion() *Completion
 	Parse(any) (any, error)
 	Manifest() ParameterManifest
 	McpManifest() (ParameterMcpManifest, []string)
@@ -548,6 +549,19 @@ type CommonParameter struct {
 	AuthSources    []ParamAuthService `yaml:"authSources"` // Deprecated: Kept for compatibility.
 	EmbeddedBy     string             `yaml:"embeddedBy"`
 	ValueFromParam string             `yaml:"valueFromParam"`
+	Completion     *Completion        `yaml:"completion"`
+}
+
+// Completion configures the values suggested for a parameter by MCP
+// completion/complete. Suggestions come from a static list of values, from the
+// result of a tool, or both.
+type Completion struct {
+	// Values is a static list of suggested values.
+	Values []any `yaml:"values"`
+	// Tool is the name of a tool whose result supplies suggested values. The
+	// tool is invoked with the values of the other arguments the client has
+	// already filled in.
+	Tool string `yaml:"tool"`
 }
 
 // GetName returns the name specified for the Parameter.
@@ -587,6 +601,18 @@ func (p *CommonParameter) IsAllowedValues(v any) bool {
 	return false
 }
 
+// GetCompletion returns how values are suggested for the Parameter. Parameters
+// without a completion suggest their allowed values, if any.
+func (p *CommonParameter) GetCompletion() *Completion {
+	if p.Completion != nil {
+		return p.Completion
+	}
+	if len(p.AllowedValues) > 0 {
+		return &Completion{Values: p.AllowedValues}
+	}
+	return nil
+}
+
 // GetExcludedValues returns the excluded values for the Parameter.
 func (p *CommonParameter) GetExcludedValues() []any {
 	return p.ExcludedValues
@@ -340,6 +340,33 @@ func TestParametersMarshal(t *testing.T) {
 				parameters.NewMapParameterWithDefault("my_map", map[string]any{"key1": "val1"}, "this param is a map of strings", "string"),
 			},
 		},
+		{
+			name: "string with completion",
+			in: []map[string]any{
+				{
+					"name":        "my_string",
+					"type":        "string",
+					"description": "this param is a string",
+					"completion": map[string]any{
+						"values": []any{"foo", "bar"},
+						"tool":   "list_strings",
+					},
+				},
+			},
+			want: parameters.Parameters{
+				&parameters.StringParameter{
+					CommonParameter: parameters.CommonParameter{
+						Name: "my_string",
+						Type: "string",
+						Desc: "this param is a string",
+						Completion: &parameters.Completion{
+							Values: []any{"foo", "bar"},
+							Tool:   "list_strings",
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "generic map (no valueType)",
 			in: []map[string]any{
@@ -2366,3 +2393,42 @@ func TestCheckParamRequired(t *testing.T) {
 		})
 	}
 }
+
+func TestGetCompletion(t *testing.T) {
+	tcs := []struct {
+		name  string
+		param parameters.Parameter
+		want  *parameters.Completion
+	}{
+		{
+			name:  "no completion",
+			param: parameters.NewStringParameter("my_string", "this param is a string"),
+			want:  nil,
+		},
+		{
+			name:  "allowed values",
+			param: parameters.NewStringParameterWithAllowedValues("my_string", "this param is a string", []any{"foo"}),
+			want:  &parameters.Completion{Values: []any{"foo"}},
+		},
+		{
+			name: "completion takes precedence over allowed values",
+			param: &parameters.StringParameter{
+				CommonParameter: parameters.CommonParameter{
+					Name:          "my_string",
+					Type:          "string",
+					Desc:          "this param is a string",
+					AllowedValues: []any{"foo"},
+					Completion:    &parameters.Completion{Tool: "list_strings"},
+				},
+			},
+			want: &parameters.Completion{Tool: "list_strings"},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, tc.param.GetCompletion()); diff != "" {
+				t.Fatalf("incorrect completion: diff %v", diff)
+			}
+		})
+	}
+}
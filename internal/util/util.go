@@ -16,6 +16,7 @@ package util
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -176,6 +177,40 @@ func LoggerFromContext(ctx context.Context) (log.Logger, error) {
 	return nil, fmt.Errorf("unable to retrieve logger")
 }
 
+// tlsConnectionStateKey is the key used to store the TLS connection state of
+// a request within context
+const tlsConnectionStateKey contextKey = "tlsConnectionState"
+
+// WithTLSConnectionState adds the TLS connection state of a request into the
+// context as a value
+func WithTLSConnectionState(ctx context.Context, state *tls.ConnectionState) context.Context {
+	return context.WithValue(ctx, tlsConnectionStateKey, state)
+}
+
+// TLSConnectionStateFromContext retrieves the TLS connection state, or nil if
+// the request was not received over TLS
+func TLSConnectionStateFromContext(ctx context.Context) *tls.ConnectionState {
+	state, _ := ctx.Value(tlsConnectionStateKey).(*tls.ConnectionState)
+	return state
+}
+
+// authClaimsKey is the key used to store the verified claims of the caller
+// within context
+const authClaimsKey contextKey = "authClaims"
+
+// WithAuthClaims adds the claims verified by each auth service, by auth
+// service name, into the context as a value
+func WithAuthClaims(ctx context.Context, claimsFromAuth map[string]map[string]any) context.Context {
+	return context.WithValue(ctx, authClaimsKey, claimsFromAuth)
+}
+
+// AuthClaimsFromContext retrieves the verified claims of the caller, or nil if
+// none were added to the context
+func AuthClaimsFromContext(ctx context.Context) map[string]map[string]any {
+	claims, _ := ctx.Value(authClaimsKey).(map[string]map[string]any)
+	return claims
+}
+
 const instrumentationKey contextKey = "instrumentation"
 
 // WithInstrumentation adds an instrumentation into the context as a value
@@ -214,3 +249,64 @@ func GenAIMetricAttrsFromContext(ctx context.Context) *GenAIMetricAttrs {
 	}
 	return nil
 }
+
+// MessageSender sends a JSON-RPC message (a notification or a request) from
+// the server to the MCP client over the transport of the current request.
+type MessageSender func(ctx context.Context, message any) error
+
+const messageSenderKey contextKey = "messageSender"
+
+// WithMessageSender adds a MessageSender to the context
+func WithMessageSender(ctx context.Context, sender MessageSender) context.Context {
+	return context.WithValue(ctx, messageSenderKey, sender)
+}
+
+// MessageSenderFromContext retrieves the MessageSender or return an error
+func MessageSenderFromContext(ctx context.Context) (MessageSender, error) {
+	if sender, ok := ctx.Value(messageSenderKey).(MessageSender); ok {
+		return sender, nil
+	}
+	return nil, fmt.Errorf("unable to retrieve message sender")
+}
+
+// ClientRequester sends a JSON-RPC request from the server to the MCP client
+// over the transport of the current request, and waits for the result of the
+// client's response.
+type ClientRequester func(ctx context.Context, method string, params any) (json.RawMessage, error)
+
+const clientRequesterKey contextKey = "clientRequester"
+
+// WithClientRequester adds a ClientRequester to the context
+func WithClientRequester(ctx context.Context, requester ClientRequester) context.Context {
+	return context.WithValue(ctx, clientRequesterKey, requester)
+}
+
+// ClientRequesterFromContext retrieves the ClientRequester or return an error
+func ClientRequesterFromContext(ctx context.Context) (ClientRequester, error) {
+	if requester, ok := ctx.Value(clientRequesterKey).(ClientRequester); ok {
+		return requester, nil
+	}
+	return nil, fmt.Errorf("unable to retrieve client requester")
+}
+
+// ProgressReporter reports the progress of the current request to the MCP
+// client that requested it. progress must increase with each call, total is
+// 0 if unknown.
+type ProgressReporter func(ctx context.Context, progress float64, total float64, message string) error
+
+const progressReporterKey contextKey = "progressReporter"
+
+// WithProgressReporter adds a ProgressReporter to the context
+func WithProgressReporter(ctx context.Context, reporter ProgressReporter) context.Context {
+	return context.WithValue(ctx, progressReporterKey, reporter)
+}
+
+// ReportProgress reports progress through the ProgressReporter of the context.
+// It is a no-op if the client did not request progress notifications.
+func ReportProgress(ctx context.Context, progress float64, total float64, message string) error {
+	reporter, ok := ctx.Value(progressReporterKey).(ProgressReporter)
+	if !ok {
+		return nil
+	}
+	return reporter(ctx, progress, total, message)
+}